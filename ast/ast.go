@@ -1,5 +1,3 @@
-// TODO(u): Figure out how and where to store comments.
-
 // Package ast declares the types used to represent abstract syntax trees of Go
 // source code.
 package ast
@@ -35,3 +33,28 @@ type File struct {
 	// Top level declarations.
 	Decls []TopLevelDecl
 }
+
+// Imports returns the import specs of the import declarations of f, in order of
+// occurrence.
+func (f *File) Imports() []ImportSpec {
+	var specs []ImportSpec
+	for _, decl := range f.Imps {
+		specs = append(specs, decl...)
+	}
+	return specs
+}
+
+// ImportPaths returns the unquoted import paths of the import declarations of f,
+// in order of occurrence; including those of named imports and dot-imports.
+// Import paths which are not valid string literals are skipped.
+func (f *File) ImportPaths() []string {
+	var paths []string
+	for _, spec := range f.Imports() {
+		path, err := spec.Path.Unquote()
+		if err != nil {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
@@ -0,0 +1,64 @@
+package ast_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mewlang/go/ast"
+	"github.com/mewlang/go/lexer"
+	"github.com/mewlang/go/parser"
+	"github.com/mewlang/go/token"
+)
+
+func TestFileImports(t *testing.T) {
+	const input = `package p
+
+import "fmt"
+
+import (
+	"io"
+	. "math"
+	str "strings"
+	_ ` + "`net/http/pprof`" + `
+)
+
+import ()
+
+import "unicode/utf8"
+`
+	tokens, err := lexer.Parse(input)
+	if err != nil {
+		t.Fatalf("unexpected error; %v", err)
+	}
+	f, err := parser.ParseFile(tokens)
+	if err != nil {
+		t.Fatalf("unexpected error; %v", err)
+	}
+	path := func(val string, line, col, offset int) token.Token {
+		return token.Token{Kind: token.String, Val: val, Line: line, Col: col, Offset: offset}
+	}
+	name := func(kind token.Kind, val string, line, col, offset int) token.Token {
+		return token.Token{Kind: kind, Val: val, Line: line, Col: col, Offset: offset}
+	}
+	wantSpecs := []ast.ImportSpec{
+		{Path: path(`"fmt"`, 3, 8, 18)},
+		{Path: path(`"io"`, 6, 2, 35)},
+		{Name: name(token.Dot, ".", 7, 2, 41), Path: path(`"math"`, 7, 4, 43)},
+		{Name: name(token.Ident, "str", 8, 2, 51), Path: path(`"strings"`, 8, 6, 55)},
+		{Name: name(token.Ident, "_", 9, 2, 66), Path: path("`net/http/pprof`", 9, 4, 68)},
+		{Path: path(`"unicode/utf8"`, 14, 8, 106)},
+	}
+	if got := f.Imports(); !reflect.DeepEqual(got, wantSpecs) {
+		t.Errorf("import specs mismatch; expected %v, got %v.", wantSpecs, got)
+	}
+	wantPaths := []string{"fmt", "io", "math", "strings", "net/http/pprof", "unicode/utf8"}
+	if got := f.ImportPaths(); !reflect.DeepEqual(got, wantPaths) {
+		t.Errorf("import paths mismatch; expected %v, got %v.", wantPaths, got)
+	}
+
+	// A file without imports.
+	f = &ast.File{}
+	if got := f.ImportPaths(); got != nil {
+		t.Errorf("import paths mismatch; expected nil, got %v.", got)
+	}
+}
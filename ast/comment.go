@@ -0,0 +1,257 @@
+package ast
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mewlang/go/token"
+	"github.com/mewlang/go/types"
+)
+
+// A CommentMap maps the nodes of a source file to the comments associated with
+// them, in order of occurrence. Comments are kept out of the nodes themselves;
+// a printer may use the map to reattach them.
+//
+// As nodes are not generally comparable, they are identified by pointers into
+// the source file; i.e. the file itself (*File), its import declarations
+// (&f.Imps[i]) and import specifiers (&f.Imps[i][j]), its top level
+// declarations (&f.Decls[i]), the specifiers of constant, type and variable
+// declarations (e.g. &decl[j] of decl := f.Decls[i].(ConstDecl)), and the
+// fields of struct types (e.g. &st[j] of a types.Struct st).
+type CommentMap map[interface{}][]token.Token
+
+// NewCommentMap returns a comment map which associates the provided comments of
+// f with the nodes of f. Adjacent comments which are not separated by any token
+// of f form a comment group, and each comment group is associated with a single
+// node; that is
+//
+//    1. the innermost node ending on the line at which the group starts, e.g.
+//
+//          X int // X comment.
+//
+//    2. the innermost node ending on the line immediately preceding the group,
+//       if the group is followed by an empty line or the end of file, e.g.
+//
+//          X int
+//          // X comment.
+//
+//    3. the outermost node following the group, e.g.
+//
+//          // Foo comment.
+//          func Foo() {}
+//
+//    4. the innermost node preceding the group, or the file itself.
+//
+// The positions of keywords, brackets and statements are not recorded; comments
+// within function bodies are thus associated as if the function declaration
+// ended at the last token of its signature.
+func NewCommentMap(f *File, comments []token.Token) CommentMap {
+	nodes := commentNodes(f)
+
+	// Record the positions of the tokens of f, to locate comment groups.
+	tv := new(tokenVisitor)
+	Walk(tv, f)
+	sort.Sort(positions(tv.poss))
+
+	cmap := make(CommentMap)
+	for len(comments) > 0 {
+		// Locate the end of the comment group.
+		n := 1
+		end := endLine(comments[0])
+		for ; n < len(comments); n++ {
+			prev, next := comments[n-1].Pos(), comments[n].Pos()
+			if next.Line > end+1 || tv.between(prev, next) {
+				break
+			}
+			end = endLine(comments[n])
+		}
+		group := comments[:n]
+		comments = comments[n:]
+
+		key := associate(nodes, group[0].Pos(), group[n-1].Pos(), end)
+		if key == nil {
+			key = f
+		}
+		cmap[key] = append(cmap[key], group...)
+	}
+	return cmap
+}
+
+// associate returns the key of the node associated with the comment group
+// spanning from start to end, where last is the position of the last comment of
+// the group; or nil if no node precedes or follows the group.
+func associate(nodes []commentNode, start, last token.Position, end int) interface{} {
+	// Locate the innermost nodes ending on the line at which the group starts
+	// and on the line immediately preceding it, the innermost preceding node
+	// and the outermost following node.
+	var same, above, prev, next *commentNode
+	for i := range nodes {
+		n := &nodes[i]
+		switch {
+		case before(n.start, start):
+			if n.end == start.Line && (same == nil || !before(n.start, same.start)) {
+				same = n
+			}
+			if n.end == start.Line-1 && (above == nil || !before(n.start, above.start)) {
+				above = n
+			}
+			if prev == nil || !before(n.start, prev.start) {
+				prev = n
+			}
+		case before(last, n.start):
+			if next == nil || before(n.start, next.start) {
+				next = n
+			}
+		}
+	}
+	switch {
+	case same != nil:
+		return same.key
+	case above != nil && (next == nil || next.start.Line > end+1):
+		return above.key
+	case next != nil:
+		return next.key
+	case prev != nil:
+		return prev.key
+	}
+	return nil
+}
+
+// A commentNode is a node with which comments may be associated.
+type commentNode struct {
+	// Pointer to the node.
+	key interface{}
+	// Position of the first token of the node.
+	start token.Position
+	// Line number at which the last token of the node ends.
+	end int
+}
+
+// commentNodes returns the nodes of f with which comments may be associated, in
+// depth-first order.
+func commentNodes(f *File) []commentNode {
+	var nodes []commentNode
+	add := func(key interface{}, node interface{}) {
+		tv := new(tokenVisitor)
+		Walk(tv, node)
+		if len(tv.poss) == 0 {
+			// Empty declaration.
+			return
+		}
+		sort.Sort(positions(tv.poss))
+		nodes = append(nodes, commentNode{key: key, start: tv.poss[0], end: tv.end})
+	}
+	// addFields adds the fields of the struct types within the provided node.
+	addFields := func(node interface{}) {
+		sv := new(structVisitor)
+		Walk(sv, node)
+		for _, field := range sv.fields {
+			add(field, *field)
+		}
+	}
+
+	add(f, f.PkgName)
+	for i := range f.Imps {
+		decl := f.Imps[i]
+		add(&f.Imps[i], decl)
+		for j := range decl {
+			add(&decl[j], decl[j])
+		}
+	}
+	for i := range f.Decls {
+		add(&f.Decls[i], f.Decls[i])
+		var specs []ValueSpec
+		switch decl := f.Decls[i].(type) {
+		case ConstDecl:
+			specs = decl
+		case VarDecl:
+			specs = decl
+		case TypeDecl:
+			for j := range decl {
+				add(&decl[j], decl[j])
+			}
+		}
+		for j := range specs {
+			if specs[j].Implicit {
+				// The type and values of the specifier are those of a previous
+				// specifier.
+				add(&specs[j], ValueSpec{Names: specs[j].Names})
+				continue
+			}
+			add(&specs[j], specs[j])
+		}
+		addFields(f.Decls[i])
+	}
+	return nodes
+}
+
+// A tokenVisitor records the positions of the tokens of the visited nodes.
+type tokenVisitor struct {
+	// Token positions.
+	poss []token.Position
+	// Line number at which the last token ends.
+	end int
+}
+
+// Visit records the position of the provided node, if it is a token.
+func (v *tokenVisitor) Visit(node interface{}) Visitor {
+	var tok token.Token
+	switch n := node.(type) {
+	case token.Token:
+		tok = n
+	case BasicLit:
+		tok = token.Token(n)
+	case OperandName:
+		tok = token.Token(n)
+	default:
+		return v
+	}
+	if tok.Line == 0 {
+		return v
+	}
+	v.poss = append(v.poss, tok.Pos())
+	if end := endLine(tok); end > v.end {
+		v.end = end
+	}
+	return v
+}
+
+// between reports whether any of the recorded tokens is located between the
+// positions a and b. The recorded positions must be sorted.
+func (v *tokenVisitor) between(a, b token.Position) bool {
+	i := sort.Search(len(v.poss), func(i int) bool { return before(a, v.poss[i]) })
+	return i < len(v.poss) && before(v.poss[i], b)
+}
+
+// A structVisitor records the fields of the visited struct types.
+type structVisitor struct {
+	// Pointers to the fields.
+	fields []*types.Field
+}
+
+// Visit records the fields of the provided node, if it is a struct type.
+func (v *structVisitor) Visit(node interface{}) Visitor {
+	if st, ok := node.(types.Struct); ok {
+		for i := range st {
+			v.fields = append(v.fields, &st[i])
+		}
+	}
+	return v
+}
+
+// positions implements sort.Interface for token positions.
+type positions []token.Position
+
+func (ps positions) Len() int           { return len(ps) }
+func (ps positions) Less(i, j int) bool { return before(ps[i], ps[j]) }
+func (ps positions) Swap(i, j int)      { ps[i], ps[j] = ps[j], ps[i] }
+
+// before reports whether the position a precedes the position b.
+func before(a, b token.Position) bool {
+	return a.Line < b.Line || a.Line == b.Line && a.Col < b.Col
+}
+
+// endLine returns the line number at which the provided token ends.
+func endLine(tok token.Token) int {
+	return tok.Line + strings.Count(tok.Val, "\n")
+}
@@ -0,0 +1,81 @@
+package ast_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mewlang/go/ast"
+	"github.com/mewlang/go/lexer"
+	"github.com/mewlang/go/parser"
+	"github.com/mewlang/go/token"
+	"github.com/mewlang/go/types"
+)
+
+func TestNewCommentMap(t *testing.T) {
+	const input = `// Package p is documented.
+package p
+
+// Foo does foo.
+// More docs.
+func Foo() {
+}
+
+type T struct {
+	// X is documented.
+	X int // X line comment.
+	Y int /* Y */
+	// Y trailing comment.
+
+	Z int
+}
+
+const (
+	A = iota // A
+	B        // B
+)
+
+// Dangling comment.
+`
+	tokens, err := lexer.Parse(input)
+	if err != nil {
+		t.Fatalf("lexer.Parse failed; %v", err)
+	}
+	var comments []token.Token
+	for _, tok := range tokens {
+		if tok.Kind == token.Comment {
+			comments = append(comments, tok)
+		}
+	}
+	f, err := parser.ParseFile(tokens)
+	if err != nil {
+		t.Fatalf("parser.ParseFile failed; %v", err)
+	}
+	st := f.Decls[1].(ast.TypeDecl)[0].Type.(types.Struct)
+	consts := f.Decls[2].(ast.ConstDecl)
+
+	golden := []struct {
+		node interface{}
+		want []string
+	}{
+		{node: f, want: []string{"// Package p is documented."}},
+		{node: &f.Decls[0], want: []string{"// Foo does foo.", "// More docs."}},
+		{node: &st[0], want: []string{"// X is documented.", "// X line comment."}},
+		{node: &st[1], want: []string{"/* Y */", "// Y trailing comment."}},
+		{node: &consts[0], want: []string{"// A"}},
+		{node: &consts[1], want: []string{"// B", "// Dangling comment."}},
+	}
+
+	cmap := ast.NewCommentMap(f, comments)
+	if len(cmap) != len(golden) {
+		t.Errorf("number of commented nodes mismatch; expected %d, got %d.", len(golden), len(cmap))
+	}
+	for i, g := range golden {
+		var got []string
+		for _, comment := range cmap[g.node] {
+			got = append(got, comment.Val)
+		}
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: comments mismatch; expected %q, got %q.", i, g.want, got)
+		}
+	}
+}
@@ -0,0 +1,483 @@
+package ast
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/mewlang/go/token"
+)
+
+// A ConstKind specifies the kind of an untyped constant.
+//
+// ref: http://golang.org/ref/spec#Constants
+type ConstKind uint8
+
+// Constant kinds. The numeric kinds are listed in order of rank; an operation
+// on numeric constants of different kinds yields the kind of higher rank.
+const (
+	// IntConst is the kind of integer constants.
+	IntConst ConstKind = iota + 1
+	// RuneConst is the kind of rune constants.
+	RuneConst
+	// FloatConst is the kind of floating-point constants.
+	FloatConst
+	// BoolConst is the kind of boolean constants.
+	BoolConst
+	// StringConst is the kind of string constants.
+	StringConst
+)
+
+// String returns the string representation of the constant kind.
+func (kind ConstKind) String() string {
+	switch kind {
+	case IntConst:
+		return "integer"
+	case RuneConst:
+		return "rune"
+	case FloatConst:
+		return "floating-point"
+	case BoolConst:
+		return "boolean"
+	case StringConst:
+		return "string"
+	}
+	return fmt.Sprintf("ConstKind(%d)", uint8(kind))
+}
+
+// isNumeric reports whether kind is an integer, rune or floating-point kind.
+func (kind ConstKind) isNumeric() bool {
+	return IntConst <= kind && kind <= FloatConst
+}
+
+// A Value is the value of an untyped constant expression.
+type Value struct {
+	// Constant kind.
+	Kind ConstKind
+	// Constant value; holds a bool, a string, a *big.Int for integer and rune
+	// constants, or a *big.Float for floating-point constants.
+	Val interface{}
+}
+
+// String returns the string representation of the constant value; e.g. 42,
+// 1.5, true or "foo".
+func (v Value) String() string {
+	switch val := v.Val.(type) {
+	case *big.Int:
+		return val.String()
+	case *big.Float:
+		return val.Text('g', 20)
+	case string:
+		return strconv.Quote(val)
+	}
+	return fmt.Sprint(v.Val)
+}
+
+// Int64 returns the value of an integer constant, or of a numeric constant
+// which is representable as an integer, and a boolean indicating success; e.g.
+// to evaluate the length of an array type.
+func (v Value) Int64() (int64, bool) {
+	x, ok := toInt(v)
+	if !ok || !x.IsInt64() {
+		return 0, false
+	}
+	return x.Int64(), true
+}
+
+const (
+	// maxBits is the maximum number of bits of integer constants; the gc
+	// compiler represents untyped integer constants using 512 bits.
+	maxBits = 512
+	// floatPrec is the precision in bits of floating-point constants.
+	floatPrec = 512
+)
+
+// EvalConst evaluates the provided constant expression, folding operations on
+// integer, floating-point, rune, string and boolean literals; the predeclared
+// constants true and false; and calls to the built-in function len with a
+// constant string argument.
+//
+// Untyped constants are represented with arbitrary precision, but an error is
+// reported if an integer constant exceeds 512 bits, as does the gc compiler.
+// Division by zero is an error, as are complex constants which are not
+// supported. The identifier iota is not defined; use EvalConstIota to
+// evaluate the expressions of constant declarations.
+//
+// ref: http://golang.org/ref/spec#Constant_expressions
+func EvalConst(e Expr) (Value, error) {
+	return evalConst(e, -1, nil)
+}
+
+// EvalConstIota is like EvalConst, but evaluates the identifier iota to the
+// provided value; e.g. the Iota of a ConstSpec.
+//
+// ref: http://golang.org/ref/spec#Iota
+func EvalConstIota(e Expr, iota int) (Value, error) {
+	return evalConst(e, iota, nil)
+}
+
+// EvalConstDecl evaluates the value expressions of the provided constant
+// declaration, and returns the value of each declared constant in order of
+// declaration. The identifier iota evaluates to the index of each specifier
+// within the declaration, and is thus shared by the constants of a specifier;
+// a specifier without value expressions implicitly repeats those of the
+// previous specifier.
+//
+// Value expressions may refer to the constants declared by preceding
+// specifiers. E.g. the following constants evaluate to 1, 2 and 40, 80.
+//
+//    const (
+//       A = 1 << iota
+//       B
+//       C, D = iota * 20, B * 40
+//    )
+//
+// ref: http://golang.org/ref/spec#Constant_declarations
+func EvalConstDecl(decl ConstDecl) ([]Value, error) {
+	var vals []Value
+	// Constants declared by preceding specifiers.
+	consts := make(map[string]Value)
+	// Value expressions of the previous specifier.
+	var prev []Expr
+	for i, spec := range decl {
+		exprs := spec.Vals
+		if len(exprs) == 0 {
+			exprs = prev
+		}
+		switch {
+		case len(exprs) == 0 && len(spec.Names) > 0:
+			return nil, fmt.Errorf("ast.EvalConstDecl: %v: missing init expr for const declaration", specsPos(decl[i:]))
+		case len(exprs) != len(spec.Names):
+			return nil, fmt.Errorf("ast.EvalConstDecl: %v: assignment mismatch: %d constants but %d values", specsPos(decl[i:]), len(spec.Names), len(exprs))
+		}
+		start := len(vals)
+		for _, e := range exprs {
+			v, err := evalConst(e, spec.Iota, consts)
+			if err != nil {
+				return nil, err
+			}
+			vals = append(vals, v)
+		}
+		for j, name := range spec.Names {
+			if name.Val != "_" {
+				consts[name.Val] = vals[start+j]
+			}
+		}
+		prev = exprs
+	}
+	return vals, nil
+}
+
+// evalConst evaluates the provided constant expression. The identifier iota is
+// not defined if iota is negative. Other identifiers are looked up in consts,
+// which may be nil.
+func evalConst(e Expr, iota int, consts map[string]Value) (Value, error) {
+	switch e := e.(type) {
+	case BasicLit:
+		return evalLit(token.Token(e))
+	case OperandName:
+		if v, ok := consts[e.Val]; ok {
+			return v, nil
+		}
+		switch {
+		case e.Val == "true" || e.Val == "false":
+			return Value{Kind: BoolConst, Val: e.Val == "true"}, nil
+		case e.Val == "iota" && iota >= 0:
+			return Value{Kind: IntConst, Val: big.NewInt(int64(iota))}, nil
+		case e.Val == "iota":
+			return Value{}, constErrorf(e.Pos(), "cannot use iota outside constant declaration")
+		}
+		return Value{}, constErrorf(e.Pos(), "%s is not a constant", e.Val)
+	case ParenExpr:
+		return evalConst(e.Expr, iota, consts)
+	case UnaryExpr:
+		x, err := evalConst(e.Expr, iota, consts)
+		if err != nil {
+			return Value{}, err
+		}
+		return evalUnary(e.Op, x)
+	case BinaryExpr:
+		x, err := evalConst(e.Left, iota, consts)
+		if err != nil {
+			return Value{}, err
+		}
+		y, err := evalConst(e.Right, iota, consts)
+		if err != nil {
+			return Value{}, err
+		}
+		return evalBinary(e.Op, x, y)
+	case CallExpr:
+		if name, ok := e.Func.(OperandName); ok && name.Val == "len" && len(e.Args) == 1 && !e.HasEllipsis {
+			if arg, ok := e.Args[0].(Expr); ok {
+				x, err := evalConst(arg, iota, consts)
+				if err != nil {
+					return Value{}, err
+				}
+				if x.Kind != StringConst {
+					return Value{}, constErrorf(arg.Pos(), "invalid argument %v (%v constant) for len", x, x.Kind)
+				}
+				return Value{Kind: IntConst, Val: big.NewInt(int64(len(x.Val.(string))))}, nil
+			}
+		}
+	}
+	return Value{}, constErrorf(e.Pos(), "%T is not a constant expression", e)
+}
+
+// evalLit evaluates the provided basic literal.
+func evalLit(tok token.Token) (Value, error) {
+	switch tok.Kind {
+	case token.Int:
+		x, ok := new(big.Int).SetString(tok.Val, 0)
+		if !ok {
+			return Value{}, constErrorf(tok.Pos(), "invalid integer literal %s", tok.Val)
+		}
+		return checkInt(tok, Value{Kind: IntConst, Val: x})
+	case token.Float:
+		x, ok := new(big.Float).SetPrec(floatPrec).SetString(tok.Val)
+		if !ok {
+			return Value{}, constErrorf(tok.Pos(), "invalid floating-point literal %s", tok.Val)
+		}
+		return Value{Kind: FloatConst, Val: x}, nil
+	case token.Rune:
+		s, err := tok.Unquote()
+		if err != nil {
+			return Value{}, constErrorf(tok.Pos(), "%v", err)
+		}
+		r, _ := utf8.DecodeRuneInString(s)
+		return Value{Kind: RuneConst, Val: big.NewInt(int64(r))}, nil
+	case token.String:
+		s, err := tok.Unquote()
+		if err != nil {
+			return Value{}, constErrorf(tok.Pos(), "%v", err)
+		}
+		return Value{Kind: StringConst, Val: s}, nil
+	}
+	return Value{}, constErrorf(tok.Pos(), "unsupported constant %s", tok.Val)
+}
+
+// evalUnary evaluates the unary operation op x.
+func evalUnary(op token.Token, x Value) (Value, error) {
+	switch {
+	case op.Kind == token.Not && x.Kind == BoolConst:
+		return Value{Kind: BoolConst, Val: !x.Val.(bool)}, nil
+	case op.Kind == token.Add && x.Kind.isNumeric():
+		return x, nil
+	case op.Kind == token.Sub && x.Kind == FloatConst:
+		return Value{Kind: FloatConst, Val: new(big.Float).SetPrec(floatPrec).Neg(x.Val.(*big.Float))}, nil
+	case op.Kind == token.Sub && x.Kind.isNumeric():
+		return checkInt(op, Value{Kind: x.Kind, Val: new(big.Int).Neg(x.Val.(*big.Int))})
+	case op.Kind == token.Xor && (x.Kind == IntConst || x.Kind == RuneConst):
+		// The bitwise complement of an untyped constant is -x - 1.
+		return checkInt(op, Value{Kind: x.Kind, Val: new(big.Int).Not(x.Val.(*big.Int))})
+	}
+	return Value{}, constErrorf(op.Pos(), "invalid operation: operator %s not defined on %v (%v constant)", op.Val, x, x.Kind)
+}
+
+// evalBinary evaluates the binary operation x op y.
+func evalBinary(op token.Token, x, y Value) (Value, error) {
+	switch op.Kind {
+	case token.Land, token.Lor:
+		if x.Kind != BoolConst || y.Kind != BoolConst {
+			break
+		}
+		if op.Kind == token.Land {
+			return Value{Kind: BoolConst, Val: x.Val.(bool) && y.Val.(bool)}, nil
+		}
+		return Value{Kind: BoolConst, Val: x.Val.(bool) || y.Val.(bool)}, nil
+	case token.Shl, token.Shr:
+		return evalShift(op, x, y)
+	}
+	if op.Kind.IsComparison() {
+		return evalComparison(op, x, y)
+	}
+
+	switch {
+	case x.Kind == StringConst && y.Kind == StringConst:
+		if op.Kind == token.Add {
+			return Value{Kind: StringConst, Val: x.Val.(string) + y.Val.(string)}, nil
+		}
+	case x.Kind.isNumeric() && y.Kind.isNumeric():
+		return evalArithmetic(op, x, y)
+	case x.Kind != y.Kind:
+		return Value{}, constErrorf(op.Pos(), "invalid operation: mismatched constant kinds %v and %v", x.Kind, y.Kind)
+	}
+	return Value{}, constErrorf(op.Pos(), "invalid operation: operator %s not defined on %v (%v constant)", op.Val, x, x.Kind)
+}
+
+// evalArithmetic evaluates the arithmetic operation x op y on numeric
+// constants.
+func evalArithmetic(op token.Token, x, y Value) (Value, error) {
+	kind := x.Kind
+	if y.Kind > kind {
+		kind = y.Kind
+	}
+	if kind == FloatConst {
+		a, b := toFloat(x), toFloat(y)
+		z := new(big.Float).SetPrec(floatPrec)
+		switch op.Kind {
+		case token.Add:
+			z.Add(a, b)
+		case token.Sub:
+			z.Sub(a, b)
+		case token.Mul:
+			z.Mul(a, b)
+		case token.Div:
+			if b.Sign() == 0 {
+				return Value{}, constErrorf(op.Pos(), "division by zero")
+			}
+			z.Quo(a, b)
+		default:
+			return Value{}, constErrorf(op.Pos(), "invalid operation: operator %s not defined on %v (%v constant)", op.Val, x, kind)
+		}
+		return Value{Kind: FloatConst, Val: z}, nil
+	}
+
+	a, b := x.Val.(*big.Int), y.Val.(*big.Int)
+	z := new(big.Int)
+	switch op.Kind {
+	case token.Add:
+		z.Add(a, b)
+	case token.Sub:
+		z.Sub(a, b)
+	case token.Mul:
+		z.Mul(a, b)
+	case token.Div, token.Mod:
+		if b.Sign() == 0 {
+			return Value{}, constErrorf(op.Pos(), "division by zero")
+		}
+		// Integer division truncates towards zero.
+		if op.Kind == token.Div {
+			z.Quo(a, b)
+		} else {
+			z.Rem(a, b)
+		}
+	case token.And:
+		z.And(a, b)
+	case token.Or:
+		z.Or(a, b)
+	case token.Xor:
+		z.Xor(a, b)
+	case token.Clear:
+		z.AndNot(a, b)
+	default:
+		return Value{}, constErrorf(op.Pos(), "invalid operation: operator %s not defined on %v (%v constant)", op.Val, x, kind)
+	}
+	return checkInt(op, Value{Kind: kind, Val: z})
+}
+
+// evalShift evaluates the shift operation x op y. The left operand must be
+// representable as an integer, and the shift count as a non-negative integer.
+func evalShift(op token.Token, x, y Value) (Value, error) {
+	a, ok := toInt(x)
+	if !ok {
+		return Value{}, constErrorf(op.Pos(), "invalid operation: shifted operand %v must be integer", x)
+	}
+	kind := x.Kind
+	if kind == FloatConst {
+		kind = IntConst
+	}
+	n, ok := toInt(y)
+	if !ok || n.Sign() < 0 {
+		return Value{}, constErrorf(op.Pos(), "invalid operation: invalid shift count %v", y)
+	}
+	if op.Kind == token.Shr {
+		if !n.IsUint64() {
+			// Shift out every bit.
+			n = big.NewInt(maxBits)
+		}
+		return Value{Kind: kind, Val: new(big.Int).Rsh(a, uint(n.Uint64()))}, nil
+	}
+	if a.Sign() == 0 {
+		return Value{Kind: kind, Val: a}, nil
+	}
+	if !n.IsUint64() || n.Uint64() > maxBits {
+		return Value{}, constErrorf(op.Pos(), "constant overflow")
+	}
+	return checkInt(op, Value{Kind: kind, Val: new(big.Int).Lsh(a, uint(n.Uint64()))})
+}
+
+// evalComparison evaluates the comparison x op y.
+func evalComparison(op token.Token, x, y Value) (Value, error) {
+	var cmp int
+	switch {
+	case x.Kind == BoolConst && y.Kind == BoolConst:
+		if op.Kind != token.Eq && op.Kind != token.Neq {
+			return Value{}, constErrorf(op.Pos(), "invalid operation: operator %s not defined on %v (%v constant)", op.Val, x, x.Kind)
+		}
+		if x.Val.(bool) != y.Val.(bool) {
+			cmp = 1
+		}
+	case x.Kind == StringConst && y.Kind == StringConst:
+		a, b := x.Val.(string), y.Val.(string)
+		switch {
+		case a < b:
+			cmp = -1
+		case a > b:
+			cmp = 1
+		}
+	case x.Kind.isNumeric() && y.Kind.isNumeric():
+		if x.Kind == FloatConst || y.Kind == FloatConst {
+			cmp = toFloat(x).Cmp(toFloat(y))
+		} else {
+			cmp = x.Val.(*big.Int).Cmp(y.Val.(*big.Int))
+		}
+	default:
+		return Value{}, constErrorf(op.Pos(), "invalid operation: mismatched constant kinds %v and %v", x.Kind, y.Kind)
+	}
+	var b bool
+	switch op.Kind {
+	case token.Eq:
+		b = cmp == 0
+	case token.Neq:
+		b = cmp != 0
+	case token.Lt:
+		b = cmp < 0
+	case token.Lte:
+		b = cmp <= 0
+	case token.Gt:
+		b = cmp > 0
+	case token.Gte:
+		b = cmp >= 0
+	}
+	return Value{Kind: BoolConst, Val: b}, nil
+}
+
+// checkInt returns the provided integer or rune constant, or an error
+// positioned at tok if it exceeds the maximum number of bits.
+func checkInt(tok token.Token, v Value) (Value, error) {
+	if v.Val.(*big.Int).BitLen() > maxBits {
+		return Value{}, constErrorf(tok.Pos(), "constant overflow")
+	}
+	return v, nil
+}
+
+// toInt returns the value of the provided numeric constant as an integer, and a
+// boolean indicating whether it is representable as such.
+func toInt(v Value) (*big.Int, bool) {
+	switch val := v.Val.(type) {
+	case *big.Int:
+		return val, true
+	case *big.Float:
+		if !val.IsInt() {
+			return nil, false
+		}
+		x, _ := val.Int(nil)
+		return x, true
+	}
+	return nil, false
+}
+
+// toFloat returns the value of the provided numeric constant as a
+// floating-point number.
+func toFloat(v Value) *big.Float {
+	if x, ok := v.Val.(*big.Float); ok {
+		return x
+	}
+	return new(big.Float).SetPrec(floatPrec).SetInt(v.Val.(*big.Int))
+}
+
+// constErrorf returns an error of EvalConst at the provided position.
+func constErrorf(pos token.Position, format string, args ...interface{}) error {
+	return fmt.Errorf("ast.EvalConst: %v: %s", pos, fmt.Sprintf(format, args...))
+}
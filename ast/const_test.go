@@ -0,0 +1,223 @@
+package ast_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mewlang/go/ast"
+	"github.com/mewlang/go/lexer"
+	"github.com/mewlang/go/parser"
+)
+
+func TestEvalConst(t *testing.T) {
+	golden := []struct {
+		input string
+		// Value of iota; -1 denotes EvalConst.
+		iota int
+		kind ast.ConstKind
+		want string
+		err  string
+	}{
+		// i=0
+		{input: "1<<10", iota: -1, kind: ast.IntConst, want: "1024"},
+		{input: `len("abc")`, iota: -1, kind: ast.IntConst, want: "3"},
+		{input: "3 * 4 + 2", iota: -1, kind: ast.IntConst, want: "14"},
+		{input: "(1 + 2) * 3", iota: -1, kind: ast.IntConst, want: "9"},
+		{input: "7 / 2", iota: -1, kind: ast.IntConst, want: "3"},
+		// i=5
+		{input: "-7 / 2", iota: -1, kind: ast.IntConst, want: "-3"},
+		{input: "-7 % 2", iota: -1, kind: ast.IntConst, want: "-1"},
+		{input: "0x10 | 0x3 &^ 1", iota: -1, kind: ast.IntConst, want: "18"},
+		{input: "^0", iota: -1, kind: ast.IntConst, want: "-1"},
+		{input: "-1 >> 100", iota: -1, kind: ast.IntConst, want: "-1"},
+		// i=10
+		{input: "7.0 / 2", iota: -1, kind: ast.FloatConst, want: "3.5"},
+		{input: "1.5e3 + .5", iota: -1, kind: ast.FloatConst, want: "1500.5"},
+		{input: "5.0 << 2", iota: -1, kind: ast.IntConst, want: "20"},
+		{input: "'a' + 1", iota: -1, kind: ast.RuneConst, want: "98"},
+		{input: `'\n' * 2.5`, iota: -1, kind: ast.FloatConst, want: "25"},
+		// i=15
+		{input: `"foo" + "bar"`, iota: -1, kind: ast.StringConst, want: `"foobar"`},
+		{input: `len("日本" + ` + "`語`" + `)`, iota: -1, kind: ast.IntConst, want: "9"},
+		{input: `1 < 2 && "a" == "a"`, iota: -1, kind: ast.BoolConst, want: "true"},
+		{input: "!true || false", iota: -1, kind: ast.BoolConst, want: "false"},
+		{input: "1.0 == 1 && 'a' >= 97", iota: -1, kind: ast.BoolConst, want: "true"},
+		// i=20
+		{input: "1 << iota + 0x10", iota: 2, kind: ast.IntConst, want: "20"},
+		{input: "1 << 511 >> 511", iota: -1, kind: ast.IntConst, want: "1"},
+		{input: "0 << 1000", iota: -1, kind: ast.IntConst, want: "0"},
+		// Errors.
+		{input: "1 / 0", iota: -1, err: "ast.EvalConst: 1:3: division by zero"},
+		{input: "1.0 / 0.0", iota: -1, err: "ast.EvalConst: 1:5: division by zero"},
+		// i=25
+		{input: "1 % 2.0", iota: -1, err: "ast.EvalConst: 1:3: invalid operation: operator % not defined on 1 (floating-point constant)"},
+		{input: "1 << 512", iota: -1, err: "ast.EvalConst: 1:3: constant overflow"},
+		{input: "1 << 511 * 2", iota: -1, err: "ast.EvalConst: 1:10: constant overflow"},
+		{input: "1 << -1", iota: -1, err: "ast.EvalConst: 1:3: invalid operation: invalid shift count -1"},
+		{input: "1.5 << 2", iota: -1, err: "ast.EvalConst: 1:5: invalid operation: shifted operand 1.5 must be integer"},
+		// i=30
+		{input: `"a" + 1`, iota: -1, err: "ast.EvalConst: 1:5: invalid operation: mismatched constant kinds string and integer"},
+		{input: "true < false", iota: -1, err: "ast.EvalConst: 1:6: invalid operation: operator < not defined on true (boolean constant)"},
+		{input: "-true", iota: -1, err: "ast.EvalConst: 1:1: invalid operation: operator - not defined on true (boolean constant)"},
+		{input: "x + 1", iota: -1, err: "ast.EvalConst: 1:1: x is not a constant"},
+		{input: "iota", iota: -1, err: "ast.EvalConst: 1:1: cannot use iota outside constant declaration"},
+		// i=35
+		{input: "len(1)", iota: -1, err: "ast.EvalConst: 1:5: invalid argument 1 (integer constant) for len"},
+		{input: "2i", iota: -1, err: "ast.EvalConst: 1:1: unsupported constant 2i"},
+	}
+
+	for i, g := range golden {
+		tokens, err := lexer.Parse(g.input)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		e, err := parser.ParseExpr(tokens)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		var v ast.Value
+		if g.iota < 0 {
+			v, err = ast.EvalConst(e)
+		} else {
+			v, err = ast.EvalConstIota(e, g.iota)
+		}
+		if g.err != "" {
+			if err == nil || err.Error() != g.err {
+				t.Errorf("i=%d: error mismatch; expected %q, got %v.", i, g.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		if v.Kind != g.kind || v.String() != g.want {
+			t.Errorf("i=%d: value mismatch; expected %v constant %s, got %v constant %s.", i, g.kind, g.want, v.Kind, v)
+		}
+	}
+}
+
+func TestValueInt64(t *testing.T) {
+	golden := []struct {
+		input string
+		want  int64
+		ok    bool
+	}{
+		{input: "1 << 62", want: 1 << 62, ok: true},
+		{input: "'a'", want: 97, ok: true},
+		{input: "8.0 / 2", want: 4, ok: true},
+		{input: "1 << 63", ok: false},
+		{input: "0.5", ok: false},
+		{input: `"4"`, ok: false},
+	}
+
+	for i, g := range golden {
+		tokens, err := lexer.Parse(g.input)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		e, err := parser.ParseExpr(tokens)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		v, err := ast.EvalConst(e)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		got, ok := v.Int64()
+		if got != g.want || ok != g.ok {
+			t.Errorf("i=%d: value mismatch; expected (%d, %v), got (%d, %v).", i, g.want, g.ok, got, ok)
+		}
+	}
+}
+
+func TestEvalConstDecl(t *testing.T) {
+	golden := []struct {
+		input string
+		want  []string
+		err   string
+	}{
+		// i=0
+		{
+			input: `package p
+
+const (
+	FooA T = 1<<iota /* bitfield … */ + 0x10   /* Foo start value */
+	FooB                                       /* FooB specifies … */
+	FooC                                       /* FooC specifies … */
+	BarA T = 1<<iota /* bitfield … */ + 0x100  /* Bar start value */
+	BarB                                       /* BarB specifies … */
+	BarC                                       /* BarC specifies … */
+	BazA T = 1<<iota /* bitfield … */ + 0x1000 /* Baz start value */
+	BazB                                       /* BazB specifies … */
+	BazC                                       /* BazC specifies … */
+)
+`,
+			want: []string{"17", "18", "20", "264", "272", "288", "4160", "4224", "4352"},
+		},
+		// i=1
+		{
+			input: `package p
+
+const (
+	A = 1 << iota
+	B
+	C, D = iota * 20, B * 40
+	_, E
+)
+`,
+			want: []string{"1", "2", "40", "80", "60", "80"},
+		},
+		// i=2
+		{
+			input: "package p\n\nconst x, y = iota, iota\n",
+			want:  []string{"0", "0"},
+		},
+		// i=3
+		{
+			input: "package p\n\nconst (\n\tA = 1\n\tB = A + C\n\tC = 2\n)\n",
+			err:   "ast.EvalConst: 5:10: C is not a constant",
+		},
+	}
+
+	for i, g := range golden {
+		tokens, err := lexer.Parse(g.input)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		f, err := parser.ParseFile(tokens)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		decl, ok := f.Decls[0].(ast.ConstDecl)
+		if !ok {
+			t.Errorf("i=%d: declaration type mismatch; expected ast.ConstDecl, got %T.", i, f.Decls[0])
+			continue
+		}
+		vals, err := ast.EvalConstDecl(decl)
+		if g.err != "" {
+			if err == nil || err.Error() != g.err {
+				t.Errorf("i=%d: error mismatch; expected %q, got %v.", i, g.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		var got []string
+		for _, v := range vals {
+			got = append(got, v.String())
+		}
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: values mismatch; expected %v, got %v.", i, g.want, got)
+		}
+	}
+}
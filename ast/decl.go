@@ -36,6 +36,12 @@ type ImportSpec struct {
 //
 // ref: http://golang.org/ref/spec#Declarations_and_scope
 type TopLevelDecl interface {
+	// Pos returns the position of the first identifier declared by the
+	// declaration; the positions of keywords are not recorded.
+	Pos() token.Position
+	// DeclKind returns the keyword of the declaration; i.e. Const, Type, Var or
+	// Func.
+	DeclKind() token.Kind
 	// isTopLevelDecl ensures that only top level declaration nodes can be
 	// assigned to the TopLevelDecl interface.
 	isTopLevelDecl()
@@ -54,6 +60,11 @@ type TopLevelDecl interface {
 //
 // ref: http://golang.org/ref/spec#Declarations_and_scope
 type Decl interface {
+	// Pos returns the position of the first identifier declared by the
+	// declaration; the positions of keywords are not recorded.
+	Pos() token.Position
+	// DeclKind returns the keyword of the declaration; i.e. Const, Type or Var.
+	DeclKind() token.Kind
 	// isDecl ensures that only declaration nodes can be assigned to the Decl
 	// interface.
 	isDecl()
@@ -80,6 +91,13 @@ type VarDecl []ValueSpec
 
 // A ValueSpec binds a list of constant or variable identifiers to the values of
 // a list of constant or variable expressions respectively.
+//
+// Within a parenthesized constant declaration, a specifier without a type and
+// value expressions implicitly repeats the type and value expressions of the
+// previous specifier. The predeclared identifier iota represents the index of
+// the specifier within the constant declaration.
+//
+// ref: http://golang.org/ref/spec#Iota
 type ValueSpec struct {
 	// Constant or variable names.
 	Names []token.Token
@@ -87,6 +105,12 @@ type ValueSpec struct {
 	Type types.Type
 	// Constant or variable value expressions, or nil.
 	Vals []Expr
+	// Value of iota for constant specifiers; i.e. the index of the specifier
+	// within its constant declaration.
+	Iota int
+	// Specifies if the type and value expressions of a constant specifier are
+	// implicitly repeated from the previous specifier.
+	Implicit bool
 }
 
 // A TypeDecl consists of zero or more type specifiers.
@@ -132,6 +156,53 @@ type MethodDecl struct {
 	Body Block
 }
 
+// Pos returns the position of the first constant name, or the zero position if
+// the declaration is empty.
+func (decl ConstDecl) Pos() token.Position { return specsPos(decl) }
+
+// Pos returns the position of the first type name, or the zero position if the
+// declaration is empty.
+func (decl TypeDecl) Pos() token.Position {
+	if len(decl) == 0 {
+		return token.Position{}
+	}
+	return decl[0].Name.Pos()
+}
+
+// Pos returns the position of the first variable name, or the zero position if
+// the declaration is empty.
+func (decl VarDecl) Pos() token.Position { return specsPos(decl) }
+
+// Pos returns the position of the function name.
+func (decl FuncDecl) Pos() token.Position { return decl.Name.Pos() }
+
+// Pos returns the position of the method name.
+func (decl MethodDecl) Pos() token.Position { return decl.Name.Pos() }
+
+// specsPos returns the position of the first name of the provided constant or
+// variable specifiers, or the zero position if there are none.
+func specsPos(specs []ValueSpec) token.Position {
+	if len(specs) == 0 || len(specs[0].Names) == 0 {
+		return token.Position{}
+	}
+	return specs[0].Names[0].Pos()
+}
+
+// DeclKind returns Const.
+func (ConstDecl) DeclKind() token.Kind { return token.Const }
+
+// DeclKind returns Type.
+func (TypeDecl) DeclKind() token.Kind { return token.Type }
+
+// DeclKind returns Var.
+func (VarDecl) DeclKind() token.Kind { return token.Var }
+
+// DeclKind returns Func.
+func (FuncDecl) DeclKind() token.Kind { return token.Func }
+
+// DeclKind returns Func.
+func (MethodDecl) DeclKind() token.Kind { return token.Func }
+
 // isDecl ensures that only declaration nodes can be assigned to the Decl
 // interface.
 func (ConstDecl) isDecl() {}
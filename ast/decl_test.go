@@ -0,0 +1,72 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/mewlang/go/token"
+	"github.com/mewlang/go/types"
+)
+
+func TestTopLevelDeclPos(t *testing.T) {
+	tok := func(val string, line, col int) token.Token {
+		return token.Token{Kind: token.Ident, Val: val, Line: line, Col: col}
+	}
+	pos := func(line, col int) token.Position {
+		return token.Position{Line: line, Col: col}
+	}
+	golden := []struct {
+		decl TopLevelDecl
+		pos  token.Position
+		kind token.Kind
+	}{
+		// const (
+		// 	A, B = 1, 2
+		// 	C    = 3
+		// )
+		{
+			decl: ConstDecl{
+				{Names: []token.Token{tok("A", 2, 2), tok("B", 2, 5)}},
+				{Names: []token.Token{tok("C", 3, 2)}},
+			},
+			pos:  pos(2, 2),
+			kind: token.Const,
+		},
+		// type T int
+		{
+			decl: TypeDecl{{Name: tok("T", 1, 6), Type: types.Int}},
+			pos:  pos(1, 6),
+			kind: token.Type,
+		},
+		// var x int
+		{
+			decl: VarDecl{{Names: []token.Token{tok("x", 1, 5)}, Type: types.Int}},
+			pos:  pos(1, 5),
+			kind: token.Var,
+		},
+		// func f()
+		{
+			decl: FuncDecl{Name: tok("f", 1, 6)},
+			pos:  pos(1, 6),
+			kind: token.Func,
+		},
+		// func (t T) m()
+		{
+			decl: MethodDecl{Receiver: types.Parameter{Names: []token.Token{tok("t", 1, 7)}, Type: types.Name{Name: tok("T", 1, 9)}}, Name: tok("m", 1, 12)},
+			pos:  pos(1, 12),
+			kind: token.Func,
+		},
+		// Empty declarations.
+		{decl: ConstDecl{}, pos: pos(0, 0), kind: token.Const},
+		{decl: TypeDecl(nil), pos: pos(0, 0), kind: token.Type},
+		{decl: VarDecl{}, pos: pos(0, 0), kind: token.Var},
+	}
+
+	for i, g := range golden {
+		if got := g.decl.Pos(); got != g.pos {
+			t.Errorf("i=%d: position mismatch; expected %v, got %v.", i, g.pos, got)
+		}
+		if got := g.decl.DeclKind(); got != g.kind {
+			t.Errorf("i=%d: kind mismatch; expected %v, got %v.", i, g.kind, got)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package ast
+
+import (
+	"reflect"
+
+	"github.com/mewlang/go/token"
+)
+
+// EqualNode reports whether the nodes a and b have the same structure. Pointers
+// are compared by the values they point to, nil and empty slices are considered
+// equal, and tokens are compared by their kind and value while their positions
+// are ignored.
+//
+// The nodes may be any node of the abstract syntax tree, as traversed by Walk.
+func EqualNode(a, b interface{}) bool {
+	return equal(reflect.ValueOf(a), reflect.ValueOf(b), false)
+}
+
+// EqualNodeWithPos is like EqualNode, but also compares the line and column of
+// tokens.
+func EqualNodeWithPos(a, b interface{}) bool {
+	return equal(reflect.ValueOf(a), reflect.ValueOf(b), true)
+}
+
+// tokenType is the reflection type of token.Token.
+var tokenType = reflect.TypeOf(token.Token{})
+
+// equal reports whether the values x and y have the same structure. Token
+// positions are only compared if withPos is true.
+func equal(x, y reflect.Value, withPos bool) bool {
+	if !x.IsValid() || !y.IsValid() {
+		return x.IsValid() == y.IsValid()
+	}
+	if x.Type() != y.Type() {
+		return false
+	}
+	if x.Kind() == reflect.Struct && x.Type().ConvertibleTo(tokenType) {
+		// Tokens and nodes defined as tokens; e.g. BasicLit and OperandName.
+		a := x.Convert(tokenType).Interface().(token.Token)
+		b := y.Convert(tokenType).Interface().(token.Token)
+		if a.Kind != b.Kind || a.Val != b.Val {
+			return false
+		}
+		return !withPos || (a.Line == b.Line && a.Col == b.Col)
+	}
+
+	switch x.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if x.IsNil() || y.IsNil() {
+			return x.IsNil() == y.IsNil()
+		}
+		return equal(x.Elem(), y.Elem(), withPos)
+	case reflect.Slice, reflect.Array:
+		if x.Len() != y.Len() {
+			return false
+		}
+		for i := 0; i < x.Len(); i++ {
+			if !equal(x.Index(i), y.Index(i), withPos) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := 0; i < x.NumField(); i++ {
+			if !equal(x.Field(i), y.Field(i), withPos) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if x.Len() != y.Len() {
+			return false
+		}
+		for _, key := range x.MapKeys() {
+			if !equal(x.MapIndex(key), y.MapIndex(key), withPos) {
+				return false
+			}
+		}
+		return true
+	case reflect.Bool:
+		return x.Bool() == y.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return x.Int() == y.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return x.Uint() == y.Uint()
+	case reflect.String:
+		return x.String() == y.String()
+	}
+	// Functions, channels and other values which are not part of the syntax
+	// tree.
+	return false
+}
@@ -0,0 +1,83 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/mewlang/go/token"
+	"github.com/mewlang/go/types"
+)
+
+func TestEqualNode(t *testing.T) {
+	tok := func(kind token.Kind, val string, line, col int) token.Token {
+		return token.Token{Kind: kind, Val: val, Line: line, Col: col}
+	}
+	// x + 1, at the given line.
+	add := func(line int) Expr {
+		return &BinaryExpr{
+			Left:  OperandName(tok(token.Ident, "x", line, 1)),
+			Op:    tok(token.Add, "+", line, 3),
+			Right: BasicLit(tok(token.Int, "1", line, 5)),
+		}
+	}
+	// f(x + 1), at the given line.
+	call := func(line int, args ...interface{}) Expr {
+		return &CallExpr{
+			Func: OperandName(tok(token.Ident, "f", line, 1)),
+			Args: args,
+		}
+	}
+	golden := []struct {
+		a, b    interface{}
+		want    bool
+		withPos bool
+	}{
+		// Identical trees.
+		{a: add(1), b: add(1), want: true, withPos: true},
+		{a: call(1, add(1)), b: call(1, add(1)), want: true, withPos: true},
+		// Trees which only differ in token positions.
+		{a: add(1), b: add(2), want: true, withPos: false},
+		{a: call(1, add(1)), b: call(3, add(3)), want: true, withPos: false},
+		// Pointer and value nodes.
+		{a: add(1), b: *add(1).(*BinaryExpr), want: false, withPos: true},
+		// Different operators.
+		{
+			a:    add(1),
+			b:    &BinaryExpr{Left: OperandName(tok(token.Ident, "x", 1, 1)), Op: tok(token.Sub, "-", 1, 3), Right: BasicLit(tok(token.Int, "1", 1, 5))},
+			want: false,
+		},
+		// Different operand values.
+		{
+			a:    add(1),
+			b:    &BinaryExpr{Left: OperandName(tok(token.Ident, "y", 1, 1)), Op: tok(token.Add, "+", 1, 3), Right: BasicLit(tok(token.Int, "1", 1, 5))},
+			want: false,
+		},
+		// Different node types for the same token.
+		{a: OperandName(tok(token.Ident, "x", 1, 1)), b: BasicLit(tok(token.Ident, "x", 1, 1)), want: false},
+		// Argument lists.
+		{a: call(1), b: &CallExpr{Func: OperandName(tok(token.Ident, "f", 1, 1)), Args: []interface{}{}}, want: true, withPos: true},
+		{a: call(1, add(1)), b: call(1), want: false},
+		{a: call(1, add(1)), b: call(1, add(1), add(1)), want: false},
+		// Types.
+		{a: &Conversion{Type: types.Int, Expr: add(1)}, b: &Conversion{Type: types.Int, Expr: add(2)}, want: true},
+		{a: &Conversion{Type: types.Int, Expr: add(1)}, b: &Conversion{Type: types.Float64, Expr: add(1)}, want: false},
+		// Nil nodes.
+		{a: nil, b: nil, want: true, withPos: true},
+		{a: add(1), b: nil, want: false},
+		{a: &BinaryExpr{}, b: &BinaryExpr{}, want: true, withPos: true},
+	}
+
+	for i, g := range golden {
+		if got := EqualNode(g.a, g.b); got != g.want {
+			t.Errorf("i=%d: EqualNode mismatch; expected %v, got %v.", i, g.want, got)
+		}
+		if got := EqualNode(g.b, g.a); got != g.want {
+			t.Errorf("i=%d: EqualNode mismatch for swapped nodes; expected %v, got %v.", i, g.want, got)
+		}
+		if !g.want {
+			continue
+		}
+		if got := EqualNodeWithPos(g.a, g.b); got != g.withPos {
+			t.Errorf("i=%d: EqualNodeWithPos mismatch; expected %v, got %v.", i, g.withPos, got)
+		}
+	}
+}
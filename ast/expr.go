@@ -8,6 +8,8 @@ import (
 // An Expr specifies the computation of a value by applying operators and
 // functions to operands.
 type Expr interface {
+	// Pos returns the position of the leading token of the expression.
+	Pos() token.Position
 	// isExpr ensures that only expression nodes can be assigned to the Expr
 	// interface.
 	isExpr()
@@ -68,6 +70,7 @@ type BinaryExpr struct {
 //
 // ref: http://golang.org/ref/spec#Primary_expressions
 type PrimaryExpr interface {
+	Expr
 	// isPrimaryExpr ensures that only primary expression nodes can be assigned
 	// to the PrimaryExpr interface.
 	isPrimaryExpr()
@@ -156,6 +159,8 @@ type IndexExpr struct {
 //
 // ref: http://golang.org/ref/spec#Slice_expressions
 type SliceExpr struct {
+	// Expression.
+	Expr PrimaryExpr
 	// Lower bound.
 	Low Expr
 	// Higher bound.
@@ -179,6 +184,59 @@ type TypeAssertion struct {
 	Type types.Type
 }
 
+// Pos returns the position of the unary operator.
+func (x UnaryExpr) Pos() token.Position { return x.Op.Pos() }
+
+// Pos returns the position of the left-hand side operand.
+func (x BinaryExpr) Pos() token.Position { return x.Left.Pos() }
+
+// Pos returns the position of the result type if it is a type name, and the
+// position of the original expression otherwise; the positions of type literals
+// are not recorded.
+func (x Conversion) Pos() token.Position {
+	if pos, ok := typePos(x.Type); ok {
+		return pos
+	}
+	return x.Expr.Pos()
+}
+
+// Pos returns the position of the function or method expression.
+func (x CallExpr) Pos() token.Position { return x.Func.Pos() }
+
+// Pos returns the position of the primary expression.
+func (x SelectorExpr) Pos() token.Position { return x.Expr.Pos() }
+
+// Pos returns the position of the primary expression.
+func (x IndexExpr) Pos() token.Position { return x.Expr.Pos() }
+
+// Pos returns the position of the primary expression.
+func (x SliceExpr) Pos() token.Position { return x.Expr.Pos() }
+
+// Pos returns the position of the interface expression.
+func (x TypeAssertion) Pos() token.Position { return x.Expr.Pos() }
+
+// String returns the source text of the expression, as written by Fprint.
+func (x UnaryExpr) String() string     { return exprString(x) }
+func (x BinaryExpr) String() string    { return exprString(x) }
+func (x Conversion) String() string    { return exprString(x) }
+func (x CallExpr) String() string      { return exprString(x) }
+func (x SelectorExpr) String() string  { return exprString(x) }
+func (x IndexExpr) String() string     { return exprString(x) }
+func (x SliceExpr) String() string     { return exprString(x) }
+func (x TypeAssertion) String() string { return exprString(x) }
+
+// typePos returns the position of the provided type, and a boolean indicating
+// success. Only the positions of type names are recorded.
+func typePos(t types.Type) (token.Position, bool) {
+	if name, ok := t.(types.Name); ok {
+		if name.Pkg.Kind != token.None {
+			return name.Pkg.Pos(), true
+		}
+		return name.Name.Pos(), true
+	}
+	return token.Position{}, false
+}
+
 // isExpr ensures that only expression nodes can be assigned to the Expr
 // interface.
 func (UnaryExpr) isExpr()     {}
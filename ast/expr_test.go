@@ -0,0 +1,71 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/mewlang/go/token"
+	"github.com/mewlang/go/types"
+)
+
+func TestExprPos(t *testing.T) {
+	tok := func(kind token.Kind, val string, line, col int) token.Token {
+		return token.Token{Kind: kind, Val: val, Line: line, Col: col}
+	}
+	pos := func(line, col int) token.Position {
+		return token.Position{Line: line, Col: col}
+	}
+	var (
+		// a at 1:1
+		a = OperandName(tok(token.Ident, "a", 1, 1))
+		// b at 1:5
+		b = OperandName(tok(token.Ident, "b", 1, 5))
+		// 42 at 2:3
+		lit = BasicLit(tok(token.Int, "42", 2, 3))
+		// T at 3:1
+		name = types.Name{Name: tok(token.Ident, "T", 3, 1)}
+		// io.Reader at 4:2
+		qualified = types.Name{Pkg: tok(token.Ident, "io", 4, 2), Name: tok(token.Ident, "Reader", 4, 5)}
+	)
+	golden := []struct {
+		x    Expr
+		want token.Position
+	}{
+		// Operands.
+		{x: a, want: pos(1, 1)},
+		{x: lit, want: pos(2, 3)},
+		{x: ParenExpr{Expr: b}, want: pos(1, 5)},
+		{x: CompositeLit{Type: name}, want: pos(3, 1)},
+		{x: CompositeLit{Type: types.Slice{Elem: types.Int}}, want: pos(0, 0)},
+		{x: FuncLit{}, want: pos(0, 0)},
+		{x: MethodExpr{ReceiverType: name, Name: tok(token.Ident, "M", 3, 3)}, want: pos(3, 1)},
+		{x: MethodExpr{ReceiverType: types.Pointer{Base: name}, Name: tok(token.Ident, "M", 3, 6)}, want: pos(3, 1)},
+		// -a at 5:7
+		{x: UnaryExpr{Op: tok(token.Sub, "-", 5, 7), Expr: a}, want: pos(5, 7)},
+		// a + b
+		{x: BinaryExpr{Left: a, Op: tok(token.Add, "+", 1, 3), Right: b}, want: pos(1, 1)},
+		// (a + b) * 42
+		{
+			x: BinaryExpr{
+				Left:  BinaryExpr{Left: b, Op: tok(token.Add, "+", 1, 7), Right: a},
+				Op:    tok(token.Mul, "*", 1, 9),
+				Right: lit,
+			},
+			want: pos(1, 5),
+		},
+		// Primary expressions.
+		{x: Conversion{Type: qualified, Expr: a}, want: pos(4, 2)},
+		{x: Conversion{Type: name, Expr: a}, want: pos(3, 1)},
+		{x: Conversion{Type: types.Pointer{Base: name}, Expr: b}, want: pos(1, 5)},
+		{x: CallExpr{Func: a, Args: []interface{}{b}}, want: pos(1, 1)},
+		{x: SelectorExpr{Expr: CallExpr{Func: b}, Selector: tok(token.Ident, "x", 1, 9)}, want: pos(1, 5)},
+		{x: IndexExpr{Expr: a, Index: lit}, want: pos(1, 1)},
+		{x: SliceExpr{Expr: b, Low: lit}, want: pos(1, 5)},
+		{x: TypeAssertion{Expr: a, Type: name}, want: pos(1, 1)},
+	}
+
+	for i, g := range golden {
+		if got := g.x.Pos(); got != g.want {
+			t.Errorf("i=%d: position mismatch; expected %v, got %v.", i, g.want, got)
+		}
+	}
+}
@@ -23,6 +23,12 @@ import (
 //    BasicLit    = int_lit | float_lit | imaginary_lit | rune_lit | string_lit .
 //
 // ref: http://golang.org/ref/spec#Operands
+//
+// BasicLit is a distinct type defined from token.Token, rather than an alias of
+// it; a type switch on an Expr thus distinguishes it from OperandName, without
+// the need for a wrapper struct. The token type of the literal is the Kind field
+// of token.Token, e.g. token.String for "foo"; a Kind method cannot be declared
+// as it would collide with the field.
 type BasicLit token.Token
 
 // A CompositeLit constructs a value for a struct, array, slice, or map and
@@ -51,7 +57,8 @@ type CompositeLit struct {
 
 // A CompositeElement may be a single expression or a key-value pair.
 type CompositeElement struct {
-	// Element key, or nil; holds an identifier (token.Token) or an Expr.
+	// Element key, or nil; holds an identifier (token.Token), an Expr or a
+	// []CompositeElement.
 	Key interface{}
 	// Element value; holds an Expr or a []CompositeElement.
 	Val interface{}
@@ -75,6 +82,12 @@ type FuncLit struct {
 //    OperandName = identifier | QualifiedIdent.
 //
 // ref: http://golang.org/ref/spec#Operands
+//
+// OperandName is a distinct type defined from token.Token, rather than an alias
+// of it; a type switch on an Expr thus distinguishes it from BasicLit, without
+// the need for a wrapper struct. The token type of the operand name is the Kind
+// field of token.Token, which is token.Ident; a Kind method cannot be declared
+// as it would collide with the field.
 type OperandName token.Token
 
 // A MethodExpr yields a function equivalent to the specified method with an
@@ -97,6 +110,40 @@ type ParenExpr struct {
 	Expr Expr
 }
 
+// Pos returns the position of the basic literal.
+func (lit BasicLit) Pos() token.Position { return token.Token(lit).Pos() }
+
+// Pos returns the position of the literal type if it is a type name; the
+// positions of type literals are not recorded, in which case the zero position
+// is returned.
+func (lit CompositeLit) Pos() token.Position {
+	pos, _ := typePos(lit.Type)
+	return pos
+}
+
+// Pos returns the zero position, as the position of the func keyword of a
+// function literal is not recorded.
+func (lit FuncLit) Pos() token.Position { return token.Position{} }
+
+// Pos returns the position of the operand name.
+func (name OperandName) Pos() token.Position { return token.Token(name).Pos() }
+
+// Pos returns the position of the receiver type name. The position of the
+// leading parenthesis of a pointer receiver type is not recorded, in which case
+// the position of the base type name is returned.
+func (x MethodExpr) Pos() token.Position {
+	t := x.ReceiverType
+	if ptr, ok := t.(types.Pointer); ok {
+		t = ptr.Base
+	}
+	pos, _ := typePos(t)
+	return pos
+}
+
+// Pos returns the position of the parenthesized expression; the position of the
+// left parenthesis is not recorded.
+func (x ParenExpr) Pos() token.Position { return x.Expr.Pos() }
+
 // isExpr ensures that only expression nodes can be assigned to the Expr
 // interface.
 func (BasicLit) isExpr()     {}
@@ -114,3 +161,15 @@ func (FuncLit) isPrimaryExpr()      {}
 func (OperandName) isPrimaryExpr()  {}
 func (MethodExpr) isPrimaryExpr()   {}
 func (ParenExpr) isPrimaryExpr()    {}
+
+// String returns the source text of the basic literal.
+func (lit BasicLit) String() string { return lit.Val }
+
+// String returns the source text of the operand name.
+func (name OperandName) String() string { return name.Val }
+
+// String returns the source text of the operand, as written by Fprint.
+func (lit CompositeLit) String() string { return exprString(lit) }
+func (lit FuncLit) String() string      { return exprString(lit) }
+func (x MethodExpr) String() string     { return exprString(x) }
+func (x ParenExpr) String() string      { return exprString(x) }
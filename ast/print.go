@@ -0,0 +1,421 @@
+package ast
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mewlang/go/token"
+	"github.com/mewlang/go/types"
+)
+
+// Fprint writes the source code of the provided node to w. The node may be a
+// File, a declaration, an expression or a type; e.g.
+//
+//    const (
+//    	A = 1
+//    	B = A + 2
+//    )
+//
+// Parentheses are inserted around the operands of binary expressions where
+// needed to preserve the meaning of the expression. Statements are not yet
+// supported, and an error is returned for any non-empty function body.
+func Fprint(w io.Writer, node interface{}) error {
+	p := &printer{w: w}
+	p.node(node)
+	return p.err
+}
+
+// exprString returns the source text of the provided expression.
+func exprString(x Expr) string {
+	buf := new(bytes.Buffer)
+	p := &printer{w: buf}
+	p.expr(x)
+	return buf.String()
+}
+
+// A printer writes the source code of nodes to an underlying writer. The first
+// error encountered is recorded and all subsequent writes are ignored.
+type printer struct {
+	// Underlying writer.
+	w io.Writer
+	// Indentation level.
+	indent int
+	// First error encountered, if any.
+	err error
+}
+
+// print writes the provided strings to the underlying writer.
+func (p *printer) print(ss ...string) {
+	for _, s := range ss {
+		if p.err != nil {
+			return
+		}
+		_, p.err = io.WriteString(p.w, s)
+	}
+}
+
+// newline writes a line break followed by the current indentation.
+func (p *printer) newline() {
+	p.print("\n", strings.Repeat("\t", p.indent))
+}
+
+// typ writes the source code of the provided type.
+func (p *printer) typ(t types.Type) {
+	if p.err != nil {
+		return
+	}
+	p.err = types.WriteType(p.w, t)
+}
+
+// node writes the source code of the provided node.
+func (p *printer) node(node interface{}) {
+	switch n := node.(type) {
+	// Source files.
+	case *File:
+		p.file(n)
+	case File:
+		p.file(&n)
+
+	// Declarations.
+	case ImportDecl:
+		p.print("import ")
+		p.group(len(n), func(i int) { p.importSpec(n[i]) })
+	case ImportSpec:
+		p.importSpec(n)
+	case ConstDecl:
+		p.print("const ")
+		p.group(len(n), func(i int) { p.valueSpec(n[i]) })
+	case VarDecl:
+		p.print("var ")
+		p.group(len(n), func(i int) { p.valueSpec(n[i]) })
+	case ValueSpec:
+		p.valueSpec(n)
+	case TypeDecl:
+		p.print("type ")
+		p.group(len(n), func(i int) { p.typeSpec(n[i]) })
+	case FuncDecl:
+		p.print("func ", n.Name.Val)
+		p.signature(n.Sig)
+		p.body(n.Body)
+	case MethodDecl:
+		p.print("func (")
+		p.params([]types.Parameter{n.Receiver})
+		p.print(") ", n.Name.Val)
+		p.signature(n.Sig)
+		p.body(n.Body)
+
+	// Expressions and types.
+	case Expr:
+		p.expr(n)
+	case types.Type:
+		p.typ(n)
+
+	default:
+		if p.err == nil {
+			p.err = fmt.Errorf("ast.Fprint: unsupported node type %T", node)
+		}
+	}
+}
+
+// file writes the source code of the provided source file.
+func (p *printer) file(f *File) {
+	p.print("package ", f.PkgName.Val, "\n")
+	for _, imp := range f.Imps {
+		p.print("\n")
+		p.node(imp)
+		p.print("\n")
+	}
+	for _, decl := range f.Decls {
+		p.print("\n")
+		p.node(decl)
+		p.print("\n")
+	}
+}
+
+// group writes either a single specifier or a parenthesized group of n
+// specifiers, one per line. The provided function is invoked to write the ith
+// specifier.
+func (p *printer) group(n int, spec func(i int)) {
+	if n == 1 {
+		spec(0)
+		return
+	}
+	p.print("(")
+	p.indent++
+	for i := 0; i < n; i++ {
+		p.newline()
+		spec(i)
+	}
+	p.indent--
+	p.newline()
+	p.print(")")
+}
+
+// importSpec writes the source code of the provided import specifier.
+func (p *printer) importSpec(spec ImportSpec) {
+	if spec.Name.Kind != token.None {
+		p.print(spec.Name.Val, " ")
+	}
+	p.print(spec.Path.Val)
+}
+
+// valueSpec writes the source code of the provided constant or variable
+// specifier.
+func (p *printer) valueSpec(spec ValueSpec) {
+	p.idents(spec.Names)
+	if spec.Implicit {
+		return
+	}
+	if spec.Type != nil {
+		p.print(" ")
+		p.typ(spec.Type)
+	}
+	if len(spec.Vals) > 0 {
+		p.print(" = ")
+		for i, val := range spec.Vals {
+			if i > 0 {
+				p.print(", ")
+			}
+			p.expr(val)
+		}
+	}
+}
+
+// typeSpec writes the source code of the provided type specifier.
+func (p *printer) typeSpec(name types.Name) {
+	p.print(name.Name.Val, " ")
+	p.typ(name.Type)
+}
+
+// signature writes the parameters and results of the provided function
+// signature.
+func (p *printer) signature(sig types.Func) {
+	// The string representation of a function type is the keyword "func"
+	// followed by its signature.
+	p.print(strings.TrimPrefix(sig.String(), "func"))
+}
+
+// params writes the provided comma-separated list of parameters.
+func (p *printer) params(params []types.Parameter) {
+	for i, param := range params {
+		if i > 0 {
+			p.print(", ")
+		}
+		if len(param.Names) > 0 {
+			p.idents(param.Names)
+			p.print(" ")
+		}
+		p.typ(param.Type)
+	}
+}
+
+// body writes the provided function body, if any.
+func (p *printer) body(body Block) {
+	if body == nil {
+		return
+	}
+	if len(body) > 0 {
+		if p.err == nil {
+			if _, ok := body[0].(BadStmt); ok {
+				p.err = errors.New("ast.Fprint: function body has not been parsed")
+			} else {
+				p.err = fmt.Errorf("ast.Fprint: unsupported node type %T", body[0])
+			}
+		}
+		return
+	}
+	p.print(" {")
+	p.newline()
+	p.print("}")
+}
+
+// idents writes the provided comma-separated list of identifiers.
+func (p *printer) idents(names []token.Token) {
+	for i, name := range names {
+		if i > 0 {
+			p.print(", ")
+		}
+		p.print(name.Val)
+	}
+}
+
+// expr writes the source code of the provided expression.
+func (p *printer) expr(x Expr) {
+	switch x := x.(type) {
+	case BasicLit:
+		p.print(x.Val)
+	case OperandName:
+		p.print(x.Val)
+	case ParenExpr:
+		p.print("(")
+		p.expr(x.Expr)
+		p.print(")")
+	case UnaryExpr:
+		p.print(x.Op.Val)
+		if y, ok := x.Expr.(UnaryExpr); ok && isMergedOp(x.Op.Val+y.Op.Val) {
+			// Prevent "- -x" from being written as "--x".
+			p.print(" ")
+		}
+		p.operand(x.Expr, unaryPrec)
+	case BinaryExpr:
+		prec := x.Op.Kind.Precedence()
+		// Binary operators associate from left to right, so the right operand
+		// requires parentheses also for operators of the same precedence.
+		p.operand(x.Left, prec)
+		p.print(" ", x.Op.Val, " ")
+		p.operand(x.Right, prec+1)
+	case Conversion:
+		switch t := x.Type.(type) {
+		case types.Pointer, types.Func:
+			p.print("(")
+			p.typ(t)
+			p.print(")")
+		case types.Chan:
+			if t.Dir == types.Recv {
+				p.print("(")
+				p.typ(t)
+				p.print(")")
+			} else {
+				p.typ(t)
+			}
+		default:
+			p.typ(t)
+		}
+		p.print("(")
+		p.expr(x.Expr)
+		p.print(")")
+	case CallExpr:
+		p.operand(x.Func, unaryPrec+1)
+		p.print("(")
+		for i, arg := range x.Args {
+			if i > 0 {
+				p.print(", ")
+			}
+			p.node(arg)
+		}
+		if x.HasEllipsis {
+			p.print("...")
+		}
+		p.print(")")
+	case SelectorExpr:
+		p.operand(x.Expr, unaryPrec+1)
+		p.print(".", x.Selector.Val)
+	case IndexExpr:
+		p.operand(x.Expr, unaryPrec+1)
+		p.print("[")
+		p.expr(x.Index)
+		p.print("]")
+	case SliceExpr:
+		p.operand(x.Expr, unaryPrec+1)
+		p.print("[")
+		p.optExpr(x.Low)
+		p.print(":")
+		p.optExpr(x.High)
+		if x.Cap != nil {
+			p.print(":")
+			p.expr(x.Cap)
+		}
+		p.print("]")
+	case TypeAssertion:
+		p.operand(x.Expr, unaryPrec+1)
+		p.print(".(")
+		p.typ(x.Type)
+		p.print(")")
+	case MethodExpr:
+		if _, ok := x.ReceiverType.(types.Pointer); ok {
+			p.print("(")
+			p.typ(x.ReceiverType)
+			p.print(")")
+		} else {
+			p.typ(x.ReceiverType)
+		}
+		p.print(".", x.Name.Val)
+	case FuncLit:
+		p.print("func")
+		p.signature(x.Sig)
+		p.body(x.Body)
+	case CompositeLit:
+		p.typ(x.Type)
+		p.literalValue(x.Vals)
+	default:
+		if p.err == nil {
+			p.err = fmt.Errorf("ast.Fprint: unsupported node type %T", x)
+		}
+	}
+}
+
+// literalValue writes the source code of the provided composite literal
+// elements, enclosed in braces.
+func (p *printer) literalValue(elems []CompositeElement) {
+	p.print("{")
+	for i, elem := range elems {
+		if i > 0 {
+			p.print(", ")
+		}
+		switch key := elem.Key.(type) {
+		case token.Token:
+			p.print(key.Val, ": ")
+		case Expr:
+			p.expr(key)
+			p.print(": ")
+		case []CompositeElement:
+			p.literalValue(key)
+			p.print(": ")
+		}
+		switch val := elem.Val.(type) {
+		case []CompositeElement:
+			p.literalValue(val)
+		case Expr:
+			p.expr(val)
+		}
+	}
+	p.print("}")
+}
+
+// optExpr writes the source code of the provided expression, if any.
+func (p *printer) optExpr(x Expr) {
+	if x != nil {
+		p.expr(x)
+	}
+}
+
+// unaryPrec is the precedence of unary expressions, which bind tighter than any
+// binary operator.
+const unaryPrec = 6
+
+// operand writes the source code of the provided operand, surrounded by
+// parentheses if it binds less tightly than prec.
+func (p *printer) operand(x Expr, prec int) {
+	if precedence(x) < prec {
+		p.print("(")
+		p.expr(x)
+		p.print(")")
+		return
+	}
+	p.expr(x)
+}
+
+// precedence returns the precedence of the provided expression.
+func precedence(x Expr) int {
+	switch x := x.(type) {
+	case BinaryExpr:
+		return x.Op.Kind.Precedence()
+	case UnaryExpr:
+		return unaryPrec
+	}
+	// Primary expressions.
+	return unaryPrec + 1
+}
+
+// isMergedOp reports whether the provided concatenation of two unary operators
+// would be lexed as a single token.
+func isMergedOp(s string) bool {
+	switch s {
+	case "++", "--", "&&", "&^":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,289 @@
+package ast_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mewlang/go/ast"
+	"github.com/mewlang/go/lexer"
+	"github.com/mewlang/go/parser"
+	"github.com/mewlang/go/token"
+	"github.com/mewlang/go/types"
+)
+
+func TestFprint(t *testing.T) {
+	tok := func(kind token.Kind, val string) token.Token {
+		return token.Token{Kind: kind, Val: val}
+	}
+	var (
+		a   = ast.OperandName(tok(token.Ident, "a"))
+		b   = ast.OperandName(tok(token.Ident, "b"))
+		c   = ast.OperandName(tok(token.Ident, "c"))
+		add = tok(token.Add, "+")
+		sub = tok(token.Sub, "-")
+		mul = tok(token.Mul, "*")
+	)
+	golden := []struct {
+		node interface{}
+		want string
+	}{
+		// Parentheses are inserted based on operator precedence.
+		{
+			node: ast.BinaryExpr{Left: ast.BinaryExpr{Left: a, Op: add, Right: b}, Op: mul, Right: c},
+			want: "(a + b) * c",
+		},
+		{
+			node: ast.BinaryExpr{Left: a, Op: mul, Right: ast.BinaryExpr{Left: b, Op: add, Right: c}},
+			want: "a * (b + c)",
+		},
+		{
+			node: ast.BinaryExpr{Left: a, Op: add, Right: ast.BinaryExpr{Left: b, Op: mul, Right: c}},
+			want: "a + b * c",
+		},
+		// Binary operators associate from left to right.
+		{
+			node: ast.BinaryExpr{Left: ast.BinaryExpr{Left: a, Op: sub, Right: b}, Op: sub, Right: c},
+			want: "a - b - c",
+		},
+		{
+			node: ast.BinaryExpr{Left: a, Op: sub, Right: ast.BinaryExpr{Left: b, Op: sub, Right: c}},
+			want: "a - (b - c)",
+		},
+		// Unary expressions.
+		{node: ast.UnaryExpr{Op: sub, Expr: ast.UnaryExpr{Op: sub, Expr: a}}, want: "- -a"},
+		{node: ast.UnaryExpr{Op: sub, Expr: ast.BinaryExpr{Left: a, Op: add, Right: b}}, want: "-(a + b)"},
+		{node: ast.UnaryExpr{Op: tok(token.Mul, "*"), Expr: ast.SelectorExpr{Expr: a, Selector: tok(token.Ident, "x")}}, want: "*a.x"},
+		// Primary expressions.
+		{
+			node: ast.CallExpr{Func: ast.SelectorExpr{Expr: a, Selector: tok(token.Ident, "f")}, Args: []interface{}{b, c}, HasEllipsis: true},
+			want: "a.f(b, c...)",
+		},
+		{node: ast.SliceExpr{Expr: a, High: b}, want: "a[:b]"},
+		{node: ast.SliceExpr{Expr: a, Low: b, High: c, Cap: c}, want: "a[b:c:c]"},
+		{node: ast.Conversion{Type: types.Pointer{Base: types.Int}, Expr: a}, want: "(*int)(a)"},
+		{node: ast.TypeAssertion{Expr: a, Type: types.String}, want: "a.(string)"},
+		// Declarations.
+		{
+			node: ast.ConstDecl{{Names: []token.Token{tok(token.Ident, "A")}, Vals: []ast.Expr{ast.BasicLit(tok(token.Int, "1"))}}},
+			want: "const A = 1",
+		},
+		{
+			node: ast.VarDecl{
+				{Names: []token.Token{tok(token.Ident, "x"), tok(token.Ident, "y")}, Type: types.Int},
+				{Names: []token.Token{tok(token.Ident, "s")}, Vals: []ast.Expr{ast.BasicLit(tok(token.String, `"s"`))}},
+			},
+			want: "var (\n\tx, y int\n\ts = \"s\"\n)",
+		},
+		{
+			node: ast.FuncDecl{Name: tok(token.Ident, "f"), Sig: types.Func{Params: []types.Parameter{{Names: []token.Token{tok(token.Ident, "n")}, Type: types.Int}}}},
+			want: "func f(n int)",
+		},
+		{
+			node: ast.VarDecl{{Names: []token.Token{tok(token.Ident, "a")}, Type: types.Array{Len: ast.BinaryExpr{Left: ast.BasicLit(tok(token.Int, "2")), Op: mul, Right: ast.OperandName(tok(token.Ident, "N"))}, Elem: types.Int}}},
+			want: "var a [2 * N]int",
+		},
+	}
+
+	for i, g := range golden {
+		buf := new(bytes.Buffer)
+		if err := ast.Fprint(buf, g.node); err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		if got := buf.String(); got != g.want {
+			t.Errorf("i=%d: output mismatch; expected %q, got %q.", i, g.want, got)
+		}
+	}
+}
+
+func TestExprString(t *testing.T) {
+	tok := func(kind token.Kind, val string) token.Token {
+		return token.Token{Kind: kind, Val: val}
+	}
+	n := ast.OperandName(tok(token.Ident, "N"))
+	golden := []struct {
+		len  interface{}
+		want string
+	}{
+		{len: ast.BinaryExpr{Left: ast.BasicLit(tok(token.Int, "2")), Op: tok(token.Mul, "*"), Right: n}, want: "[2 * N]int"},
+		{len: ast.CallExpr{Func: ast.OperandName(tok(token.Ident, "len")), Args: []interface{}{n}}, want: "[len(N)]int"},
+		{len: ast.ParenExpr{Expr: n}, want: "[(N)]int"},
+		{len: ast.SelectorExpr{Expr: ast.OperandName(tok(token.Ident, "pkg")), Selector: tok(token.Ident, "N")}, want: "[pkg.N]int"},
+	}
+
+	for i, g := range golden {
+		typ := types.Array{Len: g.len, Elem: types.Int}
+		buf := new(bytes.Buffer)
+		if err := types.WriteType(buf, typ); err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		if got := buf.String(); got != g.want {
+			t.Errorf("i=%d: WriteType mismatch; expected %q, got %q.", i, g.want, got)
+		}
+		if got := typ.String(); got != g.want {
+			t.Errorf("i=%d: String mismatch; expected %q, got %q.", i, g.want, got)
+		}
+	}
+}
+
+func TestFprintRoundTrip(t *testing.T) {
+	golden := []string{
+		"package p\n",
+		`package p
+
+import "fmt"
+
+import (
+	"io"
+	str "strings"
+)
+
+const A, B = 1, A + 2
+
+const (
+	C = iota
+	D
+)
+
+var x = fmt.Sprint(A * (B - y[1:2]), -(x + 1), *p, a.b.c[d])
+
+var y, z int
+
+type T struct{X, Y int; *fmt.Stringer}
+
+type (
+	U []map[string]chan<- *T
+	V func(a, b int, s ...string) (int, error)
+)
+
+func f(x int) int {
+}
+
+func (t T) Len(s ...string) int
+`,
+	}
+
+	for i, input := range golden {
+		want, err := lexer.Parse(input)
+		if err != nil {
+			t.Errorf("i=%d: lexer.Parse failed; %v", i, err)
+			continue
+		}
+		f, err := parser.ParseFile(want)
+		if err != nil {
+			t.Errorf("i=%d: parser.ParseFile failed; %v", i, err)
+			continue
+		}
+		buf := new(bytes.Buffer)
+		if err := ast.Fprint(buf, f); err != nil {
+			t.Errorf("i=%d: ast.Fprint failed; %v", i, err)
+			continue
+		}
+		got, err := lexer.Parse(buf.String())
+		if err != nil {
+			t.Errorf("i=%d: lexer.Parse of printed source failed; %v", i, err)
+			continue
+		}
+		if !equalTokens(got, want) {
+			t.Errorf("i=%d: token stream mismatch; expected %v, got %v.\n%s", i, want, got, buf)
+		}
+	}
+}
+
+func TestFprintParenExpr(t *testing.T) {
+	// Explicit parentheses are preserved, even where redundant.
+	golden := []string{
+		"(a + b) * c",
+		"a * (b + c)",
+		"(a * b) + c",
+		"(a) + b",
+		"((a))",
+		"-(a + b)",
+		"(f)(x)",
+		"(*p).x",
+	}
+
+	for i, input := range golden {
+		tokens, err := lexer.Parse(input)
+		if err != nil {
+			t.Errorf("i=%d: lexer.Parse failed; %v", i, err)
+			continue
+		}
+		x, err := parser.ParseExpr(tokens)
+		if err != nil {
+			t.Errorf("i=%d: parser.ParseExpr failed; %v", i, err)
+			continue
+		}
+		buf := new(bytes.Buffer)
+		if err := ast.Fprint(buf, x); err != nil {
+			t.Errorf("i=%d: ast.Fprint failed; %v", i, err)
+			continue
+		}
+		if got := buf.String(); got != input {
+			t.Errorf("i=%d: output mismatch; expected %q, got %q.", i, input, got)
+		}
+	}
+
+	// The left operand of "(a + b) * c" is a parenthesized expression.
+	tokens, err := lexer.Parse("(a + b) * c")
+	if err != nil {
+		t.Fatalf("lexer.Parse failed; %v", err)
+	}
+	x, err := parser.ParseExpr(tokens)
+	if err != nil {
+		t.Fatalf("parser.ParseExpr failed; %v", err)
+	}
+	bin, ok := x.(ast.BinaryExpr)
+	if !ok {
+		t.Fatalf("expression type mismatch; expected ast.BinaryExpr, got %T.", x)
+	}
+	if _, ok := bin.Left.(ast.ParenExpr); !ok {
+		t.Errorf("left operand type mismatch; expected ast.ParenExpr, got %T.", bin.Left)
+	}
+}
+
+func TestFprintUnparsedBody(t *testing.T) {
+	// The statements of function bodies are not yet parsed, and must not be
+	// printed as an empty body.
+	golden := []string{
+		"package p\n\nfunc (t *T) M(a ...int) (int, error) {\n\treturn 0, nil\n}\n",
+		"package p\n\nfunc f() {\n\t{\n\t}\n}\n",
+	}
+
+	for i, input := range golden {
+		tokens, err := lexer.Parse(input)
+		if err != nil {
+			t.Errorf("i=%d: lexer.Parse failed; %v", i, err)
+			continue
+		}
+		f, err := parser.ParseFile(tokens)
+		if err != nil {
+			t.Errorf("i=%d: parser.ParseFile failed; %v", i, err)
+			continue
+		}
+		want := "ast.Fprint: function body has not been parsed"
+		err = ast.Fprint(new(bytes.Buffer), f)
+		if err == nil || err.Error() != want {
+			t.Errorf("i=%d: error mismatch; expected %q, got %v.", i, want, err)
+		}
+	}
+}
+
+// equalTokens reports whether the token kinds and values of a and b are equal,
+// ignoring positions. The values of semicolons are ignored, as they may have
+// been inserted automatically.
+func equalTokens(a, b []token.Token) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Kind != b[i].Kind {
+			return false
+		}
+		if a[i].Kind != token.Semicolon && a[i].Val != b[i].Val {
+			return false
+		}
+	}
+	return true
+}
@@ -1,5 +1,10 @@
 package ast
 
+import (
+	"github.com/mewlang/go/token"
+	"github.com/mewlang/go/types"
+)
+
 // A Stmt controls execution.
 //
 //    Statement =
@@ -23,7 +28,327 @@ type Stmt interface {
 //
 // ref: http://golang.org/ref/spec#Statements
 type SimpleStmt interface {
+	Stmt
 	// isSimpleStmt ensures that only simple statement nodes can be assigned to
 	// the SimpleStmt interface.
 	isSimpleStmt()
 }
+
+// A BadStmt is a placeholder for a sequence of statements which has not been
+// parsed; e.g. the statement list of a function body, which is skipped by the
+// parser.
+type BadStmt struct {
+	// Tokens of the statements.
+	Tokens []token.Token
+}
+
+// An EmptyStmt does nothing.
+//
+//    EmptyStmt = .
+//
+// ref: http://golang.org/ref/spec#Empty_statements
+type EmptyStmt struct{}
+
+// A LabeledStmt may be the target of a goto, break or continue statement.
+//
+//    LabeledStmt = Label ":" Statement .
+//    Label       = identifier .
+//
+// ref: http://golang.org/ref/spec#Labeled_statements
+type LabeledStmt struct {
+	// Label name.
+	Label token.Token
+	// Labeled statement.
+	Stmt Stmt
+}
+
+// An ExprStmt is a function call, method call, or receive operation which
+// appears in statement context.
+//
+//    ExpressionStmt = Expression .
+//
+// ref: http://golang.org/ref/spec#Expression_statements
+type ExprStmt struct {
+	// Expression.
+	Expr Expr
+}
+
+// A SendStmt sends a value on a channel.
+//
+//    SendStmt = Channel "<-" Expression .
+//    Channel  = Expression .
+//
+// ref: http://golang.org/ref/spec#Send_statements
+type SendStmt struct {
+	// Channel expression.
+	Chan Expr
+	// Value expression.
+	Val Expr
+}
+
+// An IncDecStmt increments or decrements its operand by the untyped constant 1.
+//
+//    IncDecStmt = Expression ( "++" | "--" ) .
+//
+// ref: http://golang.org/ref/spec#IncDec_statements
+type IncDecStmt struct {
+	// Operand.
+	Expr Expr
+	// Increment (++) or decrement (--) operator.
+	Op token.Token
+}
+
+// An AssignStmt assigns the values of the right-hand side expressions to the
+// operands of the left-hand side.
+//
+//    Assignment = ExpressionList assign_op ExpressionList .
+//
+//    assign_op = [ add_op | mul_op ] "=" .
+//
+// ref: http://golang.org/ref/spec#Assignments
+type AssignStmt struct {
+	// Left-hand side operands.
+	Lhs []Expr
+	// Assignment operator.
+	Op token.Token
+	// Right-hand side expressions.
+	Rhs []Expr
+}
+
+// A ShortVarDecl is a shorthand for a regular variable declaration with
+// initializer expressions but no types.
+//
+//    ShortVarDecl = IdentifierList ":=" ExpressionList .
+//
+// ref: http://golang.org/ref/spec#Short_variable_declarations
+type ShortVarDecl struct {
+	// Variable names.
+	Names []token.Token
+	// Variable value expressions.
+	Vals []Expr
+}
+
+// A GoStmt starts the execution of a function call as an independent
+// concurrent thread of control, or goroutine, within the same address space.
+//
+//    GoStmt = "go" Expression .
+//
+// ref: http://golang.org/ref/spec#Go_statements
+type GoStmt struct {
+	// Function or method call.
+	Call CallExpr
+}
+
+// A DeferStmt invokes a function whose execution is deferred to the moment the
+// surrounding function returns.
+//
+//    DeferStmt = "defer" Expression .
+//
+// ref: http://golang.org/ref/spec#Defer_statements
+type DeferStmt struct {
+	// Function or method call.
+	Call CallExpr
+}
+
+// A ReturnStmt terminates the execution of the surrounding function, and
+// optionally provides one or more result values.
+//
+//    ReturnStmt = "return" [ ExpressionList ] .
+//
+// ref: http://golang.org/ref/spec#Return_statements
+type ReturnStmt struct {
+	// Result expressions, or nil.
+	Results []Expr
+}
+
+// A BranchStmt is a break, continue, goto or fallthrough statement.
+//
+//    BreakStmt       = "break" [ Label ] .
+//    ContinueStmt    = "continue" [ Label ] .
+//    GotoStmt        = "goto" Label .
+//    FallthroughStmt = "fallthrough" .
+//
+// ref: http://golang.org/ref/spec#Break_statements
+// ref: http://golang.org/ref/spec#Continue_statements
+// ref: http://golang.org/ref/spec#Goto_statements
+// ref: http://golang.org/ref/spec#Fallthrough_statements
+type BranchStmt struct {
+	// Keyword; break, continue, goto or fallthrough.
+	Tok token.Token
+	// Label name, or NONE.
+	Label token.Token
+}
+
+// An IfStmt specifies the conditional execution of two branches according to
+// the value of a boolean expression.
+//
+//    IfStmt = "if" [ SimpleStmt ";" ] Expression Block [ "else" ( IfStmt | Block ) ] .
+//
+// ref: http://golang.org/ref/spec#If_statements
+type IfStmt struct {
+	// Init statement, or nil.
+	Init SimpleStmt
+	// Condition.
+	Cond Expr
+	// Body of the if branch.
+	Body Block
+	// Else branch, or nil; holds an IfStmt or a Block.
+	Else Stmt
+}
+
+// A SwitchStmt provides multi-way execution by comparing the cases against the
+// value of the switch expression.
+//
+//    ExprSwitchStmt = "switch" [ SimpleStmt ";" ] [ Expression ] "{" { ExprCaseClause } "}" .
+//
+// ref: http://golang.org/ref/spec#Switch_statements
+type SwitchStmt struct {
+	// Init statement, or nil.
+	Init SimpleStmt
+	// Switch expression, or nil.
+	Tag Expr
+	// Case clauses.
+	Clauses []CaseClause
+}
+
+// A CaseClause is a case or default clause of an expression switch statement.
+//
+//    ExprCaseClause = ExprSwitchCase ":" StatementList .
+//    ExprSwitchCase = "case" ExpressionList | "default" .
+//
+// ref: http://golang.org/ref/spec#Expression_switches
+type CaseClause struct {
+	// Case expressions, or nil for the default case.
+	Exprs []Expr
+	// Clause body.
+	Body []Stmt
+}
+
+// A TypeSwitchStmt compares types rather than values.
+//
+//    TypeSwitchStmt  = "switch" [ SimpleStmt ";" ] TypeSwitchGuard "{" { TypeCaseClause } "}" .
+//    TypeSwitchGuard = [ identifier ":=" ] PrimaryExpr "." "(" "type" ")" .
+//
+// ref: http://golang.org/ref/spec#Type_switches
+type TypeSwitchStmt struct {
+	// Init statement, or nil.
+	Init SimpleStmt
+	// Name of the variable declared in the type switch guard, or NONE.
+	Name token.Token
+	// Interface expression of the type switch guard.
+	Expr PrimaryExpr
+	// Type case clauses.
+	Clauses []TypeCaseClause
+}
+
+// A TypeCaseClause is a case or default clause of a type switch statement.
+//
+//    TypeCaseClause  = TypeSwitchCase ":" StatementList .
+//    TypeSwitchCase  = "case" TypeList | "default" .
+//    TypeList        = Type { "," Type } .
+//
+// ref: http://golang.org/ref/spec#Type_switches
+type TypeCaseClause struct {
+	// Case types, or nil for the default case.
+	Types []types.Type
+	// Clause body.
+	Body []Stmt
+}
+
+// A SelectStmt chooses which of a set of possible send or receive operations
+// will proceed.
+//
+//    SelectStmt = "select" "{" { CommClause } "}" .
+//
+// ref: http://golang.org/ref/spec#Select_statements
+type SelectStmt struct {
+	// Communication clauses.
+	Clauses []CommClause
+}
+
+// A CommClause is a case or default clause of a select statement.
+//
+//    CommClause = CommCase ":" StatementList .
+//    CommCase   = "case" ( SendStmt | RecvStmt ) | "default" .
+//    RecvStmt   = [ ExpressionList "=" | IdentifierList ":=" ] RecvExpr .
+//    RecvExpr   = Expression .
+//
+// ref: http://golang.org/ref/spec#Select_statements
+type CommClause struct {
+	// Communication operation, or nil for the default case; holds a SendStmt,
+	// or a receive operation (an ExprStmt, an AssignStmt or a ShortVarDecl).
+	Comm SimpleStmt
+	// Clause body.
+	Body []Stmt
+}
+
+// A ForStmt specifies repeated execution of a block. The iteration may be
+// controlled by a condition, a for clause, or a range clause.
+//
+//    ForStmt   = "for" [ Condition | ForClause | RangeClause ] Block .
+//    Condition = Expression .
+//    ForClause = [ InitStmt ] ";" [ Condition ] ";" [ PostStmt ] .
+//    InitStmt  = SimpleStmt .
+//    PostStmt  = SimpleStmt .
+//
+// ref: http://golang.org/ref/spec#For_statements
+type ForStmt struct {
+	// Init statement, or nil.
+	Init SimpleStmt
+	// Condition, or nil.
+	Cond Expr
+	// Post statement, or nil.
+	Post SimpleStmt
+	// Range clause, or nil; mutually exclusive with Init, Cond and Post.
+	Range *RangeClause
+	// Loop body.
+	Body Block
+}
+
+// A RangeClause iterates through all entries of an array, slice, string or map,
+// or values received on a channel.
+//
+//    RangeClause = ( ExpressionList "=" | IdentifierList ":=" ) "range" Expression .
+//
+// ref: http://golang.org/ref/spec#For_statements
+type RangeClause struct {
+	// Iteration variables, or nil.
+	Lhs []Expr
+	// Assignment (=) or declare and initialize (:=) operator, or NONE.
+	Op token.Token
+	// Range expression.
+	Expr Expr
+}
+
+// isStmt ensures that only statement nodes can be assigned to the Stmt
+// interface.
+func (Block) isStmt()          {}
+func (BadStmt) isStmt()        {}
+func (ConstDecl) isStmt()      {}
+func (TypeDecl) isStmt()       {}
+func (VarDecl) isStmt()        {}
+func (EmptyStmt) isStmt()      {}
+func (LabeledStmt) isStmt()    {}
+func (ExprStmt) isStmt()       {}
+func (SendStmt) isStmt()       {}
+func (IncDecStmt) isStmt()     {}
+func (AssignStmt) isStmt()     {}
+func (ShortVarDecl) isStmt()   {}
+func (GoStmt) isStmt()         {}
+func (DeferStmt) isStmt()      {}
+func (ReturnStmt) isStmt()     {}
+func (BranchStmt) isStmt()     {}
+func (IfStmt) isStmt()         {}
+func (SwitchStmt) isStmt()     {}
+func (TypeSwitchStmt) isStmt() {}
+func (SelectStmt) isStmt()     {}
+func (ForStmt) isStmt()        {}
+
+// isSimpleStmt ensures that only simple statement nodes can be assigned to the
+// SimpleStmt interface.
+func (EmptyStmt) isSimpleStmt()    {}
+func (ExprStmt) isSimpleStmt()     {}
+func (SendStmt) isSimpleStmt()     {}
+func (IncDecStmt) isSimpleStmt()   {}
+func (AssignStmt) isSimpleStmt()   {}
+func (ShortVarDecl) isSimpleStmt() {}
@@ -0,0 +1,67 @@
+package ast
+
+import (
+	"github.com/mewlang/go/token"
+	"github.com/mewlang/go/types"
+)
+
+// A Symbol is an identifier declared at file scope.
+type Symbol struct {
+	// Declared identifier.
+	Name token.Token
+	// Keyword of the declaration; i.e. Const, Type, Var or Func.
+	Kind token.Kind
+	// Receiver base type name of methods, or NONE.
+	Recv token.Token
+}
+
+// Pos returns the position of the declared identifier.
+func (sym Symbol) Pos() token.Position { return sym.Name.Pos() }
+
+// TopLevelSymbols returns the identifiers declared at file scope by the
+// top-level declarations of f, in order of declaration. The blank identifier
+// is included, as are methods; which are attributed to their receiver base type
+// through the Recv field of the symbol.
+func TopLevelSymbols(f *File) []Symbol {
+	var syms []Symbol
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case ConstDecl:
+			syms = appendSpecSymbols(syms, decl, token.Const)
+		case VarDecl:
+			syms = appendSpecSymbols(syms, decl, token.Var)
+		case TypeDecl:
+			for _, name := range decl {
+				syms = append(syms, Symbol{Name: name.Name, Kind: token.Type})
+			}
+		case FuncDecl:
+			syms = append(syms, Symbol{Name: decl.Name, Kind: token.Func})
+		case MethodDecl:
+			syms = append(syms, Symbol{Name: decl.Name, Kind: token.Func, Recv: recvName(decl.Receiver.Type)})
+		}
+	}
+	return syms
+}
+
+// appendSpecSymbols appends the names of the provided constant or variable
+// specifiers to syms.
+func appendSpecSymbols(syms []Symbol, specs []ValueSpec, kind token.Kind) []Symbol {
+	for _, spec := range specs {
+		for _, name := range spec.Names {
+			syms = append(syms, Symbol{Name: name, Kind: kind})
+		}
+	}
+	return syms
+}
+
+// recvName returns the base type name of the provided receiver type; i.e. T of
+// the receiver types T and *T.
+func recvName(t types.Type) token.Token {
+	if ptr, ok := t.(types.Pointer); ok {
+		t = ptr.Base
+	}
+	if name, ok := t.(types.Name); ok {
+		return name.Name
+	}
+	return token.Token{}
+}
@@ -0,0 +1,61 @@
+package ast_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mewlang/go/ast"
+	"github.com/mewlang/go/lexer"
+	"github.com/mewlang/go/parser"
+	"github.com/mewlang/go/token"
+)
+
+func TestTopLevelSymbols(t *testing.T) {
+	const input = `package p
+
+const (
+	A, b = 1, 2
+)
+
+var x int
+
+type (
+	T struct{}
+	u int
+)
+
+func F() {}
+
+func (t T) M() {}
+
+func (p *u) m() {}
+`
+	tokens, err := lexer.Parse(input)
+	if err != nil {
+		t.Fatalf("unexpected error; %v", err)
+	}
+	f, err := parser.ParseFile(tokens)
+	if err != nil {
+		t.Fatalf("unexpected error; %v", err)
+	}
+	ident := func(val string, line, col, offset int) token.Token {
+		return token.Token{Kind: token.Ident, Val: val, Line: line, Col: col, Offset: offset}
+	}
+	want := []ast.Symbol{
+		{Name: ident("A", 4, 2, 20), Kind: token.Const},
+		{Name: ident("b", 4, 5, 23), Kind: token.Const},
+		{Name: ident("x", 7, 5, 39), Kind: token.Var},
+		{Name: ident("T", 10, 2, 54), Kind: token.Type},
+		{Name: ident("u", 11, 2, 66), Kind: token.Type},
+		{Name: ident("F", 14, 6, 80), Kind: token.Func},
+		{Name: ident("M", 16, 12, 99), Kind: token.Func, Recv: ident("T", 16, 9, 96)},
+		{Name: ident("m", 18, 13, 119), Kind: token.Func, Recv: ident("u", 18, 10, 116)},
+	}
+	got := ast.TopLevelSymbols(f)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("symbols mismatch; expected %v, got %v.", want, got)
+	}
+	if got, want := got[6].Pos(), (token.Position{Line: 16, Col: 12, Offset: 99}); got != want {
+		t.Errorf("position mismatch; expected %v, got %v.", want, got)
+	}
+}
@@ -0,0 +1,362 @@
+package ast
+
+import (
+	"fmt"
+
+	"github.com/mewlang/go/token"
+	"github.com/mewlang/go/types"
+)
+
+// A Visitor's Visit method is invoked for each node encountered by Walk. If the
+// result visitor w is not nil, Walk visits each of the children of node with
+// the visitor w, followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node interface{}) (w Visitor)
+}
+
+// Walk traverses an abstract syntax tree in depth-first order. It starts by
+// calling v.Visit(node); node must not be nil. If the visitor w returned by
+// v.Visit(node) is not nil, Walk is invoked recursively with visitor w for each
+// of the non-nil children of node, followed by a call of w.Visit(nil).
+//
+// The nodes of the tree include the nodes of the ast package, the types of the
+// types package, and the tokens (token.Token) of the tree; tokens of kind NONE
+// are not visited.
+func Walk(v Visitor, node interface{}) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	// Tokens and operands.
+	case token.Token, BasicLit, OperandName, types.Basic:
+		// Nothing to do.
+
+	// Source files.
+	case Package:
+		for _, f := range n.Files {
+			Walk(v, f)
+		}
+	case *File:
+		walkFile(v, n)
+	case File:
+		walkFile(v, &n)
+
+	// Declarations.
+	case ImportDecl:
+		for _, spec := range n {
+			Walk(v, spec)
+		}
+	case ImportSpec:
+		walkToken(v, n.Name)
+		walkToken(v, n.Path)
+	case ConstDecl:
+		for _, spec := range n {
+			Walk(v, spec)
+		}
+	case VarDecl:
+		for _, spec := range n {
+			Walk(v, spec)
+		}
+	case ValueSpec:
+		walkTokens(v, n.Names)
+		if n.Type != nil {
+			Walk(v, n.Type)
+		}
+		walkExprs(v, n.Vals)
+	case TypeDecl:
+		for _, spec := range n {
+			Walk(v, spec)
+		}
+	case FuncDecl:
+		walkToken(v, n.Name)
+		Walk(v, n.Sig)
+		if n.Body != nil {
+			Walk(v, n.Body)
+		}
+	case MethodDecl:
+		Walk(v, n.Receiver)
+		walkToken(v, n.Name)
+		Walk(v, n.Sig)
+		if n.Body != nil {
+			Walk(v, n.Body)
+		}
+
+	// Expressions.
+	case UnaryExpr:
+		walkToken(v, n.Op)
+		Walk(v, n.Expr)
+	case BinaryExpr:
+		Walk(v, n.Left)
+		walkToken(v, n.Op)
+		Walk(v, n.Right)
+	case Conversion:
+		Walk(v, n.Type)
+		Walk(v, n.Expr)
+	case CallExpr:
+		Walk(v, n.Func)
+		for _, arg := range n.Args {
+			Walk(v, arg)
+		}
+	case SelectorExpr:
+		Walk(v, n.Expr)
+		walkToken(v, n.Selector)
+	case IndexExpr:
+		Walk(v, n.Expr)
+		Walk(v, n.Index)
+	case SliceExpr:
+		Walk(v, n.Expr)
+		if n.Low != nil {
+			Walk(v, n.Low)
+		}
+		if n.High != nil {
+			Walk(v, n.High)
+		}
+		if n.Cap != nil {
+			Walk(v, n.Cap)
+		}
+	case TypeAssertion:
+		Walk(v, n.Expr)
+		if n.Type != nil {
+			Walk(v, n.Type)
+		}
+	case CompositeLit:
+		if n.Type != nil {
+			Walk(v, n.Type)
+		}
+		for _, elem := range n.Vals {
+			Walk(v, elem)
+		}
+	case CompositeElement:
+		if n.Key != nil {
+			Walk(v, n.Key)
+		}
+		Walk(v, n.Val)
+	case []CompositeElement:
+		for _, elem := range n {
+			Walk(v, elem)
+		}
+	case FuncLit:
+		Walk(v, n.Sig)
+		if n.Body != nil {
+			Walk(v, n.Body)
+		}
+	case MethodExpr:
+		Walk(v, n.ReceiverType)
+		walkToken(v, n.Name)
+	case ParenExpr:
+		Walk(v, n.Expr)
+
+	// Statements.
+	case Block:
+		walkStmts(v, n)
+	case BadStmt:
+		walkTokens(v, n.Tokens)
+	case EmptyStmt:
+		// Nothing to do.
+	case LabeledStmt:
+		walkToken(v, n.Label)
+		Walk(v, n.Stmt)
+	case ExprStmt:
+		Walk(v, n.Expr)
+	case SendStmt:
+		Walk(v, n.Chan)
+		Walk(v, n.Val)
+	case IncDecStmt:
+		Walk(v, n.Expr)
+		walkToken(v, n.Op)
+	case AssignStmt:
+		walkExprs(v, n.Lhs)
+		walkToken(v, n.Op)
+		walkExprs(v, n.Rhs)
+	case ShortVarDecl:
+		walkTokens(v, n.Names)
+		walkExprs(v, n.Vals)
+	case GoStmt:
+		Walk(v, n.Call)
+	case DeferStmt:
+		Walk(v, n.Call)
+	case ReturnStmt:
+		walkExprs(v, n.Results)
+	case BranchStmt:
+		walkToken(v, n.Tok)
+		walkToken(v, n.Label)
+	case IfStmt:
+		if n.Init != nil {
+			Walk(v, n.Init)
+		}
+		Walk(v, n.Cond)
+		Walk(v, n.Body)
+		if n.Else != nil {
+			Walk(v, n.Else)
+		}
+	case SwitchStmt:
+		if n.Init != nil {
+			Walk(v, n.Init)
+		}
+		if n.Tag != nil {
+			Walk(v, n.Tag)
+		}
+		for _, clause := range n.Clauses {
+			Walk(v, clause)
+		}
+	case CaseClause:
+		walkExprs(v, n.Exprs)
+		walkStmts(v, n.Body)
+	case TypeSwitchStmt:
+		if n.Init != nil {
+			Walk(v, n.Init)
+		}
+		walkToken(v, n.Name)
+		Walk(v, n.Expr)
+		for _, clause := range n.Clauses {
+			Walk(v, clause)
+		}
+	case TypeCaseClause:
+		for _, typ := range n.Types {
+			Walk(v, typ)
+		}
+		walkStmts(v, n.Body)
+	case SelectStmt:
+		for _, clause := range n.Clauses {
+			Walk(v, clause)
+		}
+	case CommClause:
+		if n.Comm != nil {
+			Walk(v, n.Comm)
+		}
+		walkStmts(v, n.Body)
+	case ForStmt:
+		if n.Init != nil {
+			Walk(v, n.Init)
+		}
+		if n.Cond != nil {
+			Walk(v, n.Cond)
+		}
+		if n.Post != nil {
+			Walk(v, n.Post)
+		}
+		if n.Range != nil {
+			Walk(v, *n.Range)
+		}
+		Walk(v, n.Body)
+	case RangeClause:
+		walkExprs(v, n.Lhs)
+		walkToken(v, n.Op)
+		Walk(v, n.Expr)
+
+	// Types.
+	case types.Name:
+		walkToken(v, n.Pkg)
+		walkToken(v, n.Name)
+		if n.Type != nil {
+			Walk(v, n.Type)
+		}
+	case types.Array:
+		Walk(v, n.Len)
+		Walk(v, n.Elem)
+	case types.Struct:
+		for _, field := range n {
+			Walk(v, field)
+		}
+	case types.Field:
+		walkTokens(v, n.Names)
+		Walk(v, n.Type)
+		walkToken(v, n.Tag)
+	case types.Pointer:
+		Walk(v, n.Base)
+	case types.Func:
+		for _, param := range n.Params {
+			Walk(v, param)
+		}
+		for _, result := range n.Results {
+			Walk(v, result)
+		}
+	case types.Parameter:
+		walkTokens(v, n.Names)
+		Walk(v, n.Type)
+	case types.Interface:
+		for _, method := range n {
+			Walk(v, method)
+		}
+	case types.Method:
+		walkToken(v, n.Pkg)
+		walkToken(v, n.Name)
+		if n.Sig != nil {
+			Walk(v, *n.Sig)
+		}
+	case types.Slice:
+		Walk(v, n.Elem)
+	case types.Map:
+		Walk(v, n.Key)
+		Walk(v, n.Elem)
+	case types.Chan:
+		Walk(v, n.Elem)
+
+	default:
+		panic(fmt.Sprintf("ast.Walk: unexpected node type %T", n))
+	}
+
+	v.Visit(nil)
+}
+
+// walkFile walks the package name, import declarations and top level
+// declarations of the provided source file.
+func walkFile(v Visitor, f *File) {
+	walkToken(v, f.PkgName)
+	for _, imp := range f.Imps {
+		Walk(v, imp)
+	}
+	for _, decl := range f.Decls {
+		Walk(v, decl)
+	}
+}
+
+// walkToken walks the provided token, unless it is of kind NONE.
+func walkToken(v Visitor, tok token.Token) {
+	if tok.Kind != token.None {
+		Walk(v, tok)
+	}
+}
+
+// walkTokens walks each of the provided tokens.
+func walkTokens(v Visitor, toks []token.Token) {
+	for _, tok := range toks {
+		walkToken(v, tok)
+	}
+}
+
+// walkExprs walks each of the provided expressions.
+func walkExprs(v Visitor, exprs []Expr) {
+	for _, x := range exprs {
+		Walk(v, x)
+	}
+}
+
+// walkStmts walks each of the provided statements.
+func walkStmts(v Visitor, stmts []Stmt) {
+	for _, stmt := range stmts {
+		Walk(v, stmt)
+	}
+}
+
+// An inspector implements the Visitor interface by invoking itself for each
+// node, and descending into the children of the node if it returns true.
+type inspector func(node interface{}) bool
+
+// Visit invokes f on node, and returns f if it should descend into the children
+// of node, and nil otherwise.
+func (f inspector) Visit(node interface{}) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses an abstract syntax tree in depth-first order. It starts by
+// calling f(node); node must not be nil. If f returns true, Inspect invokes f
+// recursively for each of the non-nil children of node, followed by a call of
+// f(nil).
+func Inspect(node interface{}, f func(node interface{}) bool) {
+	Walk(inspector(f), node)
+}
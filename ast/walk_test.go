@@ -0,0 +1,101 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/mewlang/go/ast"
+	"github.com/mewlang/go/lexer"
+	"github.com/mewlang/go/parser"
+	"github.com/mewlang/go/token"
+)
+
+func TestInspect(t *testing.T) {
+	const input = `package p
+
+import "fmt"
+
+const A, B = 1, A + 2
+
+var x = fmt.Sprint(A * (B - y[1:2]))
+
+type T struct {
+	X, Y int
+	*fmt.Stringer
+}
+
+func (t T) Len(s ...string) int
+`
+	tokens, err := lexer.Parse(input)
+	if err != nil {
+		t.Fatalf("lexer.Parse failed; %v", err)
+	}
+	f, err := parser.ParseFile(tokens)
+	if err != nil {
+		t.Fatalf("parser.ParseFile failed; %v", err)
+	}
+
+	// Count identifier tokens, and operands which denote identifiers.
+	var idents []string
+	ast.Inspect(f, func(node interface{}) bool {
+		switch n := node.(type) {
+		case token.Token:
+			if n.Kind == token.Ident {
+				idents = append(idents, n.Val)
+			}
+		case ast.OperandName:
+			idents = append(idents, n.Val)
+		}
+		return true
+	})
+	want := []string{
+		"p",
+		"A", "B", "A",
+		"x", "fmt", "Sprint", "A", "B", "y",
+		"T", "X", "Y", "int", "fmt", "Stringer",
+		"t", "T", "Len", "s", "string", "int",
+	}
+	if len(idents) != len(want) {
+		t.Fatalf("identifier count mismatch; expected %d, got %d (%q).", len(want), len(idents), idents)
+	}
+	for i := range want {
+		if idents[i] != want[i] {
+			t.Errorf("i=%d: identifier mismatch; expected %q, got %q.", i, want[i], idents[i])
+		}
+	}
+}
+
+// countVisitor counts the number of visited nodes, and stops descending at
+// function declarations.
+type countVisitor struct {
+	// Number of visited nodes, excluding the final nil node of each traversed
+	// subtree.
+	n int
+}
+
+func (v *countVisitor) Visit(node interface{}) ast.Visitor {
+	if node == nil {
+		return nil
+	}
+	v.n++
+	if _, ok := node.(ast.FuncDecl); ok {
+		return nil
+	}
+	return v
+}
+
+func TestWalk(t *testing.T) {
+	tokens, err := lexer.Parse("package p; func f(a, b int) { return a + b }")
+	if err != nil {
+		t.Fatalf("lexer.Parse failed; %v", err)
+	}
+	f, err := parser.ParseFile(tokens)
+	if err != nil {
+		t.Fatalf("parser.ParseFile failed; %v", err)
+	}
+	v := new(countVisitor)
+	ast.Walk(v, f)
+	// The file, the package name and the function declaration.
+	if want := 3; v.n != want {
+		t.Errorf("node count mismatch; expected %d, got %d.", want, v.n)
+	}
+}
@@ -1,41 +1,235 @@
 // lex is a tool which tokenizes the contents of the provided files.
+//
+// Usage:
+//
+//    lex [OPTION]... [FILE]...
+//
+// With no FILE, or when FILE is -, read standard input.
+//
+// Flags:
+//
+//    -count
+//          Print a histogram of token kinds instead of each token.
+//    -json
+//          Print the tokens as a JSON array, or as a JSON array of objects
+//          with "path" and "tokens" members, in the order of the provided
+//          files, if multiple files are provided.
+//    -only=KIND,...
+//          Only output tokens of the provided comma-separated token kinds; e.g.
+//          -only=identifier,comment. Lexically invalid tokens are also matched
+//          by their invalid token kind; e.g. -only="<invalid> rune literal".
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"log"
+	"os"
+	"sort"
+	"strings"
 
 	"github.com/mewlang/go/lexer"
+	"github.com/mewlang/go/token"
 )
 
+var (
+	// flagCount specifies whether to print a histogram of token kinds for each
+	// file, instead of printing each token.
+	flagCount bool
+	// flagJSON specifies whether to print the tokens in JSON format.
+	flagJSON bool
+	// flagOnly specifies a comma-separated list of token kinds to output.
+	flagOnly string
+)
+
+func init() {
+	flag.BoolVar(&flagCount, "count", false, "Print a histogram of token kinds instead of each token.")
+	flag.BoolVar(&flagJSON, "json", false, "Print the tokens in JSON format.")
+	flag.StringVar(&flagOnly, "only", "", "Only output tokens of the provided comma-separated token kinds.")
+}
+
 func main() {
 	flag.Parse()
-	for _, path := range flag.Args() {
-		err := lex(path)
+	if flagCount && flagJSON {
+		log.Fatalln("the -count and -json flags are mutually exclusive")
+	}
+	var only map[token.Kind]bool
+	if len(flagOnly) > 0 {
+		var err error
+		only, err = parseKinds(flagOnly)
 		if err != nil {
 			log.Fatalln(err)
 		}
 	}
+	paths := flag.Args()
+	if len(paths) == 0 {
+		// Read from standard input.
+		paths = []string{"-"}
+	}
+	var files [][]token.Token
+	total := make(histogram)
+	for _, path := range paths {
+		tokens, err := lex(path)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if only != nil {
+			tokens = filter(tokens, only)
+		}
+		switch {
+		case flagJSON:
+			files = append(files, tokens)
+		case flagCount:
+			h := make(histogram)
+			h.add(tokens)
+			total.add(tokens)
+			fmt.Printf("%s: %v\n", path, h)
+		default:
+			for _, token := range tokens {
+				fmt.Println("token:", token)
+			}
+		}
+	}
+	switch {
+	case flagJSON:
+		err := writeJSON(os.Stdout, paths, files)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	case flagCount:
+		fmt.Printf("total: %v\n", total)
+	}
 }
 
-// lex tokenizes the contents of the provided file.
-func lex(path string) error {
-	log.Println("Lexing:", path)
-	buf, err := ioutil.ReadFile(path)
-	if err != nil {
-		return err
+// lex tokenizes the contents of the provided file, and stamps each token with
+// the file path. The path "-" denotes standard input.
+func lex(path string) ([]token.Token, error) {
+	name, r := "<stdin>", io.Reader(os.Stdin)
+	if path != "-" {
+		name = path
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
 	}
-	input := string(buf)
-
-	tokens, err := lexer.Parse(input)
+	log.Println("Lexing:", name)
+	tokens, err := lexer.ParseFileReader(name, r)
 	if err != nil {
+		if _, ok := err.(lexer.ErrorList); !ok {
+			return nil, err
+		}
 		log.Println(err)
 	}
-	for _, token := range tokens {
-		fmt.Println("token:", token)
+	return tokens, nil
+}
+
+// parseKinds parses the provided comma-separated list of token kind names into a
+// set of token kinds.
+func parseKinds(s string) (map[token.Kind]bool, error) {
+	kinds := make(map[token.Kind]bool)
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		kind, ok := token.KindFromName(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown token kind %q in -only flag", name)
+		}
+		kinds[kind] = true
 	}
+	return kinds, nil
+}
 
-	return nil
+// filter returns the tokens whose kinds are in the provided set of token kinds.
+// Lexically invalid tokens are matched both by their invalid and by their valid
+// token kind.
+func filter(tokens []token.Token, kinds map[token.Kind]bool) []token.Token {
+	var filtered []token.Token
+	for _, tok := range tokens {
+		if kinds[tok.Kind] || kinds[tok.Kind&^token.Invalid] {
+			filtered = append(filtered, tok)
+		}
+	}
+	return filtered
+}
+
+// writeJSON writes the tokens of each file to w in JSON format. The tokens of a
+// single file are written as a JSON array, and the tokens of multiple files are
+// written as a JSON array of jsonFile objects, in the order of the files; the
+// same path may thus occur more than once.
+func writeJSON(w io.Writer, paths []string, files [][]token.Token) error {
+	var v interface{}
+	if len(files) == 1 {
+		v = nonNil(files[0])
+	} else {
+		fs := make([]jsonFile, len(files))
+		for i, path := range paths {
+			fs[i] = jsonFile{Path: path, Tokens: nonNil(files[i])}
+		}
+		v = fs
+	}
+	return json.NewEncoder(w).Encode(v)
+}
+
+// A jsonFile is the JSON representation of the tokens of a file.
+type jsonFile struct {
+	// File path.
+	Path string `json:"path"`
+	// Tokens of the file.
+	Tokens []token.Token `json:"tokens"`
+}
+
+// nonNil returns the provided tokens, or an empty slice if tokens is nil; files
+// without tokens are thus encoded as empty JSON arrays rather than null.
+func nonNil(tokens []token.Token) []token.Token {
+	if tokens == nil {
+		return []token.Token{}
+	}
+	return tokens
+}
+
+// A histogram records the number of occurrences of each token kind.
+type histogram map[token.Kind]int
+
+// add records the token kind of each of the provided tokens.
+func (h histogram) add(tokens []token.Token) {
+	for _, tok := range tokens {
+		h[tok.Kind]++
+	}
+}
+
+// String returns a comma-separated list of the token kinds of the histogram and
+// their number of occurrences, sorted by descending count; e.g.
+//
+//    identifier: 42, int literal: 7, ;: 3
+func (h histogram) String() string {
+	var kinds []token.Kind
+	for kind := range h {
+		kinds = append(kinds, kind)
+	}
+	sort.Sort(byCount{kinds: kinds, h: h})
+	var entries []string
+	for _, kind := range kinds {
+		entries = append(entries, fmt.Sprintf("%v: %d", kind, h[kind]))
+	}
+	return strings.Join(entries, ", ")
+}
+
+// byCount implements sort.Interface, sorting token kinds by descending number of
+// occurrences in the histogram, and by ascending token kind for equal counts.
+type byCount struct {
+	kinds []token.Kind
+	h     histogram
+}
+
+func (s byCount) Len() int      { return len(s.kinds) }
+func (s byCount) Swap(i, j int) { s.kinds[i], s.kinds[j] = s.kinds[j], s.kinds[i] }
+func (s byCount) Less(i, j int) bool {
+	ci, cj := s.h[s.kinds[i]], s.h[s.kinds[j]]
+	if ci != cj {
+		return ci > cj
+	}
+	return s.kinds[i] < s.kinds[j]
 }
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mewlang/go/token"
+)
+
+func TestWriteJSON(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	srcs := map[string]string{
+		"a.go": "package a\n",
+		"b.go": "package b\n\nvar x = 42\n",
+	}
+	var paths []string
+	var files [][]token.Token
+	for _, name := range []string{"a.go", "b.go"} {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(srcs[name]), 0644); err != nil {
+			t.Fatal(err)
+		}
+		tokens, err := lex(path)
+		if err != nil {
+			t.Fatalf("lex failed; %v", err)
+		}
+		paths = append(paths, path)
+		files = append(files, tokens)
+	}
+
+	// Single file.
+	buf := new(bytes.Buffer)
+	if err := writeJSON(buf, paths[:1], files[:1]); err != nil {
+		t.Fatalf("writeJSON failed; %v", err)
+	}
+	var got []token.Token
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal failed; %v", err)
+	}
+	if len(got) < 2 {
+		t.Fatalf("token count mismatch; expected >= 2, got %d.", len(got))
+	}
+	if want := (token.Token{Kind: token.Package, Val: "package", Line: 1, Col: 1, Filename: paths[0]}); got[0] != want {
+		t.Errorf("token mismatch; expected %#v, got %#v.", want, got[0])
+	}
+	if want := (token.Token{Kind: token.Ident, Val: "a", Line: 1, Col: 9, Offset: 8, Filename: paths[0]}); got[1] != want {
+		t.Errorf("token mismatch; expected %#v, got %#v.", want, got[1])
+	}
+
+	// Multiple files, in the order provided and including duplicate paths.
+	buf.Reset()
+	order := []int{1, 0, 1}
+	var ps []string
+	var fs [][]token.Token
+	for _, i := range order {
+		ps = append(ps, paths[i])
+		fs = append(fs, files[i])
+	}
+	if err := writeJSON(buf, ps, fs); err != nil {
+		t.Fatalf("writeJSON failed; %v", err)
+	}
+	var out []jsonFile
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("json.Unmarshal failed; %v", err)
+	}
+	if len(out) != len(order) {
+		t.Fatalf("file count mismatch; expected %d, got %d.", len(order), len(out))
+	}
+	for i, f := range out {
+		if f.Path != ps[i] {
+			t.Errorf("i=%d: path mismatch; expected %q, got %q.", i, ps[i], f.Path)
+		}
+		if len(f.Tokens) != len(fs[i]) {
+			t.Errorf("i=%d: token count mismatch; expected %d, got %d.", i, len(fs[i]), len(f.Tokens))
+		}
+	}
+	var found bool
+	for _, tok := range out[0].Tokens {
+		if tok.Kind == token.Int {
+			found = true
+			if want := (token.Token{Kind: token.Int, Val: "42", Line: 3, Col: 9, Offset: 19, Filename: paths[1]}); tok != want {
+				t.Errorf("token mismatch; expected %#v, got %#v.", want, tok)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("unable to locate int literal in tokens of %q.", paths[1])
+	}
+
+	// Files without tokens, e.g. empty files or files without tokens of the
+	// kinds of the -only flag.
+	empty := filter(files[0], map[token.Kind]bool{token.Comment: true})
+	golden := []struct {
+		files [][]token.Token
+		want  string
+	}{
+		{files: [][]token.Token{nil}, want: "[]\n"},
+		{files: [][]token.Token{empty}, want: "[]\n"},
+		{files: [][]token.Token{nil, empty}, want: `[{"path":"a.go","tokens":[]},{"path":"b.go","tokens":[]}]` + "\n"},
+	}
+	for i, g := range golden {
+		buf.Reset()
+		if err := writeJSON(buf, []string{"a.go", "b.go"}, g.files); err != nil {
+			t.Errorf("i=%d: writeJSON failed; %v", i, err)
+			continue
+		}
+		if got := buf.String(); got != g.want {
+			t.Errorf("i=%d: output mismatch; expected %q, got %q.", i, g.want, got)
+		}
+	}
+}
+
+func TestParseKinds(t *testing.T) {
+	kinds, err := parseKinds("identifier, comment,string literal")
+	if err != nil {
+		t.Fatalf("parseKinds failed; %v", err)
+	}
+	want := map[token.Kind]bool{token.Ident: true, token.Comment: true, token.String: true}
+	if len(kinds) != len(want) {
+		t.Fatalf("kind count mismatch; expected %d, got %d.", len(want), len(kinds))
+	}
+	for kind := range want {
+		if !kinds[kind] {
+			t.Errorf("missing token kind %v.", kind)
+		}
+	}
+
+	kinds, err = parseKinds("<invalid> string literal")
+	if err != nil {
+		t.Fatalf("parseKinds failed; %v", err)
+	}
+	if len(kinds) != 1 || !kinds[token.String|token.Invalid] {
+		t.Errorf("kinds mismatch; expected %v, got %v.", map[token.Kind]bool{token.String | token.Invalid: true}, kinds)
+	}
+
+	if _, err := parseKinds("identifier,foo"); err == nil {
+		t.Errorf("expected error for unknown token kind, got nil")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	tokens := []token.Token{
+		{Kind: token.Package, Val: "package", Line: 1, Col: 1},
+		{Kind: token.Ident, Val: "p", Line: 1, Col: 9},
+		{Kind: token.Semicolon, Val: "\n", Line: 1, Col: 10},
+		{Kind: token.String | token.Invalid, Val: `"foo`, Line: 2, Col: 1},
+	}
+	golden := []struct {
+		kinds map[token.Kind]bool
+		want  []token.Token
+	}{
+		{kinds: map[token.Kind]bool{token.Ident: true, token.String: true}, want: []token.Token{tokens[1], tokens[3]}},
+		{kinds: map[token.Kind]bool{token.String | token.Invalid: true}, want: []token.Token{tokens[3]}},
+		{kinds: map[token.Kind]bool{token.Ident | token.Invalid: true}, want: nil},
+	}
+	for i, g := range golden {
+		got := filter(tokens, g.kinds)
+		if len(got) != len(g.want) {
+			t.Errorf("i=%d: token count mismatch; expected %d, got %d.", i, len(g.want), len(got))
+			continue
+		}
+		for j := range g.want {
+			if got[j] != g.want[j] {
+				t.Errorf("i=%d: token mismatch; expected %#v, got %#v.", i, g.want[j], got[j])
+			}
+		}
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	golden := []struct {
+		tokens [][]token.Token
+		counts map[token.Kind]int
+		want   string
+	}{
+		{
+			tokens: nil,
+			counts: map[token.Kind]int{},
+			want:   "",
+		},
+		// Sorted by descending count.
+		{
+			tokens: [][]token.Token{{
+				{Kind: token.Var, Val: "var"},
+				{Kind: token.Ident, Val: "x"},
+				{Kind: token.Assign, Val: "="},
+				{Kind: token.Ident, Val: "y"},
+				{Kind: token.Semicolon, Val: "\n"},
+				{Kind: token.Ident, Val: "z"},
+			}},
+			counts: map[token.Kind]int{token.Var: 1, token.Ident: 3, token.Assign: 1, token.Semicolon: 1},
+			want:   `identifier: 3, var: 1, =: 1, ;: 1`,
+		},
+		// Ties are sorted by token kind, and the counts of multiple files are
+		// accumulated. Lexically invalid tokens are counted separately.
+		{
+			tokens: [][]token.Token{
+				{
+					{Kind: token.String, Val: `"a"`},
+					{Kind: token.Int, Val: "1"},
+				},
+				{
+					{Kind: token.String | token.Invalid, Val: `"b`},
+					{Kind: token.Int, Val: "2"},
+					{Kind: token.String, Val: `"c"`},
+				},
+			},
+			counts: map[token.Kind]int{token.Int: 2, token.String: 2, token.String | token.Invalid: 1},
+			want:   `int literal: 2, string literal: 2, <invalid> string literal: 1`,
+		},
+	}
+
+	for i, g := range golden {
+		h := make(histogram)
+		for _, tokens := range g.tokens {
+			h.add(tokens)
+		}
+		if len(h) != len(g.counts) {
+			t.Errorf("i=%d: kind count mismatch; expected %d, got %d.", i, len(g.counts), len(h))
+		}
+		for kind, want := range g.counts {
+			if got := h[kind]; got != want {
+				t.Errorf("i=%d: count mismatch for token kind %v; expected %d, got %d.", i, kind, want, got)
+			}
+		}
+		if got := h.String(); got != g.want {
+			t.Errorf("i=%d: histogram mismatch; expected %q, got %q.", i, g.want, got)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package lexer
+
+import (
+	"fmt"
+
+	"github.com/mewlang/go/token"
+)
+
+// CheckBalance verifies that the parentheses, brackets and braces of the
+// provided token slice are balanced and properly nested. It returns an error
+// positioned at the first unexpected closing delimiter, or at the first
+// unclosed opening delimiter; e.g.
+//
+//    3:5: unexpected ')', expected '}'
+//
+// Comments contain no delimiter tokens and are thus ignored. CheckBalance is
+// a cheap sanity check which may be performed prior to parsing.
+func CheckBalance(tokens []token.Token) error {
+	// Stack of unclosed opening delimiters.
+	var open []token.Token
+	for _, tok := range tokens {
+		switch {
+		case tok.Kind.IsOpenDelim():
+			open = append(open, tok)
+		case tok.Kind.IsCloseDelim():
+			if len(open) == 0 {
+				return fmt.Errorf("%v: unexpected '%s'", tok.Pos(), tok.Val)
+			}
+			top := open[len(open)-1]
+			if want, _ := top.Kind.MatchingDelim(); tok.Kind != want {
+				return fmt.Errorf("%v: unexpected '%s', expected '%v'", tok.Pos(), tok.Val, want)
+			}
+			open = open[:len(open)-1]
+		}
+	}
+	if len(open) > 0 {
+		tok := open[len(open)-1]
+		return fmt.Errorf("%v: unclosed '%s'", tok.Pos(), tok.Val)
+	}
+	return nil
+}
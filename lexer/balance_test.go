@@ -0,0 +1,41 @@
+package lexer
+
+import "testing"
+
+func TestCheckBalance(t *testing.T) {
+	golden := []struct {
+		in  string
+		err string
+	}{
+		// Balanced input.
+		{in: "", err: ""},
+		{in: "f(a[i], map[string]int{})", err: ""},
+		{in: "func f() {\n\tx := []int{1, 2}\n\t_ = (x[0])\n}\n", err: ""},
+		{in: "f(/* ) */ a) // }", err: ""},
+		{in: "s := \"([{\"", err: ""},
+		// Extra closing delimiter.
+		{in: "f(a))", err: "1:5: unexpected ')'"},
+		{in: "}", err: "1:1: unexpected '}'"},
+		// Crossed pair.
+		{in: "func f() {\n\tg(\n\t\t}\n\t)\n", err: "3:3: unexpected '}', expected ')'"},
+		{in: "{\n\tx[(\n\t\t1])\n}", err: "3:4: unexpected ']', expected ')'"},
+		// Unclosed opening delimiter.
+		{in: "func f() {\n\tg(a)\n", err: "1:10: unclosed '{'"},
+		{in: "a[(b)", err: "1:2: unclosed '['"},
+	}
+
+	for i, g := range golden {
+		tokens, err := Parse(g.in)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		var got string
+		if err := CheckBalance(tokens); err != nil {
+			got = err.Error()
+		}
+		if got != g.err {
+			t.Errorf("i=%d: error mismatch; expected %q, got %q.", i, g.err, got)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package lexer
+
+import (
+	"strings"
+
+	"github.com/mewlang/go/token"
+)
+
+// GroupComments groups the comment tokens of the provided token slice into
+// comment groups. A comment group is a sequence of comment tokens with no other
+// tokens and no empty lines between them; e.g. the line comments of a doc
+// comment.
+func GroupComments(tokens []token.Token) [][]token.Token {
+	var groups [][]token.Token
+	var group []token.Token
+	// End line of the last comment of the current group.
+	var end int
+	for _, tok := range tokens {
+		if tok.Kind&^token.Invalid != token.Comment {
+			if group != nil {
+				groups = append(groups, group)
+				group = nil
+			}
+			continue
+		}
+		if group != nil && tok.Line > end+1 {
+			// Empty line between comments.
+			groups = append(groups, group)
+			group = nil
+		}
+		group = append(group, tok)
+		end = tok.Line + strings.Count(tok.Val, "\n")
+	}
+	if group != nil {
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// DocComment returns the text of the doc comment of the declaration on line
+// declLine, and a boolean indicating whether the declaration has a doc comment.
+// The doc comment is the comment group which ends on the line immediately
+// preceding the declaration, unless the group starts with a trailing comment of
+// another token.
+//
+// The comment markers // and /* */ are removed from the text, as are leading
+// asterisks of lines within block comments, trailing white space of each line
+// and leading and trailing empty lines.
+func DocComment(tokens []token.Token, declLine int) (string, bool) {
+	for _, group := range GroupComments(tokens) {
+		last := group[len(group)-1]
+		if last.Line+strings.Count(last.Val, "\n") != declLine-1 {
+			continue
+		}
+		if isTrailing(tokens, group[0]) {
+			return "", false
+		}
+		return commentText(group), true
+	}
+	return "", false
+}
+
+// isTrailing reports whether the provided comment is preceded by a non-comment
+// token on the same line.
+func isTrailing(tokens []token.Token, comment token.Token) bool {
+	for _, tok := range tokens {
+		if tok == comment {
+			return false
+		}
+		if tok.Kind&^token.Invalid != token.Comment && tok.Line == comment.Line {
+			return true
+		}
+	}
+	return false
+}
+
+// commentText returns the text of the provided comment group, without comment
+// markers.
+func commentText(group []token.Token) string {
+	var lines []string
+	for _, comment := range group {
+		lines = append(lines, comment.CommentText())
+	}
+
+	// Remove leading and trailing empty lines.
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
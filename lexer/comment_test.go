@@ -0,0 +1,137 @@
+package lexer
+
+import "testing"
+
+func TestGroupComments(t *testing.T) {
+	golden := []struct {
+		in   string
+		want [][]string
+	}{
+		// Adjacent line comments.
+		{
+			in:   "// a\n// b\n// c\npackage p",
+			want: [][]string{{"// a", "// b", "// c"}},
+		},
+		// Line comments separated by an empty line.
+		{
+			in:   "// a\n\n// b\npackage p",
+			want: [][]string{{"// a"}, {"// b"}},
+		},
+		// Comments separated by a token.
+		{
+			in:   "// a\npackage p\n// b\n",
+			want: [][]string{{"// a"}, {"// b"}},
+		},
+		// Multi-line block comment followed by a line comment.
+		{
+			in:   "/* a\n b */\n// c\n\n/* d */ /* e */",
+			want: [][]string{{"/* a\n b */", "// c"}, {"/* d */", "/* e */"}},
+		},
+		// Trailing comment followed by a doc comment.
+		{
+			in:   "package p // a\n// b\nvar x int",
+			want: [][]string{{"// a", "// b"}},
+		},
+		// No comments.
+		{
+			in:   "package p",
+			want: nil,
+		},
+	}
+
+	for i, g := range golden {
+		tokens, err := Parse(g.in)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		groups := GroupComments(tokens)
+		if len(groups) != len(g.want) {
+			t.Errorf("i=%d: group count mismatch; expected %d, got %d.", i, len(g.want), len(groups))
+			continue
+		}
+		for j, group := range groups {
+			if len(group) != len(g.want[j]) {
+				t.Errorf("i=%d, j=%d: comment count mismatch; expected %d, got %d.", i, j, len(g.want[j]), len(group))
+				continue
+			}
+			for k, tok := range group {
+				if tok.Val != g.want[j][k] {
+					t.Errorf("i=%d, j=%d, k=%d: comment mismatch; expected %q, got %q.", i, j, k, g.want[j][k], tok.Val)
+				}
+			}
+		}
+	}
+}
+
+func TestDocComment(t *testing.T) {
+	golden := []struct {
+		in       string
+		declLine int
+		want     string
+		ok       bool
+	}{
+		// Line comments.
+		{
+			in:       "// Package p implements\n// foo.\npackage p",
+			declLine: 3,
+			want:     "Package p implements\nfoo.",
+			ok:       true,
+		},
+		// Block comment with leading asterisks.
+		{
+			in:       "package p\n\n/*\n * F returns 42.\n *\n * Details.\n */\nfunc F() int",
+			declLine: 8,
+			want:     "F returns 42.\n\nDetails.",
+			ok:       true,
+		},
+		// Single line block comment.
+		{
+			in:       "package p\n/* T is a type. */\ntype T int",
+			declLine: 3,
+			want:     "T is a type.",
+			ok:       true,
+		},
+		// Only the comment group preceding the declaration.
+		{
+			in:       "// Copyright.\n\n// Package p.\npackage p",
+			declLine: 4,
+			want:     "Package p.",
+			ok:       true,
+		},
+		// Empty line between comment and declaration.
+		{
+			in:       "// Package p.\n\npackage p",
+			declLine: 3,
+			ok:       false,
+		},
+		// Trailing comment of the preceding line.
+		{
+			in:       "package p\nvar x int // x.\nvar y int",
+			declLine: 3,
+			ok:       false,
+		},
+		// No comments.
+		{
+			in:       "package p\nvar x int",
+			declLine: 2,
+			ok:       false,
+		},
+	}
+
+	for i, g := range golden {
+		tokens, err := Parse(g.in)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		got, ok := DocComment(tokens, g.declLine)
+		if ok != g.ok {
+			t.Errorf("i=%d: ok mismatch; expected %t, got %t.", i, g.ok, ok)
+			continue
+		}
+		if got != g.want {
+			t.Errorf("i=%d: doc comment mismatch; expected %q, got %q.", i, g.want, got)
+		}
+	}
+}
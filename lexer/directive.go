@@ -0,0 +1,61 @@
+package lexer
+
+import (
+	"strings"
+
+	"github.com/mewlang/go/token"
+)
+
+// A Directive is a compiler directive comment of the form //go:name args; e.g.
+//
+//    //go:generate stringer -type=Kind
+//    //go:noinline
+type Directive struct {
+	// Directive name, without the "go:" prefix; e.g. "generate".
+	Name string
+	// Directive arguments, or an empty string; e.g. "stringer -type=Kind".
+	Args string
+	// Position of the directive comment.
+	Pos token.Position
+}
+
+// Directives returns the compiler directives of the provided tokens, in order of
+// occurrence. A directive is a line comment which starts a line, and which has
+// no space between // and go:; e.g. "// go:noinline" is not a directive.
+func Directives(tokens []token.Token) []Directive {
+	var dirs []Directive
+	// Last line of the preceding token.
+	prev := 0
+	for _, tok := range tokens {
+		switch tok.Kind {
+		case token.Whitespace, token.Newline:
+			continue
+		case token.Comment:
+			if tok.Line != prev {
+				if dir, ok := parseDirective(tok.Val); ok {
+					dir.Pos = tok.Pos()
+					dirs = append(dirs, dir)
+				}
+			}
+		}
+		prev = tok.Line + strings.Count(tok.Val, "\n")
+	}
+	return dirs
+}
+
+// parseDirective parses the provided comment as a compiler directive.
+func parseDirective(comment string) (Directive, bool) {
+	const prefix = "//go:"
+	if !strings.HasPrefix(comment, prefix) {
+		return Directive{}, false
+	}
+	s := comment[len(prefix):]
+	name, args := s, ""
+	if i := strings.IndexAny(s, " \t"); i != -1 {
+		name, args = s[:i], strings.TrimSpace(s[i:])
+	}
+	if name == "" {
+		return Directive{}, false
+	}
+	return Directive{Name: name, Args: args}, true
+}
@@ -0,0 +1,45 @@
+package lexer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mewlang/go/token"
+)
+
+func TestDirectives(t *testing.T) {
+	golden := []struct {
+		in   string
+		want []Directive
+	}{
+		{in: "package p\n"},
+		{
+			in:   "package token\n\n//go:generate stringer -type=Kind\n",
+			want: []Directive{{Name: "generate", Args: "stringer -type=Kind", Pos: token.Position{Line: 3, Col: 1, Offset: 15}}},
+		},
+		{
+			in: "//go:build linux\n\npackage p\n\n//go:noinline\nfunc f() {}\n\n\t//go:embed  a.txt b.txt \nvar x string\n",
+			want: []Directive{
+				{Name: "build", Args: "linux", Pos: token.Position{Line: 1, Col: 1, Offset: 0}},
+				{Name: "noinline", Pos: token.Position{Line: 5, Col: 1, Offset: 29}},
+				{Name: "embed", Args: "a.txt b.txt", Pos: token.Position{Line: 8, Col: 2, Offset: 57}},
+			},
+		},
+		// Comments which are not directives.
+		{in: "// go: foo\n// go:noinline\n//go:\n//go: foo\n/*go:noinline*/\n//line foo.go:10\n"},
+		// Directives must start a line.
+		{in: "var x int //go:noinline\n/* c */ //go:noinline\n"},
+	}
+
+	for i, g := range golden {
+		tokens, err := Parse(g.in)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		got := Directives(tokens)
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: directives mismatch; expected %v, got %v.", i, g.want, got)
+		}
+	}
+}
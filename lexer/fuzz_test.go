@@ -0,0 +1,75 @@
+package lexer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mewlang/go/token"
+)
+
+// FuzzParse checks the following invariants of Parse on arbitrary input:
+//
+//    * Parse never panics.
+//    * Every token has a known token type.
+//    * Lexically invalid tokens are reported in the returned error list.
+//    * Lexer yields the same tokens and errors as Parse.
+//
+// The end of input is not marked by a token; it is the end of the token slice.
+//
+// Run the fuzzer using:
+//
+//    go test -run=NONE -fuzz=FuzzParse github.com/mewlang/go/lexer
+func FuzzParse(f *testing.F) {
+	f.Add(source)
+	for _, g := range golden {
+		f.Add(g.in)
+	}
+	for _, g := range goldenErrors {
+		f.Add(g.in)
+	}
+	// Inputs which exercise lexer features not covered by the golden tables.
+	seeds := []string{
+		"",
+		"x := 'a\ny := \"b\n",
+		"#!/usr/bin/env gorun\n",
+		"//line foo.go:10:5\nx",
+		"x\r\ny\rz",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		tokens, err := Parse(input)
+		var errs ErrorList
+		if err != nil {
+			errs = err.(ErrorList)
+		}
+		for i, tok := range tokens {
+			kind := tok.Kind &^ token.Invalid
+			if kind > token.Ellipsis || (kind == token.None && tok.Kind != token.Invalid) {
+				t.Fatalf("i=%d: unknown token type %d of token %q", i, tok.Kind, tok.Val)
+			}
+			if !tok.Kind.IsValid() && len(errs) == 0 {
+				t.Fatalf("i=%d: invalid token %q without error", i, tok.Val)
+			}
+		}
+
+		// Compare against Lexer.
+		var got []token.Token
+		lx := New(input)
+		for {
+			tok, ok := lx.Next()
+			if !ok {
+				break
+			}
+			got = append(got, tok)
+		}
+		if !token.TokensEqual(got, tokens) {
+			t.Fatalf("Lexer tokens mismatch; expected %v, got %v.", tokens, got)
+		}
+		if !reflect.DeepEqual(lx.Errors(), errs) {
+			t.Fatalf("Lexer errors mismatch; expected %v, got %v.", errs, lx.Errors())
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package lexer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mewlang/go/token"
+)
+
+// PackageName returns the package name of the package clause of the provided
+// source file. The input is only lexed up to the end of the line containing the
+// package name; leading comments, such as license headers and build
+// constraints, and a byte order mark are skipped.
+//
+//    PackageClause  = "package" PackageName .
+//    PackageName    = identifier .
+//
+// ref: http://golang.org/ref/spec#Package_clause
+func PackageName(input string) (string, error) {
+	lx := New(input)
+	// next returns the next non-comment token, or a zero token at the end of
+	// input.
+	next := func() token.Token {
+		for {
+			tok, ok := lx.Next()
+			if !ok {
+				return token.Token{}
+			}
+			if tok.Kind&^token.Invalid != token.Comment {
+				return tok
+			}
+		}
+	}
+	if tok := next(); tok.Kind != token.Package {
+		return "", unexpected("lexer.PackageName", tok, "keyword 'package'")
+	}
+	tok := next()
+	if tok.Kind != token.Ident {
+		return "", unexpected("lexer.PackageName", tok, "package name")
+	}
+	return tok.Val, nil
+}
+
+// unexpected returns an error of the provided function, which reports that the
+// token tok was found instead of want. A zero token denotes the end of input.
+func unexpected(fn string, tok token.Token, want string) error {
+	if tok.Kind == token.None {
+		return fmt.Errorf("%s: expected %s, found EOF", fn, want)
+	}
+	return fmt.Errorf("%s: %v: expected %s, found %s", fn, tok.Pos(), want, tok.Kind.Describe())
+}
+
+// BuildConstraints returns the build constraints of the file header of the
+// provided tokens; i.e. the expression of its //go:build line, and the
+// arguments of each of its // +build lines. E.g. the following header has the
+// //go:build expression "linux && amd64" and the +build arguments
+// ["linux,amd64"].
+//
+//    //go:build linux && amd64
+//    // +build linux,amd64
+//
+//    package sys
+//
+// Build constraints are line comments which start a line of the file header.
+// The file header consists of the comments and blank lines preceding the first
+// non-comment token, up to the last blank line; a constraint must thus be
+// followed by a blank line, to distinguish it from a package doc comment. Only
+// the first //go:build line is reported.
+func BuildConstraints(tokens []token.Token) (goBuild string, plusBuild []string) {
+	// Line comments which start a line of the leading comments.
+	var comments []token.Token
+	// Lines spanned by the leading comments.
+	used := make(map[int]bool)
+	// Line of the first non-comment token, and last line of the preceding
+	// comment.
+	first, prev := 0, 0
+loop:
+	for _, tok := range tokens {
+		switch tok.Kind &^ token.Invalid {
+		case token.Whitespace, token.Newline:
+			continue
+		case token.Comment:
+		default:
+			first = tok.Line
+			break loop
+		}
+		if tok.Line != prev && strings.HasPrefix(tok.Val, "//") {
+			comments = append(comments, tok)
+		}
+		prev = tok.Line + strings.Count(tok.Val, "\n")
+		for line := tok.Line; line <= prev; line++ {
+			used[line] = true
+		}
+	}
+	if first == 0 {
+		// The end of input acts as a blank line.
+		first = prev + 2
+	}
+	// Locate the last blank line of the file header.
+	blank := first - 1
+	for blank > 0 && used[blank] {
+		blank--
+	}
+
+	for _, tok := range comments {
+		if tok.Line > blank {
+			break
+		}
+		if expr, ok := cutDirective(tok.Val, "//go:build"); ok {
+			if goBuild == "" {
+				goBuild = expr
+			}
+			continue
+		}
+		if args, ok := cutDirective(strings.TrimSpace(tok.Val[len("//"):]), "+build"); ok && args != "" {
+			plusBuild = append(plusBuild, args)
+		}
+	}
+	return goBuild, plusBuild
+}
+
+// cutDirective returns the arguments of the provided comment text, and a
+// boolean indicating whether the text is the given directive; i.e. whether it
+// consists of the directive, optionally followed by white space and arguments.
+func cutDirective(comment, directive string) (args string, ok bool) {
+	if !strings.HasPrefix(comment, directive) {
+		return "", false
+	}
+	rest := comment[len(directive):]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return "", false
+	}
+	return strings.TrimSpace(rest), true
+}
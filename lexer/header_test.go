@@ -0,0 +1,112 @@
+package lexer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPackageName(t *testing.T) {
+	golden := []struct {
+		in   string
+		want string
+		err  string
+	}{
+		{in: "package main\n", want: "main"},
+		{in: "package p; import \"fmt\"", want: "p"},
+		{in: "\ufeffpackage p\n", want: "p"},
+		{in: "// Package foo does bar.\npackage foo // import \"x/foo\"\n\nfunc f() {", want: "foo"},
+		{in: "//go:build linux && amd64\n// +build linux,amd64\n\npackage sys\n", want: "sys"},
+		{
+			in: `/*
+ * Copyright 2014 The Authors. All rights reserved.
+ *
+ * Use of this source code is governed by a BSD-style license.
+ */
+
+// Package license has a license header.
+package license
+
+import "fmt"
+`,
+			want: "license",
+		},
+		{in: "package\n\tp\n", want: "p"},
+		{in: "package /* name: */ q", want: "q"},
+		// Only the package clause is lexed.
+		{in: "package p\n'unterminated", want: "p"},
+		// Invalid package clauses.
+		{in: "", err: "lexer.PackageName: expected keyword 'package', found EOF"},
+		{in: "// package p\n", err: "lexer.PackageName: expected keyword 'package', found EOF"},
+		{in: "func main() {}", err: "lexer.PackageName: 1:1: expected keyword 'package', found keyword 'func'"},
+		{in: "package", err: "lexer.PackageName: expected package name, found EOF"},
+		{in: "package 42", err: "lexer.PackageName: 1:9: expected package name, found an int literal"},
+		{in: "package ;", err: "lexer.PackageName: 1:9: expected package name, found ';'"},
+	}
+
+	for i, g := range golden {
+		got, err := PackageName(g.in)
+		var errStr string
+		if err != nil {
+			errStr = err.Error()
+		}
+		if errStr != g.err {
+			t.Errorf("i=%d: error mismatch; expected %q, got %q.", i, g.err, errStr)
+			continue
+		}
+		if got != g.want {
+			t.Errorf("i=%d: package name mismatch; expected %q, got %q.", i, g.want, got)
+		}
+	}
+}
+
+func TestBuildConstraints(t *testing.T) {
+	golden := []struct {
+		in        string
+		goBuild   string
+		plusBuild []string
+	}{
+		{in: "package p\n"},
+		{in: "//go:build linux\n\npackage p\n", goBuild: "linux"},
+		{in: "// +build linux darwin\n\npackage p\n", plusBuild: []string{"linux darwin"}},
+		{
+			in:        "//go:build (linux || darwin) && !cgo\n// +build linux darwin\n// +build !cgo\n\n// Package p does q.\npackage p\n",
+			goBuild:   "(linux || darwin) && !cgo",
+			plusBuild: []string{"linux darwin", "!cgo"},
+		},
+		// License header before the build constraints.
+		{
+			in:        "/*\n * Copyright 2014 The Authors.\n */\n\n// Copyright notice.\n\n//go:build ignore\n//+build ignore\n\npackage main\n",
+			goBuild:   "ignore",
+			plusBuild: []string{"ignore"},
+		},
+		// Build constraints within the doc comment are ignored.
+		{in: "//go:build linux\npackage p\n"},
+		{in: "// +build linux\n// Package p.\npackage p\n"},
+		{in: "//go:build linux\n\n// +build linux\npackage p\n", goBuild: "linux"},
+		// Build constraints after the package clause are ignored.
+		{in: "package p\n\n//go:build linux\n\n// +build linux\n\nfunc f() {}\n"},
+		// Only the first //go:build line is reported.
+		{in: "//go:build linux\n//go:build darwin\n\npackage p\n", goBuild: "linux"},
+		// Comments which are not build constraints.
+		{in: "//go:buildx linux\n// +builds linux\n// +build\n// go:build linux\n\npackage p\n"},
+		{in: "/* x */ // +build linux\n\npackage p\n"},
+		{in: "/*\n//go:build linux\n*/\n\npackage p\n"},
+		// End of input acts as a blank line.
+		{in: "//go:build linux\n// +build linux\n", goBuild: "linux", plusBuild: []string{"linux"}},
+	}
+
+	for i, g := range golden {
+		tokens, err := Parse(g.in)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		goBuild, plusBuild := BuildConstraints(tokens)
+		if goBuild != g.goBuild {
+			t.Errorf("i=%d: //go:build mismatch; expected %q, got %q.", i, g.goBuild, goBuild)
+		}
+		if !reflect.DeepEqual(plusBuild, g.plusBuild) {
+			t.Errorf("i=%d: +build mismatch; expected %q, got %q.", i, g.plusBuild, plusBuild)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package lexer
+
+import "unicode"
+
+// IsIdentStart reports whether r may start an identifier; i.e. whether it is a
+// Unicode letter or an underscore.
+//
+//    identifier = letter { letter | unicode_digit } .
+//    letter     = unicode_letter | "_" .
+//
+// ref: http://golang.org/ref/spec#Identifiers
+func IsIdentStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+// IsIdentCont reports whether r may continue an identifier; i.e. whether it is a
+// Unicode letter, a Unicode decimal digit or an underscore.
+//
+// ref: http://golang.org/ref/spec#Identifiers
+func IsIdentCont(r rune) bool {
+	return IsIdentStart(r) || unicode.IsDigit(r)
+}
+
+// IsIdentifier reports whether s is a legal identifier; i.e. a non-empty
+// sequence of letters and digits, starting with a letter, which is not a
+// keyword. Invalid UTF-8 encodings are rejected, as the Unicode replacement
+// character is neither a letter nor a digit.
+//
+// ref: http://golang.org/ref/spec#Identifiers
+func IsIdentifier(s string) bool {
+	if _, ok := keywords[s]; ok {
+		return false
+	}
+	for i, r := range s {
+		if i == 0 && !IsIdentStart(r) || !IsIdentCont(r) {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,97 @@
+package lexer
+
+import "testing"
+
+func TestIsIdentStart(t *testing.T) {
+	golden := []struct {
+		r    rune
+		want bool
+	}{
+		{r: 'a', want: true},
+		{r: 'Z', want: true},
+		{r: '_', want: true},
+		{r: 'ŝ', want: true},
+		{r: 'Ж', want: true},
+		{r: '0', want: false},
+		{r: '۰', want: false}, // Extended Arabic-Indic digit zero
+		{r: '-', want: false},
+		{r: ' ', want: false},
+		{r: '$', want: false},
+	}
+
+	for i, g := range golden {
+		got := IsIdentStart(g.r)
+		if got != g.want {
+			t.Errorf("i=%d: result mismatch for %#U; expected %v, got %v.", i, g.r, g.want, got)
+		}
+	}
+}
+
+func TestIsIdentCont(t *testing.T) {
+	golden := []struct {
+		r    rune
+		want bool
+	}{
+		{r: 'a', want: true},
+		{r: '_', want: true},
+		{r: '9', want: true},
+		{r: 'ŝ', want: true},
+		{r: '۰', want: true},  // Extended Arabic-Indic digit zero
+		{r: '۱', want: true},  // Extended Arabic-Indic digit one
+		{r: '۸', want: true},  // Extended Arabic-Indic digit eight
+		{r: '६', want: true},  // Devanagari digit six
+		{r: '²', want: false}, // Superscript two; not a decimal digit
+		{r: '-', want: false},
+		{r: '.', want: false},
+	}
+
+	for i, g := range golden {
+		got := IsIdentCont(g.r)
+		if got != g.want {
+			t.Errorf("i=%d: result mismatch for %#U; expected %v, got %v.", i, g.r, g.want, got)
+		}
+	}
+}
+
+func TestIsIdentifier(t *testing.T) {
+	golden := []struct {
+		s    string
+		want bool
+	}{
+		// i=0
+		{s: "a", want: true},
+		{s: "_x", want: true},
+		{s: "_", want: true},
+		{s: "x9", want: true},
+		{s: "ThisVariableIsExported", want: true},
+		// i=5
+		{s: "αβ", want: true},
+		{s: "Ж", want: true},
+		{s: "foo६४", want: true},
+		{s: "a۰۱۸", want: true},
+		{s: "ŝfoo", want: true},
+		// i=10
+		{s: "iota", want: true},
+		{s: "int", want: true},
+		{s: "", want: false},
+		{s: "123abc", want: false},
+		{s: "६४", want: false},
+		// i=15
+		{s: "for", want: false},
+		{s: "func", want: false},
+		{s: "foo-bar", want: false},
+		{s: "foo bar", want: false},
+		{s: "foo.Bar", want: false},
+		// i=20
+		{s: "x²", want: false},
+		{s: "a\xffb", want: false},
+		{s: "\ufeffx", want: false},
+	}
+
+	for i, g := range golden {
+		got := IsIdentifier(g.s)
+		if got != g.want {
+			t.Errorf("i=%d: result mismatch for %q; expected %v, got %v.", i, g.s, g.want, got)
+		}
+	}
+}
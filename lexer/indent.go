@@ -0,0 +1,56 @@
+package lexer
+
+import (
+	"strings"
+
+	"github.com/mewlang/go/token"
+)
+
+// An Issue is a source hygiene issue on a given line of the input.
+type Issue struct {
+	// Line number, starting at 1.
+	Line int
+	// Description of the issue.
+	Msg string
+}
+
+// IndentIssues returns the lines of the input which are indented using spaces,
+// or using a mix of tabs and spaces; Go source code is conventionally indented
+// using tabs. Line numbers refer to the lines of the input, unaffected by line
+// directives.
+//
+// Lines which start within a block comment or a raw string literal are not
+// reported, and neither are blank lines.
+func IndentIssues(input string) []Issue {
+	lx := New(input)
+	lx.EmitTrivia = true
+	var issues []Issue
+	line := 1
+	lineStart := true
+	// White space at the start of the current line, if any.
+	indent := ""
+	for {
+		tok, ok := lx.Next()
+		if !ok {
+			break
+		}
+		switch {
+		case tok.Kind == token.Whitespace && lineStart:
+			indent = tok.Val
+		case tok.Kind == token.Newline:
+			// Ignore blank lines.
+			indent = ""
+		case indent != "":
+			switch {
+			case strings.ContainsRune(indent, ' ') && strings.ContainsRune(indent, '\t'):
+				issues = append(issues, Issue{Line: line, Msg: "indentation contains both tabs and spaces"})
+			case strings.ContainsRune(indent, ' '):
+				issues = append(issues, Issue{Line: line, Msg: "indentation uses spaces instead of tabs"})
+			}
+			indent = ""
+		}
+		lineStart = tok.Kind == token.Newline
+		line += strings.Count(tok.Val, "\n")
+	}
+	return issues
+}
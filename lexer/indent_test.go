@@ -0,0 +1,46 @@
+package lexer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIndentIssues(t *testing.T) {
+	golden := []struct {
+		in   string
+		want []Issue
+	}{
+		// Tab-indented lines.
+		{in: "func f() {\n\tif x {\n\t\ty()\n\t}\n}\n", want: nil},
+		// Space-indented lines.
+		{
+			in: "func f() {\n    x()\n\ty()\n  }\n",
+			want: []Issue{
+				{Line: 2, Msg: "indentation uses spaces instead of tabs"},
+				{Line: 4, Msg: "indentation uses spaces instead of tabs"},
+			},
+		},
+		// Mixed lines.
+		{
+			in: "func f() {\n\t  x()\n  \ty()\n\tz()\n}\n",
+			want: []Issue{
+				{Line: 2, Msg: "indentation contains both tabs and spaces"},
+				{Line: 3, Msg: "indentation contains both tabs and spaces"},
+			},
+		},
+		// Blank lines, block comments and raw string literals.
+		{in: "x\n   \n\t \r\ny\n", want: nil},
+		{in: "/*\n * comment\n */\nx\n", want: nil},
+		{in: "s := `\n    raw\n`\n", want: nil},
+		{in: "/* a\n */ x\n  y\n", want: []Issue{{Line: 3, Msg: "indentation uses spaces instead of tabs"}}},
+		// Line directives do not affect line numbers.
+		{in: "//line foo.go:100\n  x\n", want: []Issue{{Line: 2, Msg: "indentation uses spaces instead of tabs"}}},
+	}
+
+	for i, g := range golden {
+		got := IndentIssues(g.in)
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: issues mismatch; expected %v, got %v.", i, g.want, got)
+		}
+	}
+}
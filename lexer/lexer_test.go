@@ -2,8 +2,16 @@ package lexer
 
 import (
 	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/mewlang/go/token"
 )
@@ -14,127 +22,127 @@ var golden = []struct {
 	want token.Token
 }{
 	// Special tokens
-	{in: "/* a comment */", want: token.Token{Kind: token.Comment, Val: "/* a comment */", Line: 1, Col: 1}},
-	{in: "// a comment \n", want: token.Token{Kind: token.Comment, Val: "// a comment ", Line: 4, Col: 1}},
-	{in: "/*\r*/", want: token.Token{Kind: token.Comment, Val: "/**/", Line: 8, Col: 1}},
-	{in: "//\r\n", want: token.Token{Kind: token.Comment, Val: "//", Line: 11, Col: 1}},
+	{in: "/* a comment */", want: token.Token{Kind: token.Comment, Val: "/* a comment */", Line: 1, Col: 1, Offset: 0}},
+	{in: "// a comment \n", want: token.Token{Kind: token.Comment, Val: "// a comment ", Line: 4, Col: 1, Offset: 23}},
+	{in: "/*\r*/", want: token.Token{Kind: token.Comment, Val: "/**/", Line: 8, Col: 1, Offset: 45}},
+	{in: "//\r\n", want: token.Token{Kind: token.Comment, Val: "//", Line: 11, Col: 1, Offset: 58}},
 
 	// Identifiers and basic type literals
-	{in: "foobar", want: token.Token{Kind: token.Ident, Val: "foobar", Line: 15, Col: 1}},
-	{in: "a۰۱۸", want: token.Token{Kind: token.Ident, Val: "a۰۱۸", Line: 18, Col: 1}},
-	{in: "foo६४", want: token.Token{Kind: token.Ident, Val: "foo६४", Line: 21, Col: 1}},
-	{in: "bar９８７６", want: token.Token{Kind: token.Ident, Val: "bar９８７６", Line: 24, Col: 1}},
-	{in: "ŝ", want: token.Token{Kind: token.Ident, Val: "ŝ", Line: 27, Col: 1}},       // was bug (issue 4000)
-	{in: "ŝfoo", want: token.Token{Kind: token.Ident, Val: "ŝfoo", Line: 30, Col: 1}}, // was bug (issue 4000)
-	{in: "0", want: token.Token{Kind: token.Int, Val: "0", Line: 33, Col: 1}},
-	{in: "1", want: token.Token{Kind: token.Int, Val: "1", Line: 36, Col: 1}},
-	{in: "123456789012345678890", want: token.Token{Kind: token.Int, Val: "123456789012345678890", Line: 39, Col: 1}},
-	{in: "01234567", want: token.Token{Kind: token.Int, Val: "01234567", Line: 42, Col: 1}},
-	{in: "0xcafebabe", want: token.Token{Kind: token.Int, Val: "0xcafebabe", Line: 45, Col: 1}},
-	{in: "0.", want: token.Token{Kind: token.Float, Val: "0.", Line: 48, Col: 1}},
-	{in: ".0", want: token.Token{Kind: token.Float, Val: ".0", Line: 51, Col: 1}},
-	{in: "3.14159265", want: token.Token{Kind: token.Float, Val: "3.14159265", Line: 54, Col: 1}},
-	{in: "1e0", want: token.Token{Kind: token.Float, Val: "1e0", Line: 57, Col: 1}},
-	{in: "1e+100", want: token.Token{Kind: token.Float, Val: "1e+100", Line: 60, Col: 1}},
-	{in: "1e-100", want: token.Token{Kind: token.Float, Val: "1e-100", Line: 63, Col: 1}},
-	{in: "2.71828e-1000", want: token.Token{Kind: token.Float, Val: "2.71828e-1000", Line: 66, Col: 1}},
-	{in: "0i", want: token.Token{Kind: token.Imag, Val: "0i", Line: 69, Col: 1}},
-	{in: "1i", want: token.Token{Kind: token.Imag, Val: "1i", Line: 72, Col: 1}},
-	{in: "012345678901234567889i", want: token.Token{Kind: token.Imag, Val: "012345678901234567889i", Line: 75, Col: 1}},
-	{in: "123456789012345678890i", want: token.Token{Kind: token.Imag, Val: "123456789012345678890i", Line: 78, Col: 1}},
-	{in: "0.i", want: token.Token{Kind: token.Imag, Val: "0.i", Line: 81, Col: 1}},
-	{in: ".0i", want: token.Token{Kind: token.Imag, Val: ".0i", Line: 84, Col: 1}},
-	{in: "3.14159265i", want: token.Token{Kind: token.Imag, Val: "3.14159265i", Line: 87, Col: 1}},
-	{in: "1e0i", want: token.Token{Kind: token.Imag, Val: "1e0i", Line: 90, Col: 1}},
-	{in: "1e+100i", want: token.Token{Kind: token.Imag, Val: "1e+100i", Line: 93, Col: 1}},
-	{in: "1e-100i", want: token.Token{Kind: token.Imag, Val: "1e-100i", Line: 96, Col: 1}},
-	{in: "2.71828e-1000i", want: token.Token{Kind: token.Imag, Val: "2.71828e-1000i", Line: 99, Col: 1}},
-	{in: "'a'", want: token.Token{Kind: token.Rune, Val: "'a'", Line: 102, Col: 1}},
-	{in: "'\\000'", want: token.Token{Kind: token.Rune, Val: "'\\000'", Line: 105, Col: 1}},
-	{in: "'\\xFF'", want: token.Token{Kind: token.Rune, Val: "'\\xFF'", Line: 108, Col: 1}},
-	{in: "'\\uff16'", want: token.Token{Kind: token.Rune, Val: "'\\uff16'", Line: 111, Col: 1}},
-	{in: "'\\U0000ff16'", want: token.Token{Kind: token.Rune, Val: "'\\U0000ff16'", Line: 114, Col: 1}},
-	{in: "`foobar`", want: token.Token{Kind: token.String, Val: "`foobar`", Line: 117, Col: 1}},
-	{in: `"\a\b\f\n\r\t\v\\\""`, want: token.Token{Kind: token.String, Val: `"\a\b\f\n\r\t\v\\\""`, Line: 120, Col: 1}},
-	{in: "`foo\n\t                        bar`", want: token.Token{Kind: token.String, Val: "`foo\n\t                        bar`", Line: 123, Col: 1}},
-	{in: "`\r`", want: token.Token{Kind: token.String, Val: "``", Line: 127, Col: 1}},
-	{in: "`foo\r\nbar`", want: token.Token{Kind: token.String, Val: "`foo\nbar`", Line: 130, Col: 1}},
+	{in: "foobar", want: token.Token{Kind: token.Ident, Val: "foobar", Line: 15, Col: 1, Offset: 70}},
+	{in: "a۰۱۸", want: token.Token{Kind: token.Ident, Val: "a۰۱۸", Line: 18, Col: 1, Offset: 84}},
+	{in: "foo६४", want: token.Token{Kind: token.Ident, Val: "foo६४", Line: 21, Col: 1, Offset: 99}},
+	{in: "bar９８７６", want: token.Token{Kind: token.Ident, Val: "bar９８７６", Line: 24, Col: 1, Offset: 116}},
+	{in: "ŝ", want: token.Token{Kind: token.Ident, Val: "ŝ", Line: 27, Col: 1, Offset: 139}},       // was bug (issue 4000)
+	{in: "ŝfoo", want: token.Token{Kind: token.Ident, Val: "ŝfoo", Line: 30, Col: 1, Offset: 149}}, // was bug (issue 4000)
+	{in: "0", want: token.Token{Kind: token.Int, Val: "0", Line: 33, Col: 1, Offset: 162}},
+	{in: "1", want: token.Token{Kind: token.Int, Val: "1", Line: 36, Col: 1, Offset: 171}},
+	{in: "123456789012345678890", want: token.Token{Kind: token.Int, Val: "123456789012345678890", Line: 39, Col: 1, Offset: 180}},
+	{in: "01234567", want: token.Token{Kind: token.Int, Val: "01234567", Line: 42, Col: 1, Offset: 209}},
+	{in: "0xcafebabe", want: token.Token{Kind: token.Int, Val: "0xcafebabe", Line: 45, Col: 1, Offset: 225}},
+	{in: "0.", want: token.Token{Kind: token.Float, Val: "0.", Line: 48, Col: 1, Offset: 243}},
+	{in: ".0", want: token.Token{Kind: token.Float, Val: ".0", Line: 51, Col: 1, Offset: 253}},
+	{in: "3.14159265", want: token.Token{Kind: token.Float, Val: "3.14159265", Line: 54, Col: 1, Offset: 263}},
+	{in: "1e0", want: token.Token{Kind: token.Float, Val: "1e0", Line: 57, Col: 1, Offset: 281}},
+	{in: "1e+100", want: token.Token{Kind: token.Float, Val: "1e+100", Line: 60, Col: 1, Offset: 292}},
+	{in: "1e-100", want: token.Token{Kind: token.Float, Val: "1e-100", Line: 63, Col: 1, Offset: 306}},
+	{in: "2.71828e-1000", want: token.Token{Kind: token.Float, Val: "2.71828e-1000", Line: 66, Col: 1, Offset: 320}},
+	{in: "0i", want: token.Token{Kind: token.Imag, Val: "0i", Line: 69, Col: 1, Offset: 341}},
+	{in: "1i", want: token.Token{Kind: token.Imag, Val: "1i", Line: 72, Col: 1, Offset: 351}},
+	{in: "012345678901234567889i", want: token.Token{Kind: token.Imag, Val: "012345678901234567889i", Line: 75, Col: 1, Offset: 361}},
+	{in: "123456789012345678890i", want: token.Token{Kind: token.Imag, Val: "123456789012345678890i", Line: 78, Col: 1, Offset: 391}},
+	{in: "0.i", want: token.Token{Kind: token.Imag, Val: "0.i", Line: 81, Col: 1, Offset: 421}},
+	{in: ".0i", want: token.Token{Kind: token.Imag, Val: ".0i", Line: 84, Col: 1, Offset: 432}},
+	{in: "3.14159265i", want: token.Token{Kind: token.Imag, Val: "3.14159265i", Line: 87, Col: 1, Offset: 443}},
+	{in: "1e0i", want: token.Token{Kind: token.Imag, Val: "1e0i", Line: 90, Col: 1, Offset: 462}},
+	{in: "1e+100i", want: token.Token{Kind: token.Imag, Val: "1e+100i", Line: 93, Col: 1, Offset: 474}},
+	{in: "1e-100i", want: token.Token{Kind: token.Imag, Val: "1e-100i", Line: 96, Col: 1, Offset: 489}},
+	{in: "2.71828e-1000i", want: token.Token{Kind: token.Imag, Val: "2.71828e-1000i", Line: 99, Col: 1, Offset: 504}},
+	{in: "'a'", want: token.Token{Kind: token.Rune, Val: "'a'", Line: 102, Col: 1, Offset: 526}},
+	{in: "'\\000'", want: token.Token{Kind: token.Rune, Val: "'\\000'", Line: 105, Col: 1, Offset: 537}},
+	{in: "'\\xFF'", want: token.Token{Kind: token.Rune, Val: "'\\xFF'", Line: 108, Col: 1, Offset: 551}},
+	{in: "'\\uff16'", want: token.Token{Kind: token.Rune, Val: "'\\uff16'", Line: 111, Col: 1, Offset: 565}},
+	{in: "'\\U0000ff16'", want: token.Token{Kind: token.Rune, Val: "'\\U0000ff16'", Line: 114, Col: 1, Offset: 581}},
+	{in: "`foobar`", want: token.Token{Kind: token.String, Val: "`foobar`", Line: 117, Col: 1, Offset: 601}},
+	{in: `"\a\b\f\n\r\t\v\\\""`, want: token.Token{Kind: token.String, Val: `"\a\b\f\n\r\t\v\\\""`, Line: 120, Col: 1, Offset: 617}},
+	{in: "`foo\n\t                        bar`", want: token.Token{Kind: token.String, Val: "`foo\n\t                        bar`", Line: 123, Col: 1, Offset: 645}},
+	{in: "`\r`", want: token.Token{Kind: token.String, Val: "``", Line: 127, Col: 1, Offset: 687}},
+	{in: "`foo\r\nbar`", want: token.Token{Kind: token.String, Val: "`foo\nbar`", Line: 130, Col: 1, Offset: 698}},
 
 	// Operators and delimiters
-	{in: "+", want: token.Token{Kind: token.Add, Val: "+", Line: 134, Col: 1}},
-	{in: "-", want: token.Token{Kind: token.Sub, Val: "-", Line: 137, Col: 1}},
-	{in: "*", want: token.Token{Kind: token.Mul, Val: "*", Line: 140, Col: 1}},
-	{in: "/", want: token.Token{Kind: token.Div, Val: "/", Line: 143, Col: 1}},
-	{in: "%", want: token.Token{Kind: token.Mod, Val: "%", Line: 146, Col: 1}},
-	{in: "&", want: token.Token{Kind: token.And, Val: "&", Line: 149, Col: 1}},
-	{in: "|", want: token.Token{Kind: token.Or, Val: "|", Line: 152, Col: 1}},
-	{in: "^", want: token.Token{Kind: token.Xor, Val: "^", Line: 155, Col: 1}},
-	{in: "<<", want: token.Token{Kind: token.Shl, Val: "<<", Line: 158, Col: 1}},
-	{in: ">>", want: token.Token{Kind: token.Shr, Val: ">>", Line: 161, Col: 1}},
-	{in: "&^", want: token.Token{Kind: token.Clear, Val: "&^", Line: 164, Col: 1}},
-	{in: "+=", want: token.Token{Kind: token.AddAssign, Val: "+=", Line: 167, Col: 1}},
-	{in: "-=", want: token.Token{Kind: token.SubAssign, Val: "-=", Line: 170, Col: 1}},
-	{in: "*=", want: token.Token{Kind: token.MulAssign, Val: "*=", Line: 173, Col: 1}},
-	{in: "/=", want: token.Token{Kind: token.DivAssign, Val: "/=", Line: 176, Col: 1}},
-	{in: "%=", want: token.Token{Kind: token.ModAssign, Val: "%=", Line: 179, Col: 1}},
-	{in: "&=", want: token.Token{Kind: token.AndAssign, Val: "&=", Line: 182, Col: 1}},
-	{in: "|=", want: token.Token{Kind: token.OrAssign, Val: "|=", Line: 185, Col: 1}},
-	{in: "^=", want: token.Token{Kind: token.XorAssign, Val: "^=", Line: 188, Col: 1}},
-	{in: "<<=", want: token.Token{Kind: token.ShlAssign, Val: "<<=", Line: 191, Col: 1}},
-	{in: ">>=", want: token.Token{Kind: token.ShrAssign, Val: ">>=", Line: 194, Col: 1}},
-	{in: "&^=", want: token.Token{Kind: token.ClearAssign, Val: "&^=", Line: 197, Col: 1}},
-	{in: "&&", want: token.Token{Kind: token.Land, Val: "&&", Line: 200, Col: 1}},
-	{in: "||", want: token.Token{Kind: token.Lor, Val: "||", Line: 203, Col: 1}},
-	{in: "<-", want: token.Token{Kind: token.Arrow, Val: "<-", Line: 206, Col: 1}},
-	{in: "++", want: token.Token{Kind: token.Inc, Val: "++", Line: 209, Col: 1}},
-	{in: "--", want: token.Token{Kind: token.Dec, Val: "--", Line: 212, Col: 1}},
-	{in: "==", want: token.Token{Kind: token.Eq, Val: "==", Line: 215, Col: 1}},
-	{in: "<", want: token.Token{Kind: token.Lt, Val: "<", Line: 218, Col: 1}},
-	{in: ">", want: token.Token{Kind: token.Gt, Val: ">", Line: 221, Col: 1}},
-	{in: "=", want: token.Token{Kind: token.Assign, Val: "=", Line: 224, Col: 1}},
-	{in: "!", want: token.Token{Kind: token.Not, Val: "!", Line: 227, Col: 1}},
-	{in: "!=", want: token.Token{Kind: token.Neq, Val: "!=", Line: 230, Col: 1}},
-	{in: "<=", want: token.Token{Kind: token.Lte, Val: "<=", Line: 233, Col: 1}},
-	{in: ">=", want: token.Token{Kind: token.Gte, Val: ">=", Line: 236, Col: 1}},
-	{in: ":=", want: token.Token{Kind: token.DeclAssign, Val: ":=", Line: 239, Col: 1}},
-	{in: "...", want: token.Token{Kind: token.Ellipsis, Val: "...", Line: 242, Col: 1}},
-	{in: "(", want: token.Token{Kind: token.Lparen, Val: "(", Line: 245, Col: 1}},
-	{in: "[", want: token.Token{Kind: token.Lbrack, Val: "[", Line: 248, Col: 1}},
-	{in: "{", want: token.Token{Kind: token.Lbrace, Val: "{", Line: 251, Col: 1}},
-	{in: ",", want: token.Token{Kind: token.Comma, Val: ",", Line: 254, Col: 1}},
-	{in: ".", want: token.Token{Kind: token.Dot, Val: ".", Line: 257, Col: 1}},
-	{in: ")", want: token.Token{Kind: token.Rparen, Val: ")", Line: 260, Col: 1}},
-	{in: "]", want: token.Token{Kind: token.Rbrack, Val: "]", Line: 263, Col: 1}},
-	{in: "}", want: token.Token{Kind: token.Rbrace, Val: "}", Line: 266, Col: 1}},
-	{in: ";", want: token.Token{Kind: token.Semicolon, Val: ";", Line: 269, Col: 1}},
-	{in: ":", want: token.Token{Kind: token.Colon, Val: ":", Line: 272, Col: 1}},
+	{in: "+", want: token.Token{Kind: token.Add, Val: "+", Line: 134, Col: 1, Offset: 716}},
+	{in: "-", want: token.Token{Kind: token.Sub, Val: "-", Line: 137, Col: 1, Offset: 725}},
+	{in: "*", want: token.Token{Kind: token.Mul, Val: "*", Line: 140, Col: 1, Offset: 734}},
+	{in: "/", want: token.Token{Kind: token.Div, Val: "/", Line: 143, Col: 1, Offset: 743}},
+	{in: "%", want: token.Token{Kind: token.Mod, Val: "%", Line: 146, Col: 1, Offset: 752}},
+	{in: "&", want: token.Token{Kind: token.And, Val: "&", Line: 149, Col: 1, Offset: 761}},
+	{in: "|", want: token.Token{Kind: token.Or, Val: "|", Line: 152, Col: 1, Offset: 770}},
+	{in: "^", want: token.Token{Kind: token.Xor, Val: "^", Line: 155, Col: 1, Offset: 779}},
+	{in: "<<", want: token.Token{Kind: token.Shl, Val: "<<", Line: 158, Col: 1, Offset: 788}},
+	{in: ">>", want: token.Token{Kind: token.Shr, Val: ">>", Line: 161, Col: 1, Offset: 798}},
+	{in: "&^", want: token.Token{Kind: token.Clear, Val: "&^", Line: 164, Col: 1, Offset: 808}},
+	{in: "+=", want: token.Token{Kind: token.AddAssign, Val: "+=", Line: 167, Col: 1, Offset: 818}},
+	{in: "-=", want: token.Token{Kind: token.SubAssign, Val: "-=", Line: 170, Col: 1, Offset: 828}},
+	{in: "*=", want: token.Token{Kind: token.MulAssign, Val: "*=", Line: 173, Col: 1, Offset: 838}},
+	{in: "/=", want: token.Token{Kind: token.DivAssign, Val: "/=", Line: 176, Col: 1, Offset: 848}},
+	{in: "%=", want: token.Token{Kind: token.ModAssign, Val: "%=", Line: 179, Col: 1, Offset: 858}},
+	{in: "&=", want: token.Token{Kind: token.AndAssign, Val: "&=", Line: 182, Col: 1, Offset: 868}},
+	{in: "|=", want: token.Token{Kind: token.OrAssign, Val: "|=", Line: 185, Col: 1, Offset: 878}},
+	{in: "^=", want: token.Token{Kind: token.XorAssign, Val: "^=", Line: 188, Col: 1, Offset: 888}},
+	{in: "<<=", want: token.Token{Kind: token.ShlAssign, Val: "<<=", Line: 191, Col: 1, Offset: 898}},
+	{in: ">>=", want: token.Token{Kind: token.ShrAssign, Val: ">>=", Line: 194, Col: 1, Offset: 909}},
+	{in: "&^=", want: token.Token{Kind: token.ClearAssign, Val: "&^=", Line: 197, Col: 1, Offset: 920}},
+	{in: "&&", want: token.Token{Kind: token.Land, Val: "&&", Line: 200, Col: 1, Offset: 931}},
+	{in: "||", want: token.Token{Kind: token.Lor, Val: "||", Line: 203, Col: 1, Offset: 941}},
+	{in: "<-", want: token.Token{Kind: token.Arrow, Val: "<-", Line: 206, Col: 1, Offset: 951}},
+	{in: "++", want: token.Token{Kind: token.Inc, Val: "++", Line: 209, Col: 1, Offset: 961}},
+	{in: "--", want: token.Token{Kind: token.Dec, Val: "--", Line: 212, Col: 1, Offset: 971}},
+	{in: "==", want: token.Token{Kind: token.Eq, Val: "==", Line: 215, Col: 1, Offset: 981}},
+	{in: "<", want: token.Token{Kind: token.Lt, Val: "<", Line: 218, Col: 1, Offset: 991}},
+	{in: ">", want: token.Token{Kind: token.Gt, Val: ">", Line: 221, Col: 1, Offset: 1000}},
+	{in: "=", want: token.Token{Kind: token.Assign, Val: "=", Line: 224, Col: 1, Offset: 1009}},
+	{in: "!", want: token.Token{Kind: token.Not, Val: "!", Line: 227, Col: 1, Offset: 1018}},
+	{in: "!=", want: token.Token{Kind: token.Neq, Val: "!=", Line: 230, Col: 1, Offset: 1027}},
+	{in: "<=", want: token.Token{Kind: token.Lte, Val: "<=", Line: 233, Col: 1, Offset: 1037}},
+	{in: ">=", want: token.Token{Kind: token.Gte, Val: ">=", Line: 236, Col: 1, Offset: 1047}},
+	{in: ":=", want: token.Token{Kind: token.DeclAssign, Val: ":=", Line: 239, Col: 1, Offset: 1057}},
+	{in: "...", want: token.Token{Kind: token.Ellipsis, Val: "...", Line: 242, Col: 1, Offset: 1067}},
+	{in: "(", want: token.Token{Kind: token.Lparen, Val: "(", Line: 245, Col: 1, Offset: 1078}},
+	{in: "[", want: token.Token{Kind: token.Lbrack, Val: "[", Line: 248, Col: 1, Offset: 1087}},
+	{in: "{", want: token.Token{Kind: token.Lbrace, Val: "{", Line: 251, Col: 1, Offset: 1096}},
+	{in: ",", want: token.Token{Kind: token.Comma, Val: ",", Line: 254, Col: 1, Offset: 1105}},
+	{in: ".", want: token.Token{Kind: token.Dot, Val: ".", Line: 257, Col: 1, Offset: 1114}},
+	{in: ")", want: token.Token{Kind: token.Rparen, Val: ")", Line: 260, Col: 1, Offset: 1123}},
+	{in: "]", want: token.Token{Kind: token.Rbrack, Val: "]", Line: 263, Col: 1, Offset: 1132}},
+	{in: "}", want: token.Token{Kind: token.Rbrace, Val: "}", Line: 266, Col: 1, Offset: 1141}},
+	{in: ";", want: token.Token{Kind: token.Semicolon, Val: ";", Line: 269, Col: 1, Offset: 1150}},
+	{in: ":", want: token.Token{Kind: token.Colon, Val: ":", Line: 272, Col: 1, Offset: 1159}},
 
 	// Keywords
-	{in: "break", want: token.Token{Kind: token.Break, Val: "break", Line: 275, Col: 1}},
-	{in: "case", want: token.Token{Kind: token.Case, Val: "case", Line: 278, Col: 1}},
-	{in: "chan", want: token.Token{Kind: token.Chan, Val: "chan", Line: 281, Col: 1}},
-	{in: "const", want: token.Token{Kind: token.Const, Val: "const", Line: 284, Col: 1}},
-	{in: "continue", want: token.Token{Kind: token.Continue, Val: "continue", Line: 287, Col: 1}},
-	{in: "default", want: token.Token{Kind: token.Default, Val: "default", Line: 290, Col: 1}},
-	{in: "defer", want: token.Token{Kind: token.Defer, Val: "defer", Line: 293, Col: 1}},
-	{in: "else", want: token.Token{Kind: token.Else, Val: "else", Line: 296, Col: 1}},
-	{in: "fallthrough", want: token.Token{Kind: token.Fallthrough, Val: "fallthrough", Line: 299, Col: 1}},
-	{in: "for", want: token.Token{Kind: token.For, Val: "for", Line: 302, Col: 1}},
-	{in: "func", want: token.Token{Kind: token.Func, Val: "func", Line: 305, Col: 1}},
-	{in: "go", want: token.Token{Kind: token.Go, Val: "go", Line: 308, Col: 1}},
-	{in: "goto", want: token.Token{Kind: token.Goto, Val: "goto", Line: 311, Col: 1}},
-	{in: "if", want: token.Token{Kind: token.If, Val: "if", Line: 314, Col: 1}},
-	{in: "import", want: token.Token{Kind: token.Import, Val: "import", Line: 317, Col: 1}},
-	{in: "interface", want: token.Token{Kind: token.Interface, Val: "interface", Line: 320, Col: 1}},
-	{in: "map", want: token.Token{Kind: token.Map, Val: "map", Line: 323, Col: 1}},
-	{in: "package", want: token.Token{Kind: token.Package, Val: "package", Line: 326, Col: 1}},
-	{in: "range", want: token.Token{Kind: token.Range, Val: "range", Line: 329, Col: 1}},
-	{in: "return", want: token.Token{Kind: token.Return, Val: "return", Line: 332, Col: 1}},
-	{in: "select", want: token.Token{Kind: token.Select, Val: "select", Line: 335, Col: 1}},
-	{in: "struct", want: token.Token{Kind: token.Struct, Val: "struct", Line: 338, Col: 1}},
-	{in: "switch", want: token.Token{Kind: token.Switch, Val: "switch", Line: 341, Col: 1}},
-	{in: "type", want: token.Token{Kind: token.Type, Val: "type", Line: 344, Col: 1}},
-	{in: "var", want: token.Token{Kind: token.Var, Val: "var", Line: 347, Col: 1}},
+	{in: "break", want: token.Token{Kind: token.Break, Val: "break", Line: 275, Col: 1, Offset: 1168}},
+	{in: "case", want: token.Token{Kind: token.Case, Val: "case", Line: 278, Col: 1, Offset: 1181}},
+	{in: "chan", want: token.Token{Kind: token.Chan, Val: "chan", Line: 281, Col: 1, Offset: 1193}},
+	{in: "const", want: token.Token{Kind: token.Const, Val: "const", Line: 284, Col: 1, Offset: 1205}},
+	{in: "continue", want: token.Token{Kind: token.Continue, Val: "continue", Line: 287, Col: 1, Offset: 1218}},
+	{in: "default", want: token.Token{Kind: token.Default, Val: "default", Line: 290, Col: 1, Offset: 1234}},
+	{in: "defer", want: token.Token{Kind: token.Defer, Val: "defer", Line: 293, Col: 1, Offset: 1249}},
+	{in: "else", want: token.Token{Kind: token.Else, Val: "else", Line: 296, Col: 1, Offset: 1262}},
+	{in: "fallthrough", want: token.Token{Kind: token.Fallthrough, Val: "fallthrough", Line: 299, Col: 1, Offset: 1274}},
+	{in: "for", want: token.Token{Kind: token.For, Val: "for", Line: 302, Col: 1, Offset: 1293}},
+	{in: "func", want: token.Token{Kind: token.Func, Val: "func", Line: 305, Col: 1, Offset: 1304}},
+	{in: "go", want: token.Token{Kind: token.Go, Val: "go", Line: 308, Col: 1, Offset: 1316}},
+	{in: "goto", want: token.Token{Kind: token.Goto, Val: "goto", Line: 311, Col: 1, Offset: 1326}},
+	{in: "if", want: token.Token{Kind: token.If, Val: "if", Line: 314, Col: 1, Offset: 1338}},
+	{in: "import", want: token.Token{Kind: token.Import, Val: "import", Line: 317, Col: 1, Offset: 1348}},
+	{in: "interface", want: token.Token{Kind: token.Interface, Val: "interface", Line: 320, Col: 1, Offset: 1362}},
+	{in: "map", want: token.Token{Kind: token.Map, Val: "map", Line: 323, Col: 1, Offset: 1379}},
+	{in: "package", want: token.Token{Kind: token.Package, Val: "package", Line: 326, Col: 1, Offset: 1390}},
+	{in: "range", want: token.Token{Kind: token.Range, Val: "range", Line: 329, Col: 1, Offset: 1405}},
+	{in: "return", want: token.Token{Kind: token.Return, Val: "return", Line: 332, Col: 1, Offset: 1418}},
+	{in: "select", want: token.Token{Kind: token.Select, Val: "select", Line: 335, Col: 1, Offset: 1432}},
+	{in: "struct", want: token.Token{Kind: token.Struct, Val: "struct", Line: 338, Col: 1, Offset: 1446}},
+	{in: "switch", want: token.Token{Kind: token.Switch, Val: "switch", Line: 341, Col: 1, Offset: 1460}},
+	{in: "type", want: token.Token{Kind: token.Type, Val: "type", Line: 344, Col: 1, Offset: 1474}},
+	{in: "var", want: token.Token{Kind: token.Var, Val: "var", Line: 347, Col: 1, Offset: 1486}},
 }
 
 // source contains each token of golden separated by white space.
@@ -152,16 +160,18 @@ func init() {
 
 func TestParse(t *testing.T) {
 	// Disable insertion of semicolons.
-	f := insertSemicolon
-	insertSemicolon = func(*lexer) {}
-	defer func() {
-		// Enable insertion of semicolons.
-		insertSemicolon = f
-	}()
-
-	tokens, err := Parse(source)
-	if err != nil {
-		t.Fatalf("Parse failed; %v", err)
+	lx := New(source)
+	lx.NoSemicolons = true
+	var tokens []token.Token
+	for {
+		tok, ok := lx.Next()
+		if !ok {
+			break
+		}
+		tokens = append(tokens, tok)
+	}
+	if errs := lx.Errors(); len(errs) > 0 {
+		t.Fatalf("Lexer failed; %v", errs)
 	}
 	for i, g := range golden {
 		if i >= len(tokens) {
@@ -182,124 +192,125 @@ func TestParseInsertSemicolon(t *testing.T) {
 		want []token.Token
 	}{
 		{in: "", want: []token.Token{}},
-		{in: "\ufeff;", want: []token.Token{{Kind: token.Semicolon, Val: ";", Line: 1, Col: 1}}},                                                       // first BOM is ignored; a semicolon is present in the source
-		{in: ";", want: []token.Token{{Kind: token.Semicolon, Val: ";", Line: 1, Col: 1}}},                                                             // a semicolon is present in the source
-		{in: "foo\n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}}},       // a semicolon was automatically inserted.
-		{in: "123\n", want: []token.Token{{Kind: token.Int, Val: "123", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}}},         // a semicolon was automatically inserted.
-		{in: "1.2\n", want: []token.Token{{Kind: token.Float, Val: "1.2", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}}},       // a semicolon was automatically inserted.
-		{in: "'x'\n", want: []token.Token{{Kind: token.Rune, Val: "'x'", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}}},        // a semicolon was automatically inserted.
-		{in: `"x"` + "\n", want: []token.Token{{Kind: token.String, Val: `"x"`, Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}}}, // a semicolon was automatically inserted.
-		{in: "`x`\n", want: []token.Token{{Kind: token.String, Val: "`x`", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}}},      // a semicolon was automatically inserted.
-
-		{in: "+\n", want: []token.Token{{Kind: token.Add, Val: "+", Line: 1, Col: 1}}},
-		{in: "-\n", want: []token.Token{{Kind: token.Sub, Val: "-", Line: 1, Col: 1}}},
-		{in: "*\n", want: []token.Token{{Kind: token.Mul, Val: "*", Line: 1, Col: 1}}},
-		{in: "/\n", want: []token.Token{{Kind: token.Div, Val: "/", Line: 1, Col: 1}}},
-		{in: "%\n", want: []token.Token{{Kind: token.Mod, Val: "%", Line: 1, Col: 1}}},
-
-		{in: "&\n", want: []token.Token{{Kind: token.And, Val: "&", Line: 1, Col: 1}}},
-		{in: "|\n", want: []token.Token{{Kind: token.Or, Val: "|", Line: 1, Col: 1}}},
-		{in: "^\n", want: []token.Token{{Kind: token.Xor, Val: "^", Line: 1, Col: 1}}},
-		{in: "<<\n", want: []token.Token{{Kind: token.Shl, Val: "<<", Line: 1, Col: 1}}},
-		{in: ">>\n", want: []token.Token{{Kind: token.Shr, Val: ">>", Line: 1, Col: 1}}},
-		{in: "&^\n", want: []token.Token{{Kind: token.Clear, Val: "&^", Line: 1, Col: 1}}},
-
-		{in: "+=\n", want: []token.Token{{Kind: token.AddAssign, Val: "+=", Line: 1, Col: 1}}},
-		{in: "-=\n", want: []token.Token{{Kind: token.SubAssign, Val: "-=", Line: 1, Col: 1}}},
-		{in: "*=\n", want: []token.Token{{Kind: token.MulAssign, Val: "*=", Line: 1, Col: 1}}},
-		{in: "/=\n", want: []token.Token{{Kind: token.DivAssign, Val: "/=", Line: 1, Col: 1}}},
-		{in: "%=\n", want: []token.Token{{Kind: token.ModAssign, Val: "%=", Line: 1, Col: 1}}},
-
-		{in: "&=\n", want: []token.Token{{Kind: token.AndAssign, Val: "&=", Line: 1, Col: 1}}},
-		{in: "|=\n", want: []token.Token{{Kind: token.OrAssign, Val: "|=", Line: 1, Col: 1}}},
-		{in: "^=\n", want: []token.Token{{Kind: token.XorAssign, Val: "^=", Line: 1, Col: 1}}},
-		{in: "<<=\n", want: []token.Token{{Kind: token.ShlAssign, Val: "<<=", Line: 1, Col: 1}}},
-		{in: ">>=\n", want: []token.Token{{Kind: token.ShrAssign, Val: ">>=", Line: 1, Col: 1}}},
-		{in: "&^=\n", want: []token.Token{{Kind: token.ClearAssign, Val: "&^=", Line: 1, Col: 1}}},
-
-		{in: "&&\n", want: []token.Token{{Kind: token.Land, Val: "&&", Line: 1, Col: 1}}},
-		{in: "||\n", want: []token.Token{{Kind: token.Lor, Val: "||", Line: 1, Col: 1}}},
-		{in: "<-\n", want: []token.Token{{Kind: token.Arrow, Val: "<-", Line: 1, Col: 1}}},
-		{in: "++\n", want: []token.Token{{Kind: token.Inc, Val: "++", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 3}}}, // a semicolon was automatically inserted.
-		{in: "--\n", want: []token.Token{{Kind: token.Dec, Val: "--", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 3}}}, // a semicolon was automatically inserted.
-
-		{in: "==\n", want: []token.Token{{Kind: token.Eq, Val: "==", Line: 1, Col: 1}}},
-		{in: "<\n", want: []token.Token{{Kind: token.Lt, Val: "<", Line: 1, Col: 1}}},
-		{in: ">\n", want: []token.Token{{Kind: token.Gt, Val: ">", Line: 1, Col: 1}}},
-		{in: "=\n", want: []token.Token{{Kind: token.Assign, Val: "=", Line: 1, Col: 1}}},
-		{in: "!\n", want: []token.Token{{Kind: token.Not, Val: "!", Line: 1, Col: 1}}},
-
-		{in: "!=\n", want: []token.Token{{Kind: token.Neq, Val: "!=", Line: 1, Col: 1}}},
-		{in: "<=\n", want: []token.Token{{Kind: token.Lte, Val: "<=", Line: 1, Col: 1}}},
-		{in: ">=\n", want: []token.Token{{Kind: token.Gte, Val: ">=", Line: 1, Col: 1}}},
-		{in: ":=\n", want: []token.Token{{Kind: token.DeclAssign, Val: ":=", Line: 1, Col: 1}}},
-		{in: "...\n", want: []token.Token{{Kind: token.Ellipsis, Val: "...", Line: 1, Col: 1}}},
-
-		{in: "(\n", want: []token.Token{{Kind: token.Lparen, Val: "(", Line: 1, Col: 1}}},
-		{in: "[\n", want: []token.Token{{Kind: token.Lbrack, Val: "[", Line: 1, Col: 1}}},
-		{in: "{\n", want: []token.Token{{Kind: token.Lbrace, Val: "{", Line: 1, Col: 1}}},
-		{in: ",\n", want: []token.Token{{Kind: token.Comma, Val: ",", Line: 1, Col: 1}}},
-		{in: ".\n", want: []token.Token{{Kind: token.Dot, Val: ".", Line: 1, Col: 1}}},
-
-		{in: ")\n", want: []token.Token{{Kind: token.Rparen, Val: ")", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 2}}}, // a semicolon was automatically inserted.
-		{in: "]\n", want: []token.Token{{Kind: token.Rbrack, Val: "]", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 2}}}, // a semicolon was automatically inserted.
-		{in: "}\n", want: []token.Token{{Kind: token.Rbrace, Val: "}", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 2}}}, // a semicolon was automatically inserted.
-		{in: ";\n", want: []token.Token{{Kind: token.Semicolon, Val: ";", Line: 1, Col: 1}}},                                                  // a semicolon is present in the source
-		{in: ":\n", want: []token.Token{{Kind: token.Colon, Val: ":", Line: 1, Col: 1}}},
-
-		{in: "break\n", want: []token.Token{{Kind: token.Break, Val: "break", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 6}}}, // a semicolon was automatically inserted.
-		{in: "case\n", want: []token.Token{{Kind: token.Case, Val: "case", Line: 1, Col: 1}}},
-		{in: "chan\n", want: []token.Token{{Kind: token.Chan, Val: "chan", Line: 1, Col: 1}}},
-		{in: "const\n", want: []token.Token{{Kind: token.Const, Val: "const", Line: 1, Col: 1}}},
-		{in: "continue\n", want: []token.Token{{Kind: token.Continue, Val: "continue", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 9}}}, // a semicolon was automatically inserted.
-
-		{in: "default\n", want: []token.Token{{Kind: token.Default, Val: "default", Line: 1, Col: 1}}},
-		{in: "defer\n", want: []token.Token{{Kind: token.Defer, Val: "defer", Line: 1, Col: 1}}},
-		{in: "else\n", want: []token.Token{{Kind: token.Else, Val: "else", Line: 1, Col: 1}}},
-		{in: "fallthrough\n", want: []token.Token{{Kind: token.Fallthrough, Val: "fallthrough", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 12}}}, // a semicolon was automatically inserted.
-		{in: "for\n", want: []token.Token{{Kind: token.For, Val: "for", Line: 1, Col: 1}}},
-
-		{in: "func\n", want: []token.Token{{Kind: token.Func, Val: "func", Line: 1, Col: 1}}},
-		{in: "go\n", want: []token.Token{{Kind: token.Go, Val: "go", Line: 1, Col: 1}}},
-		{in: "goto\n", want: []token.Token{{Kind: token.Goto, Val: "goto", Line: 1, Col: 1}}},
-		{in: "if\n", want: []token.Token{{Kind: token.If, Val: "if", Line: 1, Col: 1}}},
-		{in: "import\n", want: []token.Token{{Kind: token.Import, Val: "import", Line: 1, Col: 1}}},
-
-		{in: "interface\n", want: []token.Token{{Kind: token.Interface, Val: "interface", Line: 1, Col: 1}}},
-		{in: "map\n", want: []token.Token{{Kind: token.Map, Val: "map", Line: 1, Col: 1}}},
-		{in: "package\n", want: []token.Token{{Kind: token.Package, Val: "package", Line: 1, Col: 1}}},
-		{in: "range\n", want: []token.Token{{Kind: token.Range, Val: "range", Line: 1, Col: 1}}},
-		{in: "return\n", want: []token.Token{{Kind: token.Return, Val: "return", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 7}}}, // a semicolon was automatically inserted.
-
-		{in: "select\n", want: []token.Token{{Kind: token.Select, Val: "select", Line: 1, Col: 1}}},
-		{in: "struct\n", want: []token.Token{{Kind: token.Struct, Val: "struct", Line: 1, Col: 1}}},
-		{in: "switch\n", want: []token.Token{{Kind: token.Switch, Val: "switch", Line: 1, Col: 1}}},
-		{in: "type\n", want: []token.Token{{Kind: token.Type, Val: "type", Line: 1, Col: 1}}},
-		{in: "var\n", want: []token.Token{{Kind: token.Var, Val: "var", Line: 1, Col: 1}}},
-
-		{in: "foo//comment\n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}, {Kind: token.Comment, Val: "//comment", Line: 1, Col: 4}}},         // a semicolon was automatically inserted.
-		{in: "foo//comment", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}, {Kind: token.Comment, Val: "//comment", Line: 1, Col: 4}}},           // a semicolon was automatically inserted.
-		{in: "foo/*comment*/\n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}, {Kind: token.Comment, Val: "/*comment*/", Line: 1, Col: 4}}},     // a semicolon was automatically inserted.
-		{in: "foo/*\n*/", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}, {Kind: token.Comment, Val: "/*\n*/", Line: 1, Col: 4}}},                 // a semicolon was automatically inserted.
-		{in: "foo/*comment*/    \n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}, {Kind: token.Comment, Val: "/*comment*/", Line: 1, Col: 4}}}, // a semicolon was automatically inserted.
-		{in: "foo/*\n*/    ", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}, {Kind: token.Comment, Val: "/*\n*/", Line: 1, Col: 4}}},             // a semicolon was automatically inserted.
-
-		{in: "foo    // comment\n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}, {Kind: token.Comment, Val: "// comment", Line: 1, Col: 8}}},                                                                                                                                                                                                                               // a semicolon was automatically inserted.
-		{in: "foo    // comment", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}, {Kind: token.Comment, Val: "// comment", Line: 1, Col: 8}}},                                                                                                                                                                                                                                 // a semicolon was automatically inserted.
-		{in: "foo    /*comment*/\n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}, {Kind: token.Comment, Val: "/*comment*/", Line: 1, Col: 8}}},                                                                                                                                                                                                                             // a semicolon was automatically inserted.
-		{in: "foo    /*\n*/", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}, {Kind: token.Comment, Val: "/*\n*/", Line: 1, Col: 8}}},                                                                                                                                                                                                                                         // a semicolon was automatically inserted.
-		{in: "foo    /*  */ /* \n */ bar/**/\n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}, {Kind: token.Comment, Val: "/*  */", Line: 1, Col: 8}, {Kind: token.Comment, Val: "/* \n */", Line: 1, Col: 15}, {Kind: token.Ident, Val: "bar", Line: 2, Col: 5}, {Kind: token.Semicolon, Val: ";", Line: 2, Col: 8}, {Kind: token.Comment, Val: "/**/", Line: 2, Col: 8}}}, // a semicolon was automatically inserted.
-		{in: "foo    /*0*/ /*1*/ /*2*/\n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}, {Kind: token.Comment, Val: "/*0*/", Line: 1, Col: 8}, {Kind: token.Comment, Val: "/*1*/", Line: 1, Col: 14}, {Kind: token.Comment, Val: "/*2*/", Line: 1, Col: 20}}},                                                                                                               // a semicolon was automatically inserted.
-
-		{in: "foo    /*comment*/    \n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}, {Kind: token.Comment, Val: "/*comment*/", Line: 1, Col: 8}}},                                                                                                               // a semicolon was automatically inserted.
-		{in: "foo    /*0*/ /*1*/ /*2*/    \n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}, {Kind: token.Comment, Val: "/*0*/", Line: 1, Col: 8}, {Kind: token.Comment, Val: "/*1*/", Line: 1, Col: 14}, {Kind: token.Comment, Val: "/*2*/", Line: 1, Col: 20}}}, // a semicolon was automatically inserted.
-		{in: "foo	/**/ /*-------------*/       /*----\n*/bar       /*  \n*/baa\n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}, {Kind: token.Comment, Val: "/**/", Line: 1, Col: 5}, {Kind: token.Comment, Val: "/*-------------*/", Line: 1, Col: 10}, {Kind: token.Comment, Val: "/*----\n*/", Line: 1, Col: 34}, {Kind: token.Ident, Val: "bar", Line: 2, Col: 3}, {Kind: token.Semicolon, Val: ";", Line: 2, Col: 6}, {Kind: token.Comment, Val: "/*  \n*/", Line: 2, Col: 13}, {Kind: token.Ident, Val: "baa", Line: 3, Col: 3}, {Kind: token.Semicolon, Val: ";", Line: 3, Col: 6}}}, // a semicolon was automatically inserted.
-		{in: "foo    /* an EOF terminates a line */", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}, {Kind: token.Comment, Val: "/* an EOF terminates a line */", Line: 1, Col: 8}}},                                                                                                          // a semicolon was automatically inserted.
-		{in: "foo    /* an EOF terminates a line */ /*", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}, {Kind: token.Comment, Val: "/* an EOF terminates a line */", Line: 1, Col: 8}, {Kind: token.Comment | token.Invalid, Val: "/*", Line: 1, Col: 39}}, err: "unexpected eof in comment"}, // a semicolon was automatically inserted.
-		{in: "foo    /* an EOF terminates a line */ //", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4}, {Kind: token.Comment, Val: "/* an EOF terminates a line */", Line: 1, Col: 8}, {Kind: token.Comment, Val: "//", Line: 1, Col: 39}}},                                                   // a semicolon was automatically inserted.
-
-		{in: "package main\n\nfunc main() {\n\tif {\n\t\treturn /* */ }\n}\n", want: []token.Token{{Kind: token.Package, Val: "package", Line: 1, Col: 1}, {Kind: token.Ident, Val: "main", Line: 1, Col: 9}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 13}, {Kind: token.Func, Val: "func", Line: 3, Col: 1}, {Kind: token.Ident, Val: "main", Line: 3, Col: 6}, {Kind: token.Lparen, Val: "(", Line: 3, Col: 10}, {Kind: token.Rparen, Val: ")", Line: 3, Col: 11}, {Kind: token.Lbrace, Val: "{", Line: 3, Col: 13}, {Kind: token.If, Val: "if", Line: 4, Col: 2}, {Kind: token.Lbrace, Val: "{", Line: 4, Col: 5}, {Kind: token.Return, Val: "return", Line: 5, Col: 3}, {Kind: token.Comment, Val: "/* */", Line: 5, Col: 10}, {Kind: token.Rbrace, Val: "}", Line: 5, Col: 16}, {Kind: token.Semicolon, Val: ";", Line: 5, Col: 17}, {Kind: token.Rbrace, Val: "}", Line: 6, Col: 1}, {Kind: token.Semicolon, Val: ";", Line: 6, Col: 2}}}, // a semicolon was automatically inserted.
-		{in: "package main", want: []token.Token{{Kind: token.Package, Val: "package", Line: 1, Col: 1}, {Kind: token.Ident, Val: "main", Line: 1, Col: 9}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 13}}}, // a semicolon was automatically inserted.
+		{in: "\ufeff;", want: []token.Token{{Kind: token.Semicolon, Val: ";", Line: 1, Col: 1, Offset: 3}}},                                                                  // first BOM is ignored; a semicolon is present in the source
+		{in: ";", want: []token.Token{{Kind: token.Semicolon, Val: ";", Line: 1, Col: 1, Offset: 0}}},                                                                        // a semicolon is present in the source
+		{in: "foo\n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}}},       // a semicolon was automatically inserted.
+		{in: "123\n", want: []token.Token{{Kind: token.Int, Val: "123", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}}},         // a semicolon was automatically inserted.
+		{in: "1.2\n", want: []token.Token{{Kind: token.Float, Val: "1.2", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}}},       // a semicolon was automatically inserted.
+		{in: "'x'\n", want: []token.Token{{Kind: token.Rune, Val: "'x'", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}}},        // a semicolon was automatically inserted.
+		{in: `"x"` + "\n", want: []token.Token{{Kind: token.String, Val: `"x"`, Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}}}, // a semicolon was automatically inserted.
+		{in: "`x`\n", want: []token.Token{{Kind: token.String, Val: "`x`", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}}},      // a semicolon was automatically inserted.
+
+		{in: "+\n", want: []token.Token{{Kind: token.Add, Val: "+", Line: 1, Col: 1, Offset: 0}}},
+		{in: "-\n", want: []token.Token{{Kind: token.Sub, Val: "-", Line: 1, Col: 1, Offset: 0}}},
+		{in: "*\n", want: []token.Token{{Kind: token.Mul, Val: "*", Line: 1, Col: 1, Offset: 0}}},
+		{in: "/\n", want: []token.Token{{Kind: token.Div, Val: "/", Line: 1, Col: 1, Offset: 0}}},
+		{in: "%\n", want: []token.Token{{Kind: token.Mod, Val: "%", Line: 1, Col: 1, Offset: 0}}},
+
+		{in: "&\n", want: []token.Token{{Kind: token.And, Val: "&", Line: 1, Col: 1, Offset: 0}}},
+		{in: "|\n", want: []token.Token{{Kind: token.Or, Val: "|", Line: 1, Col: 1, Offset: 0}}},
+		{in: "^\n", want: []token.Token{{Kind: token.Xor, Val: "^", Line: 1, Col: 1, Offset: 0}}},
+		{in: "<<\n", want: []token.Token{{Kind: token.Shl, Val: "<<", Line: 1, Col: 1, Offset: 0}}},
+		{in: ">>\n", want: []token.Token{{Kind: token.Shr, Val: ">>", Line: 1, Col: 1, Offset: 0}}},
+		{in: "&^\n", want: []token.Token{{Kind: token.Clear, Val: "&^", Line: 1, Col: 1, Offset: 0}}},
+
+		{in: "+=\n", want: []token.Token{{Kind: token.AddAssign, Val: "+=", Line: 1, Col: 1, Offset: 0}}},
+		{in: "-=\n", want: []token.Token{{Kind: token.SubAssign, Val: "-=", Line: 1, Col: 1, Offset: 0}}},
+		{in: "*=\n", want: []token.Token{{Kind: token.MulAssign, Val: "*=", Line: 1, Col: 1, Offset: 0}}},
+		{in: "/=\n", want: []token.Token{{Kind: token.DivAssign, Val: "/=", Line: 1, Col: 1, Offset: 0}}},
+		{in: "%=\n", want: []token.Token{{Kind: token.ModAssign, Val: "%=", Line: 1, Col: 1, Offset: 0}}},
+
+		{in: "&=\n", want: []token.Token{{Kind: token.AndAssign, Val: "&=", Line: 1, Col: 1, Offset: 0}}},
+		{in: "|=\n", want: []token.Token{{Kind: token.OrAssign, Val: "|=", Line: 1, Col: 1, Offset: 0}}},
+		{in: "^=\n", want: []token.Token{{Kind: token.XorAssign, Val: "^=", Line: 1, Col: 1, Offset: 0}}},
+		{in: "<<=\n", want: []token.Token{{Kind: token.ShlAssign, Val: "<<=", Line: 1, Col: 1, Offset: 0}}},
+		{in: ">>=\n", want: []token.Token{{Kind: token.ShrAssign, Val: ">>=", Line: 1, Col: 1, Offset: 0}}},
+		{in: "&^=\n", want: []token.Token{{Kind: token.ClearAssign, Val: "&^=", Line: 1, Col: 1, Offset: 0}}},
+
+		{in: "&&\n", want: []token.Token{{Kind: token.Land, Val: "&&", Line: 1, Col: 1, Offset: 0}}},
+		{in: "||\n", want: []token.Token{{Kind: token.Lor, Val: "||", Line: 1, Col: 1, Offset: 0}}},
+		{in: "<-\n", want: []token.Token{{Kind: token.Arrow, Val: "<-", Line: 1, Col: 1, Offset: 0}}},
+		{in: "++\n", want: []token.Token{{Kind: token.Inc, Val: "++", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 3, Offset: 2}}}, // a semicolon was automatically inserted.
+		{in: "--\n", want: []token.Token{{Kind: token.Dec, Val: "--", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 3, Offset: 2}}}, // a semicolon was automatically inserted.
+
+		{in: "==\n", want: []token.Token{{Kind: token.Eq, Val: "==", Line: 1, Col: 1, Offset: 0}}},
+		{in: "<\n", want: []token.Token{{Kind: token.Lt, Val: "<", Line: 1, Col: 1, Offset: 0}}},
+		{in: ">\n", want: []token.Token{{Kind: token.Gt, Val: ">", Line: 1, Col: 1, Offset: 0}}},
+		{in: "=\n", want: []token.Token{{Kind: token.Assign, Val: "=", Line: 1, Col: 1, Offset: 0}}},
+		{in: "!\n", want: []token.Token{{Kind: token.Not, Val: "!", Line: 1, Col: 1, Offset: 0}}},
+
+		{in: "!=\n", want: []token.Token{{Kind: token.Neq, Val: "!=", Line: 1, Col: 1, Offset: 0}}},
+		{in: "<=\n", want: []token.Token{{Kind: token.Lte, Val: "<=", Line: 1, Col: 1, Offset: 0}}},
+		{in: ">=\n", want: []token.Token{{Kind: token.Gte, Val: ">=", Line: 1, Col: 1, Offset: 0}}},
+		{in: ":=\n", want: []token.Token{{Kind: token.DeclAssign, Val: ":=", Line: 1, Col: 1, Offset: 0}}},
+		{in: "...\n", want: []token.Token{{Kind: token.Ellipsis, Val: "...", Line: 1, Col: 1, Offset: 0}}},
+
+		{in: "(\n", want: []token.Token{{Kind: token.Lparen, Val: "(", Line: 1, Col: 1, Offset: 0}}},
+		{in: "[\n", want: []token.Token{{Kind: token.Lbrack, Val: "[", Line: 1, Col: 1, Offset: 0}}},
+		{in: "{\n", want: []token.Token{{Kind: token.Lbrace, Val: "{", Line: 1, Col: 1, Offset: 0}}},
+		{in: ",\n", want: []token.Token{{Kind: token.Comma, Val: ",", Line: 1, Col: 1, Offset: 0}}},
+		{in: ".\n", want: []token.Token{{Kind: token.Dot, Val: ".", Line: 1, Col: 1, Offset: 0}}},
+
+		{in: ")\n", want: []token.Token{{Kind: token.Rparen, Val: ")", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 2, Offset: 1}}}, // a semicolon was automatically inserted.
+		{in: "]\n", want: []token.Token{{Kind: token.Rbrack, Val: "]", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 2, Offset: 1}}}, // a semicolon was automatically inserted.
+		{in: "}\n", want: []token.Token{{Kind: token.Rbrace, Val: "}", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 2, Offset: 1}}}, // a semicolon was automatically inserted.
+		{in: ";\n", want: []token.Token{{Kind: token.Semicolon, Val: ";", Line: 1, Col: 1, Offset: 0}}},                                                             // a semicolon is present in the source
+		{in: ":\n", want: []token.Token{{Kind: token.Colon, Val: ":", Line: 1, Col: 1, Offset: 0}}},
+
+		{in: "break\n", want: []token.Token{{Kind: token.Break, Val: "break", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 6, Offset: 5}}}, // a semicolon was automatically inserted.
+		{in: "case\n", want: []token.Token{{Kind: token.Case, Val: "case", Line: 1, Col: 1, Offset: 0}}},
+		{in: "chan\n", want: []token.Token{{Kind: token.Chan, Val: "chan", Line: 1, Col: 1, Offset: 0}}},
+		{in: "const\n", want: []token.Token{{Kind: token.Const, Val: "const", Line: 1, Col: 1, Offset: 0}}},
+		{in: "continue\n", want: []token.Token{{Kind: token.Continue, Val: "continue", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 9, Offset: 8}}}, // a semicolon was automatically inserted.
+
+		{in: "default\n", want: []token.Token{{Kind: token.Default, Val: "default", Line: 1, Col: 1, Offset: 0}}},
+		{in: "defer\n", want: []token.Token{{Kind: token.Defer, Val: "defer", Line: 1, Col: 1, Offset: 0}}},
+		{in: "else\n", want: []token.Token{{Kind: token.Else, Val: "else", Line: 1, Col: 1, Offset: 0}}},
+		{in: "fallthrough\n", want: []token.Token{{Kind: token.Fallthrough, Val: "fallthrough", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 12, Offset: 11}}}, // a semicolon was automatically inserted.
+		{in: "for\n", want: []token.Token{{Kind: token.For, Val: "for", Line: 1, Col: 1, Offset: 0}}},
+
+		{in: "func\n", want: []token.Token{{Kind: token.Func, Val: "func", Line: 1, Col: 1, Offset: 0}}},
+		{in: "go\n", want: []token.Token{{Kind: token.Go, Val: "go", Line: 1, Col: 1, Offset: 0}}},
+		{in: "goto\n", want: []token.Token{{Kind: token.Goto, Val: "goto", Line: 1, Col: 1, Offset: 0}}},
+		{in: "if\n", want: []token.Token{{Kind: token.If, Val: "if", Line: 1, Col: 1, Offset: 0}}},
+		{in: "import\n", want: []token.Token{{Kind: token.Import, Val: "import", Line: 1, Col: 1, Offset: 0}}},
+
+		{in: "interface\n", want: []token.Token{{Kind: token.Interface, Val: "interface", Line: 1, Col: 1, Offset: 0}}},
+		{in: "map\n", want: []token.Token{{Kind: token.Map, Val: "map", Line: 1, Col: 1, Offset: 0}}},
+		{in: "package\n", want: []token.Token{{Kind: token.Package, Val: "package", Line: 1, Col: 1, Offset: 0}}},
+		{in: "range\n", want: []token.Token{{Kind: token.Range, Val: "range", Line: 1, Col: 1, Offset: 0}}},
+		{in: "return\n", want: []token.Token{{Kind: token.Return, Val: "return", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 7, Offset: 6}}}, // a semicolon was automatically inserted.
+
+		{in: "select\n", want: []token.Token{{Kind: token.Select, Val: "select", Line: 1, Col: 1, Offset: 0}}},
+		{in: "struct\n", want: []token.Token{{Kind: token.Struct, Val: "struct", Line: 1, Col: 1, Offset: 0}}},
+		{in: "switch\n", want: []token.Token{{Kind: token.Switch, Val: "switch", Line: 1, Col: 1, Offset: 0}}},
+		{in: "type\n", want: []token.Token{{Kind: token.Type, Val: "type", Line: 1, Col: 1, Offset: 0}}},
+		{in: "var\n", want: []token.Token{{Kind: token.Var, Val: "var", Line: 1, Col: 1, Offset: 0}}},
+
+		{in: "foo//comment\n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}, {Kind: token.Comment, Val: "//comment", Line: 1, Col: 4, Offset: 3}}},         // a semicolon was automatically inserted.
+		{in: "foo//comment", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}, {Kind: token.Comment, Val: "//comment", Line: 1, Col: 4, Offset: 3}}},           // a semicolon was automatically inserted.
+		{in: "foo/*comment*/\n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}, {Kind: token.Comment, Val: "/*comment*/", Line: 1, Col: 4, Offset: 3}}},     // a semicolon was automatically inserted.
+		{in: "foo/*\n*/", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}, {Kind: token.Comment, Val: "/*\n*/", Line: 1, Col: 4, Offset: 3}}},                 // a semicolon was automatically inserted.
+		{in: "foo/*comment*/    \n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}, {Kind: token.Comment, Val: "/*comment*/", Line: 1, Col: 4, Offset: 3}}}, // a semicolon was automatically inserted.
+		{in: "foo/*\n*/    ", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}, {Kind: token.Comment, Val: "/*\n*/", Line: 1, Col: 4, Offset: 3}}},             // a semicolon was automatically inserted.
+
+		{in: "foo    // comment\n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}, {Kind: token.Comment, Val: "// comment", Line: 1, Col: 8, Offset: 7}}},                                                                                                                                                                                                                                                                               // a semicolon was automatically inserted.
+		{in: "foo    // comment", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}, {Kind: token.Comment, Val: "// comment", Line: 1, Col: 8, Offset: 7}}},                                                                                                                                                                                                                                                                                 // a semicolon was automatically inserted.
+		{in: "foo    /*comment*/\n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}, {Kind: token.Comment, Val: "/*comment*/", Line: 1, Col: 8, Offset: 7}}},                                                                                                                                                                                                                                                                             // a semicolon was automatically inserted.
+		{in: "foo    /*\n*/", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}, {Kind: token.Comment, Val: "/*\n*/", Line: 1, Col: 8, Offset: 7}}},                                                                                                                                                                                                                                                                                         // a semicolon was automatically inserted.
+		{in: "foo    /*  */ /* \n */ bar/**/\n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}, {Kind: token.Comment, Val: "/*  */", Line: 1, Col: 8, Offset: 7}, {Kind: token.Comment, Val: "/* \n */", Line: 1, Col: 15, Offset: 14}, {Kind: token.Ident, Val: "bar", Line: 2, Col: 5, Offset: 22}, {Kind: token.Semicolon, Val: ";", Line: 2, Col: 8, Offset: 25}, {Kind: token.Comment, Val: "/**/", Line: 2, Col: 8, Offset: 25}}}, // a semicolon was automatically inserted.
+		{in: "foo    /*0*/ /*1*/ /*2*/\n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}, {Kind: token.Comment, Val: "/*0*/", Line: 1, Col: 8, Offset: 7}, {Kind: token.Comment, Val: "/*1*/", Line: 1, Col: 14, Offset: 13}, {Kind: token.Comment, Val: "/*2*/", Line: 1, Col: 20, Offset: 19}}},                                                                                                                                       // a semicolon was automatically inserted.
+
+		{in: "foo    /*comment*/    \n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}, {Kind: token.Comment, Val: "/*comment*/", Line: 1, Col: 8, Offset: 7}}},                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                            // a semicolon was automatically inserted.
+		{in: "foo    /*0*/ /*1*/ /*2*/    \n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}, {Kind: token.Comment, Val: "/*0*/", Line: 1, Col: 8, Offset: 7}, {Kind: token.Comment, Val: "/*1*/", Line: 1, Col: 14, Offset: 13}, {Kind: token.Comment, Val: "/*2*/", Line: 1, Col: 20, Offset: 19}}},                                                                                                                                                                                                                                                                                                                                                                                      // a semicolon was automatically inserted.
+		{in: "foo	/**/ /*-------------*/       /*----\n*/bar       /*  \n*/baa\n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}, {Kind: token.Comment, Val: "/**/", Line: 1, Col: 5, Offset: 4}, {Kind: token.Comment, Val: "/*-------------*/", Line: 1, Col: 10, Offset: 9}, {Kind: token.Comment, Val: "/*----\n*/", Line: 1, Col: 34, Offset: 33}, {Kind: token.Ident, Val: "bar", Line: 2, Col: 3, Offset: 42}, {Kind: token.Semicolon, Val: ";", Line: 2, Col: 6, Offset: 45}, {Kind: token.Comment, Val: "/*  \n*/", Line: 2, Col: 13, Offset: 52}, {Kind: token.Ident, Val: "baa", Line: 3, Col: 3, Offset: 59}, {Kind: token.Semicolon, Val: ";", Line: 3, Col: 6, Offset: 62}}}, // a semicolon was automatically inserted.
+		{in: "foo /*/ */\n", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}, {Kind: token.Comment, Val: "/*/ */", Line: 1, Col: 5, Offset: 4}}},                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                             // the slash of /*/ does not terminate the comment.
+		{in: "foo    /* an EOF terminates a line */", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}, {Kind: token.Comment, Val: "/* an EOF terminates a line */", Line: 1, Col: 8, Offset: 7}}},                                                                                                                                                                                                                                                                                                                                                                                                                                                                                            // a semicolon was automatically inserted.
+		{in: "foo    /* an EOF terminates a line */ /*", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}, {Kind: token.Comment, Val: "/* an EOF terminates a line */", Line: 1, Col: 8, Offset: 7}, {Kind: token.Comment | token.Invalid, Val: "/*", Line: 1, Col: 39, Offset: 38}}, err: "unexpected eof in comment"},                                                                                                                                                                                                                                                                                                                                                                       // a semicolon was automatically inserted.
+		{in: "foo    /* an EOF terminates a line */ //", want: []token.Token{{Kind: token.Ident, Val: "foo", Line: 1, Col: 1, Offset: 0}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3}, {Kind: token.Comment, Val: "/* an EOF terminates a line */", Line: 1, Col: 8, Offset: 7}, {Kind: token.Comment, Val: "//", Line: 1, Col: 39, Offset: 38}}},                                                                                                                                                                                                                                                                                                                                                                                                                         // a semicolon was automatically inserted.
+
+		{in: "package main\n\nfunc main() {\n\tif {\n\t\treturn /* */ }\n}\n", want: []token.Token{{Kind: token.Package, Val: "package", Line: 1, Col: 1, Offset: 0}, {Kind: token.Ident, Val: "main", Line: 1, Col: 9, Offset: 8}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 13, Offset: 12}, {Kind: token.Func, Val: "func", Line: 3, Col: 1, Offset: 14}, {Kind: token.Ident, Val: "main", Line: 3, Col: 6, Offset: 19}, {Kind: token.Lparen, Val: "(", Line: 3, Col: 10, Offset: 23}, {Kind: token.Rparen, Val: ")", Line: 3, Col: 11, Offset: 24}, {Kind: token.Lbrace, Val: "{", Line: 3, Col: 13, Offset: 26}, {Kind: token.If, Val: "if", Line: 4, Col: 2, Offset: 29}, {Kind: token.Lbrace, Val: "{", Line: 4, Col: 5, Offset: 32}, {Kind: token.Return, Val: "return", Line: 5, Col: 3, Offset: 36}, {Kind: token.Comment, Val: "/* */", Line: 5, Col: 10, Offset: 43}, {Kind: token.Rbrace, Val: "}", Line: 5, Col: 16, Offset: 49}, {Kind: token.Semicolon, Val: ";", Line: 5, Col: 17, Offset: 50}, {Kind: token.Rbrace, Val: "}", Line: 6, Col: 1, Offset: 51}, {Kind: token.Semicolon, Val: ";", Line: 6, Col: 2, Offset: 52}}}, // a semicolon was automatically inserted.
+		{in: "package main", want: []token.Token{{Kind: token.Package, Val: "package", Line: 1, Col: 1, Offset: 0}, {Kind: token.Ident, Val: "main", Line: 1, Col: 9, Offset: 8}, {Kind: token.Semicolon, Val: ";", Line: 1, Col: 13, Offset: 12}}}, // a semicolon was automatically inserted.
 	}
 
 	for i, g := range golden {
@@ -314,97 +325,178 @@ func TestParseInsertSemicolon(t *testing.T) {
 	}
 }
 
-func TestParseErrors(t *testing.T) {
-	// test cases derived from errors in go/src/pkg/scanner/scanner_test.go
+func TestParseInsertSemicolonEOF(t *testing.T) {
+	// A semicolon is automatically inserted at EOF, even if the final line has no
+	// terminating newline.
 	golden := []struct {
 		in   string
-		err  string
-		want token.Token
+		want []token.Token
 	}{
-		{in: "\a", err: "syntax error: unexpected U+0007", want: token.Token{Kind: token.Invalid, Val: "\a", Line: 1, Col: 1}},
-		{in: `#`, err: "syntax error: unexpected U+0023 '#'", want: token.Token{Kind: token.Invalid, Val: `#`, Line: 1, Col: 1}},
-		{in: `…`, err: "syntax error: unexpected U+2026 '…'", want: token.Token{Kind: token.Invalid, Val: `…`, Line: 1, Col: 1}},
-		{in: `' '`, want: token.Token{Kind: token.Rune, Val: "' '", Line: 1, Col: 1}},
-		{in: `''`, err: "empty rune literal or unescaped ' in rune literal", want: token.Token{Kind: token.Rune | token.Invalid, Val: "''", Line: 1, Col: 1}},
-		{in: `'12'`, err: "too many characters in rune literal", want: token.Token{Kind: token.Rune | token.Invalid, Val: "'12'", Line: 1, Col: 1}},
-		{in: `'123'`, err: "too many characters in rune literal", want: token.Token{Kind: token.Rune | token.Invalid, Val: "'123'", Line: 1, Col: 1}},
-		{in: `'\0'`, err: "too few digits in octal escape; expected 3, got 1", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\0'`, Line: 1, Col: 1}},
-		{in: `'\07'`, err: "too few digits in octal escape; expected 3, got 2", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\07'`, Line: 1, Col: 1}},
-		{in: `'\8'`, err: "unknown escape sequence U+0038 '8'", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\8'`, Line: 1, Col: 1}},
-		{in: `'\08'`, err: "non-octal character U+0038 '8' in octal escape", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\08'`, Line: 1, Col: 1}},
-		{in: `'\0`, err: "unexpected eof in octal escape", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\0`, Line: 1, Col: 1}},
-		{in: `'\00`, err: "unexpected eof in octal escape", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\00`, Line: 1, Col: 1}},
-		{in: `'\000`, err: "unexpected eof in rune literal", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\000`, Line: 1, Col: 1}},
-		{in: `'\x'`, err: "too few digits in hex escape; expected 2, got 0", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\x'`, Line: 1, Col: 1}},
-		{in: `'\x0'`, err: "too few digits in hex escape; expected 2, got 1", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\x0'`, Line: 1, Col: 1}},
-		{in: `'\x0g'`, err: "non-hex character U+0067 'g' in hex escape", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\x0g'`, Line: 1, Col: 1}},
-		{in: `'\x`, err: "unexpected eof in hex escape", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\x`, Line: 1, Col: 1}},
-		{in: `'\x0`, err: "unexpected eof in hex escape", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\x0`, Line: 1, Col: 1}},
-		{in: `'\x00`, err: "unexpected eof in rune literal", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\x00`, Line: 1, Col: 1}},
-		{in: `'\u'`, err: "too few digits in Unicode escape; expected 4, got 0", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\u'`, Line: 1, Col: 1}},
-		{in: `'\u0'`, err: "too few digits in Unicode escape; expected 4, got 1", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\u0'`, Line: 1, Col: 1}},
-		{in: `'\u00'`, err: "too few digits in Unicode escape; expected 4, got 2", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\u00'`, Line: 1, Col: 1}},
-		{in: `'\u000'`, err: "too few digits in Unicode escape; expected 4, got 3", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\u000'`, Line: 1, Col: 1}},
-		{in: `'\u000`, err: "unexpected eof in Unicode escape", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\u000`, Line: 1, Col: 1}},
-		{in: `'\u0000'`, want: token.Token{Kind: token.Rune, Val: `'\u0000'`, Line: 1, Col: 1}},
-		{in: `'\U'`, err: "too few digits in Unicode escape; expected 8, got 0", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\U'`, Line: 1, Col: 1}},
-		{in: `'\U0'`, err: "too few digits in Unicode escape; expected 8, got 1", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\U0'`, Line: 1, Col: 1}},
-		{in: `'\U00'`, err: "too few digits in Unicode escape; expected 8, got 2", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\U00'`, Line: 1, Col: 1}},
-		{in: `'\U000'`, err: "too few digits in Unicode escape; expected 8, got 3", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\U000'`, Line: 1, Col: 1}},
-		{in: `'\U0000'`, err: "too few digits in Unicode escape; expected 8, got 4", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\U0000'`, Line: 1, Col: 1}},
-		{in: `'\U00000'`, err: "too few digits in Unicode escape; expected 8, got 5", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\U00000'`, Line: 1, Col: 1}},
-		{in: `'\U000000'`, err: "too few digits in Unicode escape; expected 8, got 6", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\U000000'`, Line: 1, Col: 1}},
-		{in: `'\U0000000'`, err: "too few digits in Unicode escape; expected 8, got 7", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\U0000000'`, Line: 1, Col: 1}},
-		{in: `'\U0000000`, err: "unexpected eof in Unicode escape", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\U0000000`, Line: 1, Col: 1}},
-		{in: `'\U00000000'`, want: token.Token{Kind: token.Rune, Val: `'\U00000000'`, Line: 1, Col: 1}},
-		{in: `'\Uffffffff'`, err: "invalid Unicode code point U+FFFFFFFFFFFFFFFF in escape sequence", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\Uffffffff'`, Line: 1, Col: 1}},
-		{in: `'\U0g'`, err: "non-hex character U+0067 'g' in Unicode escape", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\U0g'`, Line: 1, Col: 1}},
-		{in: `'`, err: "unexpected eof in rune literal", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'`, Line: 1, Col: 1}},
-		{in: `'\`, err: "unexpected eof in escape sequence", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\`, Line: 1, Col: 1}},
-		{in: "'\n", err: "unexpected newline in rune literal", want: token.Token{Kind: token.Rune | token.Invalid, Val: "'", Line: 1, Col: 1}},
-		{in: "'\n ", err: "unexpected newline in rune literal", want: token.Token{Kind: token.Rune | token.Invalid, Val: "'", Line: 1, Col: 1}},
-		{in: "'x", err: "unexpected eof in rune literal", want: token.Token{Kind: token.Rune | token.Invalid, Val: "'x", Line: 1, Col: 1}},
-		{in: "'x\n", err: "unexpected newline in rune literal", want: token.Token{Kind: token.Rune | token.Invalid, Val: "'x", Line: 1, Col: 1}},
-		{in: `""`, want: token.Token{Kind: token.String, Val: `""`, Line: 1, Col: 1}},
-		{in: `"abc`, err: "unexpected eof in string literal", want: token.Token{Kind: token.String | token.Invalid, Val: `"abc`, Line: 1, Col: 1}},
-		{in: "\"abc\n", err: "unexpected newline in string literal", want: token.Token{Kind: token.String | token.Invalid, Val: "\"abc", Line: 1, Col: 1}},
-		{in: "\"abc\n ", err: "unexpected newline in string literal", want: token.Token{Kind: token.String | token.Invalid, Val: "\"abc", Line: 1, Col: 1}},
-		{in: `"\q"`, err: "unknown escape sequence U+0071 'q'", want: token.Token{Kind: token.String | token.Invalid, Val: `"\q"`, Line: 1, Col: 1}},
-		{in: "``", want: token.Token{Kind: token.String, Val: "``", Line: 1, Col: 1}},
-		{in: "`", err: "unexpected eof in raw string literal", want: token.Token{Kind: token.String | token.Invalid, Val: "`", Line: 1, Col: 1}},
-		{in: "/**/", want: token.Token{Kind: token.Comment, Val: "/**/", Line: 1, Col: 1}},
-		{in: "/*", err: "unexpected eof in comment", want: token.Token{Kind: token.Comment | token.Invalid, Val: "/*", Line: 1, Col: 1}},
-		{in: "077", want: token.Token{Kind: token.Int, Val: "077", Line: 1, Col: 1}},
-		{in: "078.", want: token.Token{Kind: token.Float, Val: "078.", Line: 1, Col: 1}},
-		{in: "07801234567.", want: token.Token{Kind: token.Float, Val: "07801234567.", Line: 1, Col: 1}},
-		{in: "078e0", want: token.Token{Kind: token.Float, Val: "078e0", Line: 1, Col: 1}},
-		{in: "078", err: "invalid digit '8' in octal constant", want: token.Token{Kind: token.Int | token.Invalid, Val: "078", Line: 1, Col: 1}},
-		{in: "07800000009", err: "invalid digit '8' in octal constant", want: token.Token{Kind: token.Int | token.Invalid, Val: "07800000009", Line: 1, Col: 1}},
-		{in: "079", err: "invalid digit '9' in octal constant", want: token.Token{Kind: token.Int | token.Invalid, Val: "079", Line: 1, Col: 1}},
-		{in: "0x", err: "missing digits in hexadecimal constant", want: token.Token{Kind: token.Int | token.Invalid, Val: "0x", Line: 1, Col: 1}},
-		{in: "0X", err: "missing digits in hexadecimal constant", want: token.Token{Kind: token.Int | token.Invalid, Val: "0X", Line: 1, Col: 1}},
-		{in: ".3e", err: "missing digits in floating-point exponent", want: token.Token{Kind: token.Float | token.Invalid, Val: ".3e", Line: 1, Col: 1}},
-		{in: "3.14E", err: "missing digits in floating-point exponent", want: token.Token{Kind: token.Float | token.Invalid, Val: "3.14E", Line: 1, Col: 1}},
-		{in: "5e", err: "missing digits in floating-point exponent", want: token.Token{Kind: token.Float | token.Invalid, Val: "5e", Line: 1, Col: 1}},
-		{in: "//abc\x00def", err: "illegal NUL character", want: token.Token{Kind: token.Comment | token.Invalid, Val: "//abc\x00def", Line: 1, Col: 1}},
-		{in: "/*abc\x00def*/", err: "illegal NUL character", want: token.Token{Kind: token.Comment | token.Invalid, Val: "/*abc\x00def*/", Line: 1, Col: 1}},
-		{in: "'\x00'", err: "illegal NUL character", want: token.Token{Kind: token.Rune | token.Invalid, Val: "'\x00'", Line: 1, Col: 1}},
-		{in: "\"abc\x00def\"", err: "illegal NUL character", want: token.Token{Kind: token.String | token.Invalid, Val: "\"abc\x00def\"", Line: 1, Col: 1}},
-		{in: "`abc\x00def`", err: "illegal NUL character", want: token.Token{Kind: token.String | token.Invalid, Val: "`abc\x00def`", Line: 1, Col: 1}},
-		{in: "//abc\x80def", err: "illegal UTF-8 encoding", want: token.Token{Kind: token.Comment | token.Invalid, Val: "//abc\x80def", Line: 1, Col: 1}},
-		{in: "/*abc\x80def*/", err: "illegal UTF-8 encoding", want: token.Token{Kind: token.Comment | token.Invalid, Val: "/*abc\x80def*/", Line: 1, Col: 1}},
-		{in: "'\x80'", err: "illegal UTF-8 encoding", want: token.Token{Kind: token.Rune | token.Invalid, Val: "'\x80'", Line: 1, Col: 1}},
-		{in: "\"abc\x80def\"", err: "illegal UTF-8 encoding", want: token.Token{Kind: token.String | token.Invalid, Val: "\"abc\x80def\"", Line: 1, Col: 1}},
-		{in: "`abc\x80def`", err: "illegal UTF-8 encoding", want: token.Token{Kind: token.String | token.Invalid, Val: "`abc\x80def`", Line: 1, Col: 1}},
-		{in: "\ufeff\ufeff", err: "illegal byte order mark", want: token.Token{Kind: token.Invalid, Val: "\ufeff", Line: 1, Col: 1}},                               // only first BOM is ignored.
-		{in: "//abc\ufeffdef", err: "illegal byte order mark", want: token.Token{Kind: token.Comment | token.Invalid, Val: "//abc\ufeffdef", Line: 1, Col: 1}},     // only first BOM is ignored.
-		{in: "/*abc\ufeffdef*/", err: "illegal byte order mark", want: token.Token{Kind: token.Comment | token.Invalid, Val: "/*abc\ufeffdef*/", Line: 1, Col: 1}}, // only first BOM is ignored.
-		{in: "'\ufeff'", err: "illegal byte order mark", want: token.Token{Kind: token.Rune | token.Invalid, Val: "'\ufeff'", Line: 1, Col: 1}},                    // only first BOM is ignored.
-		{in: "\"abc\ufeffdef\"", err: "illegal byte order mark", want: token.Token{Kind: token.String | token.Invalid, Val: "\"abc\ufeffdef\"", Line: 1, Col: 1}},  // only first BOM is ignored.
-		{in: "`abc\ufeffdef`", err: "illegal byte order mark", want: token.Token{Kind: token.String | token.Invalid, Val: "`abc\ufeffdef`", Line: 1, Col: 1}},      // only first BOM is ignored.
+		{
+			in: "package main",
+			want: []token.Token{
+				{Kind: token.Package, Val: "package", Line: 1, Col: 1, Offset: 0},
+				{Kind: token.Ident, Val: "main", Line: 1, Col: 9, Offset: 8},
+				{Kind: token.Semicolon, Val: ";", Line: 1, Col: 13, Offset: 12},
+			},
+		},
+		{
+			in: "foo",
+			want: []token.Token{
+				{Kind: token.Ident, Val: "foo", Line: 1, Col: 1, Offset: 0},
+				{Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3},
+			},
+		},
+		{
+			in: "foo \t ",
+			want: []token.Token{
+				{Kind: token.Ident, Val: "foo", Line: 1, Col: 1, Offset: 0},
+				{Kind: token.Semicolon, Val: ";", Line: 1, Col: 4, Offset: 3},
+			},
+		},
+		{
+			in: "x\ny++",
+			want: []token.Token{
+				{Kind: token.Ident, Val: "x", Line: 1, Col: 1, Offset: 0},
+				{Kind: token.Semicolon, Val: ";", Line: 1, Col: 2, Offset: 1},
+				{Kind: token.Ident, Val: "y", Line: 2, Col: 1, Offset: 2},
+				{Kind: token.Inc, Val: "++", Line: 2, Col: 2, Offset: 3},
+				{Kind: token.Semicolon, Val: ";", Line: 2, Col: 4, Offset: 5},
+			},
+		},
+		{
+			in: "}",
+			want: []token.Token{
+				{Kind: token.Rbrace, Val: "}", Line: 1, Col: 1, Offset: 0},
+				{Kind: token.Semicolon, Val: ";", Line: 1, Col: 2, Offset: 1},
+			},
+		},
+		{
+			in: "a +",
+			want: []token.Token{
+				{Kind: token.Ident, Val: "a", Line: 1, Col: 1, Offset: 0},
+				{Kind: token.Add, Val: "+", Line: 1, Col: 3, Offset: 2},
+			},
+		},
 	}
 
 	for i, g := range golden {
+		got, err := Parse(g.in)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: tokens mismatch for %q; expected %v, got %v.", i, g.in, g.want, got)
+		}
+	}
+}
+
+// test cases derived from errors in go/src/pkg/scanner/scanner_test.go
+var goldenErrors = []struct {
+	in   string
+	err  string
+	want token.Token
+}{
+	{in: "\a", err: "syntax error: unexpected U+0007", want: token.Token{Kind: token.Invalid, Val: "\a", Line: 1, Col: 1, Offset: 0}},
+	{in: `#`, err: "syntax error: unexpected U+0023 '#'", want: token.Token{Kind: token.Invalid, Val: `#`, Line: 1, Col: 1, Offset: 0}},
+	{in: `…`, err: "syntax error: unexpected U+2026 '…'", want: token.Token{Kind: token.Invalid, Val: `…`, Line: 1, Col: 1, Offset: 0}},
+	{in: `' '`, want: token.Token{Kind: token.Rune, Val: "' '", Line: 1, Col: 1, Offset: 0}},
+	{in: `''`, err: "empty rune literal or unescaped ' in rune literal", want: token.Token{Kind: token.Rune | token.Invalid, Val: "''", Line: 1, Col: 1, Offset: 0}},
+	{in: `'12'`, err: "too many characters in rune literal", want: token.Token{Kind: token.Rune | token.Invalid, Val: "'12'", Line: 1, Col: 1, Offset: 0}},
+	{in: `'123'`, err: "too many characters in rune literal", want: token.Token{Kind: token.Rune | token.Invalid, Val: "'123'", Line: 1, Col: 1, Offset: 0}},
+	{in: `'\0'`, err: "too few digits in octal escape; expected 3, got 1", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\0'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\07'`, err: "too few digits in octal escape; expected 3, got 2", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\07'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\8'`, err: "unknown escape sequence U+0038 '8'", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\8'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\08'`, err: "non-octal character U+0038 '8' in octal escape", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\08'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\0`, err: "unexpected eof in octal escape", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\0`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\00`, err: "unexpected eof in octal escape", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\00`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\000`, err: "unexpected eof in rune literal", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\000`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\x'`, err: "too few digits in hex escape; expected 2, got 0", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\x'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\x0'`, err: "too few digits in hex escape; expected 2, got 1", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\x0'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `"\x4'"`, err: "non-hex character U+0027 ''' in hex escape", want: token.Token{Kind: token.String | token.Invalid, Val: `"\x4'"`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\x0g'`, err: "non-hex character U+0067 'g' in hex escape", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\x0g'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\x`, err: "unexpected eof in hex escape", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\x`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\x0`, err: "unexpected eof in hex escape", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\x0`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\x00`, err: "unexpected eof in rune literal", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\x00`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\u'`, err: "too few digits in Unicode escape; expected 4, got 0", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\u'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\u0'`, err: "too few digits in Unicode escape; expected 4, got 1", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\u0'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\u00'`, err: "too few digits in Unicode escape; expected 4, got 2", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\u00'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\u000'`, err: "too few digits in Unicode escape; expected 4, got 3", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\u000'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\u000`, err: "unexpected eof in Unicode escape", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\u000`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\u0000'`, want: token.Token{Kind: token.Rune, Val: `'\u0000'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\U'`, err: "too few digits in Unicode escape; expected 8, got 0", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\U'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\U0'`, err: "too few digits in Unicode escape; expected 8, got 1", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\U0'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\U00'`, err: "too few digits in Unicode escape; expected 8, got 2", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\U00'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\U000'`, err: "too few digits in Unicode escape; expected 8, got 3", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\U000'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\U0000'`, err: "too few digits in Unicode escape; expected 8, got 4", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\U0000'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\U00000'`, err: "too few digits in Unicode escape; expected 8, got 5", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\U00000'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\U000000'`, err: "too few digits in Unicode escape; expected 8, got 6", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\U000000'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\U0000000'`, err: "too few digits in Unicode escape; expected 8, got 7", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\U0000000'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\U0000000`, err: "unexpected eof in Unicode escape", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\U0000000`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\U00000000'`, want: token.Token{Kind: token.Rune, Val: `'\U00000000'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\Uffffffff'`, err: "invalid Unicode code point U+FFFFFFFFFFFFFFFF in escape sequence", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\Uffffffff'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\U0g'`, err: "non-hex character U+0067 'g' in Unicode escape", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\U0g'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'`, err: "unexpected eof in rune literal", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'`, Line: 1, Col: 1, Offset: 0}},
+	{in: `'\`, err: "unexpected eof in escape sequence", want: token.Token{Kind: token.Rune | token.Invalid, Val: `'\`, Line: 1, Col: 1, Offset: 0}},
+	{in: "'\n", err: "unexpected newline in rune literal", want: token.Token{Kind: token.Rune | token.Invalid, Val: "'", Line: 1, Col: 1, Offset: 0}},
+	{in: "'\n ", err: "unexpected newline in rune literal", want: token.Token{Kind: token.Rune | token.Invalid, Val: "'", Line: 1, Col: 1, Offset: 0}},
+	{in: "'x", err: "unexpected eof in rune literal", want: token.Token{Kind: token.Rune | token.Invalid, Val: "'x", Line: 1, Col: 1, Offset: 0}},
+	{in: "'x\n", err: "unexpected newline in rune literal", want: token.Token{Kind: token.Rune | token.Invalid, Val: "'x", Line: 1, Col: 1, Offset: 0}},
+	{in: `""`, want: token.Token{Kind: token.String, Val: `""`, Line: 1, Col: 1, Offset: 0}},
+	{in: `"abc`, err: "unexpected eof in string literal", want: token.Token{Kind: token.String | token.Invalid, Val: `"abc`, Line: 1, Col: 1, Offset: 0}},
+	{in: "\"abc\n", err: "unexpected newline in string literal", want: token.Token{Kind: token.String | token.Invalid, Val: "\"abc", Line: 1, Col: 1, Offset: 0}},
+	{in: "\"abc\n ", err: "unexpected newline in string literal", want: token.Token{Kind: token.String | token.Invalid, Val: "\"abc", Line: 1, Col: 1, Offset: 0}},
+	{in: `"\q"`, err: "unknown escape sequence U+0071 'q'", want: token.Token{Kind: token.String | token.Invalid, Val: `"\q"`, Line: 1, Col: 1, Offset: 0}},
+	{in: `"\`, err: "unexpected eof in escape sequence", want: token.Token{Kind: token.String | token.Invalid, Val: `"\`, Line: 1, Col: 1, Offset: 0}},
+	{in: `"abc\`, err: "unexpected eof in escape sequence", want: token.Token{Kind: token.String | token.Invalid, Val: `"abc\`, Line: 1, Col: 1, Offset: 0}},
+	{in: "``", want: token.Token{Kind: token.String, Val: "``", Line: 1, Col: 1, Offset: 0}},
+	{in: "`", err: "unexpected eof in raw string literal", want: token.Token{Kind: token.String | token.Invalid, Val: "`", Line: 1, Col: 1, Offset: 0}},
+	{in: "/**/", want: token.Token{Kind: token.Comment, Val: "/**/", Line: 1, Col: 1, Offset: 0}},
+	{in: "/*", err: "unexpected eof in comment", want: token.Token{Kind: token.Comment | token.Invalid, Val: "/*", Line: 1, Col: 1, Offset: 0}},
+	{in: "077", want: token.Token{Kind: token.Int, Val: "077", Line: 1, Col: 1, Offset: 0}},
+	{in: "078.", want: token.Token{Kind: token.Float, Val: "078.", Line: 1, Col: 1, Offset: 0}},
+	{in: "07801234567.", want: token.Token{Kind: token.Float, Val: "07801234567.", Line: 1, Col: 1, Offset: 0}},
+	{in: "078e0", want: token.Token{Kind: token.Float, Val: "078e0", Line: 1, Col: 1, Offset: 0}},
+	{in: "078", err: "invalid digit '8' in octal constant", want: token.Token{Kind: token.Int | token.Invalid, Val: "078", Line: 1, Col: 1, Offset: 0}},
+	{in: "07800000009", err: "invalid digit '8' in octal constant", want: token.Token{Kind: token.Int | token.Invalid, Val: "07800000009", Line: 1, Col: 1, Offset: 0}},
+	{in: "079", err: "invalid digit '9' in octal constant", want: token.Token{Kind: token.Int | token.Invalid, Val: "079", Line: 1, Col: 1, Offset: 0}},
+	{in: "0x", err: "missing digits in hexadecimal constant", want: token.Token{Kind: token.Int | token.Invalid, Val: "0x", Line: 1, Col: 1, Offset: 0}},
+	{in: "0X", err: "missing digits in hexadecimal constant", want: token.Token{Kind: token.Int | token.Invalid, Val: "0X", Line: 1, Col: 1, Offset: 0}},
+	{in: "0o17", want: token.Token{Kind: token.Int, Val: "0o17", Line: 1, Col: 1, Offset: 0}},
+	{in: "0O7", want: token.Token{Kind: token.Int, Val: "0O7", Line: 1, Col: 1, Offset: 0}},
+	{in: "0o", err: "octal literal has no digits", want: token.Token{Kind: token.Int | token.Invalid, Val: "0o", Line: 1, Col: 1, Offset: 0}},
+	{in: "0O", err: "octal literal has no digits", want: token.Token{Kind: token.Int | token.Invalid, Val: "0O", Line: 1, Col: 1, Offset: 0}},
+	{in: "0o78", err: "invalid digit '8' in octal literal", want: token.Token{Kind: token.Int | token.Invalid, Val: "0o78", Line: 1, Col: 1, Offset: 0}},
+	{in: "0o9", err: "invalid digit '9' in octal literal", want: token.Token{Kind: token.Int | token.Invalid, Val: "0o9", Line: 1, Col: 1, Offset: 0}},
+	{in: "0x1Fi", want: token.Token{Kind: token.Imag, Val: "0x1Fi", Line: 1, Col: 1, Offset: 0}},
+	{in: "0XABi", want: token.Token{Kind: token.Imag, Val: "0XABi", Line: 1, Col: 1, Offset: 0}},
+	{in: "0o17i", want: token.Token{Kind: token.Imag, Val: "0o17i", Line: 1, Col: 1, Offset: 0}},
+	{in: "0O7i", want: token.Token{Kind: token.Imag, Val: "0O7i", Line: 1, Col: 1, Offset: 0}},
+	{in: "0o78i", err: "invalid digit '8' in octal literal", want: token.Token{Kind: token.Imag | token.Invalid, Val: "0o78i", Line: 1, Col: 1, Offset: 0}},
+	{in: ".3e", err: "missing digits in floating-point exponent", want: token.Token{Kind: token.Float | token.Invalid, Val: ".3e", Line: 1, Col: 1, Offset: 0}},
+	{in: "3.14E", err: "missing digits in floating-point exponent", want: token.Token{Kind: token.Float | token.Invalid, Val: "3.14E", Line: 1, Col: 1, Offset: 0}},
+	{in: "5e", err: "missing digits in floating-point exponent", want: token.Token{Kind: token.Float | token.Invalid, Val: "5e", Line: 1, Col: 1, Offset: 0}},
+	{in: "//abc\x00def", err: "illegal NUL character", want: token.Token{Kind: token.Comment | token.Invalid, Val: "//abc\x00def", Line: 1, Col: 1, Offset: 0}},
+	{in: "/*abc\x00def*/", err: "illegal NUL character", want: token.Token{Kind: token.Comment | token.Invalid, Val: "/*abc\x00def*/", Line: 1, Col: 1, Offset: 0}},
+	{in: "'\x00'", err: "illegal NUL character", want: token.Token{Kind: token.Rune | token.Invalid, Val: "'\x00'", Line: 1, Col: 1, Offset: 0}},
+	{in: "\"abc\x00def\"", err: "illegal NUL character", want: token.Token{Kind: token.String | token.Invalid, Val: "\"abc\x00def\"", Line: 1, Col: 1, Offset: 0}},
+	{in: "`abc\x00def`", err: "illegal NUL character", want: token.Token{Kind: token.String | token.Invalid, Val: "`abc\x00def`", Line: 1, Col: 1, Offset: 0}},
+	{in: "//abc\x80def", err: "illegal UTF-8 encoding", want: token.Token{Kind: token.Comment | token.Invalid, Val: "//abc\x80def", Line: 1, Col: 1, Offset: 0}},
+	{in: "/*abc\x80def*/", err: "illegal UTF-8 encoding", want: token.Token{Kind: token.Comment | token.Invalid, Val: "/*abc\x80def*/", Line: 1, Col: 1, Offset: 0}},
+	{in: "'\x80'", err: "illegal UTF-8 encoding", want: token.Token{Kind: token.Rune | token.Invalid, Val: "'\x80'", Line: 1, Col: 1, Offset: 0}},
+	{in: "\"abc\x80def\"", err: "illegal UTF-8 encoding", want: token.Token{Kind: token.String | token.Invalid, Val: "\"abc\x80def\"", Line: 1, Col: 1, Offset: 0}},
+	{in: "`abc\x80def`", err: "illegal UTF-8 encoding", want: token.Token{Kind: token.String | token.Invalid, Val: "`abc\x80def`", Line: 1, Col: 1, Offset: 0}},
+	{in: "\ufeff\ufeff", err: "illegal byte order mark", want: token.Token{Kind: token.Invalid, Val: "\ufeff", Line: 1, Col: 1, Offset: 3}},                               // only first BOM is ignored.
+	{in: "//abc\ufeffdef", err: "illegal byte order mark", want: token.Token{Kind: token.Comment | token.Invalid, Val: "//abc\ufeffdef", Line: 1, Col: 1, Offset: 0}},     // only first BOM is ignored.
+	{in: "/*abc\ufeffdef*/", err: "illegal byte order mark", want: token.Token{Kind: token.Comment | token.Invalid, Val: "/*abc\ufeffdef*/", Line: 1, Col: 1, Offset: 0}}, // only first BOM is ignored.
+	{in: "'\ufeff'", err: "illegal byte order mark", want: token.Token{Kind: token.Rune | token.Invalid, Val: "'\ufeff'", Line: 1, Col: 1, Offset: 0}},                    // only first BOM is ignored.
+	{in: "\"abc\ufeffdef\"", err: "illegal byte order mark", want: token.Token{Kind: token.String | token.Invalid, Val: "\"abc\ufeffdef\"", Line: 1, Col: 1, Offset: 0}},  // only first BOM is ignored.
+	{in: "`abc\ufeffdef`", err: "illegal byte order mark", want: token.Token{Kind: token.String | token.Invalid, Val: "`abc\ufeffdef`", Line: 1, Col: 1, Offset: 0}},      // only first BOM is ignored.
+}
+
+func TestParseErrors(t *testing.T) {
+	for i, g := range goldenErrors {
 		tokens, err := Parse(g.in)
 		errstr := ""
 		if err != nil {
@@ -424,6 +516,30 @@ func TestParseErrors(t *testing.T) {
 	}
 }
 
+func TestParseUnterminatedComment(t *testing.T) {
+	const input = "package p\n\nvar x = 1 /* a */\n\n/* unterminated\n\nfunc f() {}\n"
+	tokens, err := Parse(input)
+	if err == nil || err.Error() != "unexpected eof in comment" {
+		t.Fatalf("error mismatch; expected %v, got %v.", "unexpected eof in comment", err)
+	}
+	// The tokens preceding the unterminated comment remain usable.
+	want := []token.Token{
+		{Kind: token.Package, Val: "package", Line: 1, Col: 1, Offset: 0},
+		{Kind: token.Ident, Val: "p", Line: 1, Col: 9, Offset: 8},
+		{Kind: token.Semicolon, Val: ";", Line: 1, Col: 10, Offset: 9},
+		{Kind: token.Var, Val: "var", Line: 3, Col: 1, Offset: 11},
+		{Kind: token.Ident, Val: "x", Line: 3, Col: 5, Offset: 15},
+		{Kind: token.Assign, Val: "=", Line: 3, Col: 7, Offset: 17},
+		{Kind: token.Int, Val: "1", Line: 3, Col: 9, Offset: 19},
+		{Kind: token.Semicolon, Val: ";", Line: 3, Col: 10, Offset: 20},
+		{Kind: token.Comment, Val: "/* a */", Line: 3, Col: 11, Offset: 21},
+		{Kind: token.Comment | token.Invalid, Val: "/* unterminated\n\nfunc f() {}\n", Line: 5, Col: 1, Offset: 30},
+	}
+	if !reflect.DeepEqual(tokens, want) {
+		t.Errorf("tokens mismatch; expected %v, got %v.", want, tokens)
+	}
+}
+
 func TestParsePosition(t *testing.T) {
 	input := `// Package p implements …
 package p
@@ -482,246 +598,246 @@ func Merge(ts ...T) T {
 }
 `
 	want := []token.Token{
-		{Kind: token.Comment, Val: "// Package p implements …", Line: 1, Col: 1},
-		{Kind: token.Package, Val: "package", Line: 2, Col: 1},
-		{Kind: token.Ident, Val: "p", Line: 2, Col: 9},
-		{Kind: token.Semicolon, Val: ";", Line: 2, Col: 10},
-		{Kind: token.Import, Val: "import", Line: 4, Col: 1},
-		{Kind: token.String, Val: "\"strings\"", Line: 4, Col: 8},
-		{Kind: token.Semicolon, Val: ";", Line: 4, Col: 17},
-		{Kind: token.Comment, Val: "// T is a bitfield which specifies …", Line: 6, Col: 1},
-		{Kind: token.Type, Val: "type", Line: 7, Col: 1},
-		{Kind: token.Ident, Val: "T", Line: 7, Col: 6},
-		{Kind: token.Ident, Val: "uint16", Line: 7, Col: 8},
-		{Kind: token.Semicolon, Val: ";", Line: 7, Col: 14},
-		{Kind: token.Comment, Val: "// T bitfield values.", Line: 9, Col: 1},
-		{Kind: token.Const, Val: "const", Line: 10, Col: 1},
-		{Kind: token.Lparen, Val: "(", Line: 10, Col: 7},
-		{Kind: token.Ident, Val: "FooA", Line: 11, Col: 2},
-		{Kind: token.Ident, Val: "T", Line: 11, Col: 7},
-		{Kind: token.Assign, Val: "=", Line: 11, Col: 9},
-		{Kind: token.Int, Val: "1", Line: 11, Col: 11},
-		{Kind: token.Shl, Val: "<<", Line: 11, Col: 12},
-		{Kind: token.Ident, Val: "iota", Line: 11, Col: 14},
-		{Kind: token.Comment, Val: "/* bitfield … */", Line: 11, Col: 19},
-		{Kind: token.Add, Val: "+", Line: 11, Col: 36},
-		{Kind: token.Int, Val: "0x10", Line: 11, Col: 38},
-		{Kind: token.Semicolon, Val: ";", Line: 11, Col: 42},
-		{Kind: token.Comment, Val: "/* Foo start value */", Line: 11, Col: 45},
-		{Kind: token.Ident, Val: "FooB", Line: 12, Col: 2},
-		{Kind: token.Semicolon, Val: ";", Line: 12, Col: 6},
-		{Kind: token.Comment, Val: "/* FooB specifies … */", Line: 12, Col: 45},
-		{Kind: token.Ident, Val: "FooC", Line: 13, Col: 2},
-		{Kind: token.Semicolon, Val: ";", Line: 13, Col: 6},
-		{Kind: token.Comment, Val: "/* FooC specifies … */", Line: 13, Col: 45},
-		{Kind: token.Ident, Val: "BarA", Line: 14, Col: 2},
-		{Kind: token.Ident, Val: "T", Line: 14, Col: 7},
-		{Kind: token.Assign, Val: "=", Line: 14, Col: 9},
-		{Kind: token.Int, Val: "1", Line: 14, Col: 11},
-		{Kind: token.Shl, Val: "<<", Line: 14, Col: 12},
-		{Kind: token.Ident, Val: "iota", Line: 14, Col: 14},
-		{Kind: token.Comment, Val: "/* bitfield … */", Line: 14, Col: 19},
-		{Kind: token.Add, Val: "+", Line: 14, Col: 36},
-		{Kind: token.Int, Val: "0x100", Line: 14, Col: 38},
-		{Kind: token.Semicolon, Val: ";", Line: 14, Col: 43},
-		{Kind: token.Comment, Val: "/* Bar start value */", Line: 14, Col: 45},
-		{Kind: token.Ident, Val: "BarB", Line: 15, Col: 2},
-		{Kind: token.Semicolon, Val: ";", Line: 15, Col: 6},
-		{Kind: token.Comment, Val: "/* BarB specifies … */", Line: 15, Col: 45},
-		{Kind: token.Ident, Val: "BarC", Line: 16, Col: 2},
-		{Kind: token.Semicolon, Val: ";", Line: 16, Col: 6},
-		{Kind: token.Comment, Val: "/* BarC specifies … */", Line: 16, Col: 45},
-		{Kind: token.Ident, Val: "BazA", Line: 17, Col: 2},
-		{Kind: token.Ident, Val: "T", Line: 17, Col: 7},
-		{Kind: token.Assign, Val: "=", Line: 17, Col: 9},
-		{Kind: token.Int, Val: "1", Line: 17, Col: 11},
-		{Kind: token.Shl, Val: "<<", Line: 17, Col: 12},
-		{Kind: token.Ident, Val: "iota", Line: 17, Col: 14},
-		{Kind: token.Comment, Val: "/* bitfield … */", Line: 17, Col: 19},
-		{Kind: token.Add, Val: "+", Line: 17, Col: 36},
-		{Kind: token.Int, Val: "0x1000", Line: 17, Col: 38},
-		{Kind: token.Semicolon, Val: ";", Line: 17, Col: 44},
-		{Kind: token.Comment, Val: "/* Baz start value */", Line: 17, Col: 45},
-		{Kind: token.Ident, Val: "BazB", Line: 18, Col: 2},
-		{Kind: token.Semicolon, Val: ";", Line: 18, Col: 6},
-		{Kind: token.Comment, Val: "/* BazB specifies … */", Line: 18, Col: 45},
-		{Kind: token.Ident, Val: "BazC", Line: 19, Col: 2},
-		{Kind: token.Semicolon, Val: ";", Line: 19, Col: 6},
-		{Kind: token.Comment, Val: "/* BazC specifies … */", Line: 19, Col: 45},
-		{Kind: token.Rparen, Val: ")", Line: 20, Col: 1},
-		{Kind: token.Semicolon, Val: ";", Line: 20, Col: 2},
-		{Kind: token.Comment, Val: "// names specifies the name of each …", Line: 22, Col: 1},
-		{Kind: token.Var, Val: "var", Line: 23, Col: 1},
-		{Kind: token.Ident, Val: "names", Line: 23, Col: 5},
-		{Kind: token.Assign, Val: "=", Line: 23, Col: 11},
-		{Kind: token.Map, Val: "map", Line: 23, Col: 13},
-		{Kind: token.Lbrack, Val: "[", Line: 23, Col: 16},
-		{Kind: token.Ident, Val: "T", Line: 23, Col: 17},
-		{Kind: token.Rbrack, Val: "]", Line: 23, Col: 18},
-		{Kind: token.Ident, Val: "string", Line: 23, Col: 19},
-		{Kind: token.Lbrace, Val: "{", Line: 23, Col: 25},
-		{Kind: token.Ident, Val: "FooA", Line: 24, Col: 2},
-		{Kind: token.Colon, Val: ":", Line: 24, Col: 6},
-		{Kind: token.String, Val: "\"foo A\"", Line: 24, Col: 8},
-		{Kind: token.Comma, Val: ",", Line: 24, Col: 15},
-		{Kind: token.Ident, Val: "FooB", Line: 25, Col: 2},
-		{Kind: token.Colon, Val: ":", Line: 25, Col: 6},
-		{Kind: token.String, Val: "\"foo B\"", Line: 25, Col: 8},
-		{Kind: token.Comma, Val: ",", Line: 25, Col: 15},
-		{Kind: token.Ident, Val: "FooC", Line: 26, Col: 2},
-		{Kind: token.Colon, Val: ":", Line: 26, Col: 6},
-		{Kind: token.String, Val: "\"foo C\"", Line: 26, Col: 8},
-		{Kind: token.Comma, Val: ",", Line: 26, Col: 15},
-		{Kind: token.Ident, Val: "BarA", Line: 27, Col: 2},
-		{Kind: token.Colon, Val: ":", Line: 27, Col: 6},
-		{Kind: token.String, Val: "\"bar A\"", Line: 27, Col: 8},
-		{Kind: token.Comma, Val: ",", Line: 27, Col: 15},
-		{Kind: token.Ident, Val: "BarB", Line: 28, Col: 2},
-		{Kind: token.Colon, Val: ":", Line: 28, Col: 6},
-		{Kind: token.String, Val: "\"bar B\"", Line: 28, Col: 8},
-		{Kind: token.Comma, Val: ",", Line: 28, Col: 15},
-		{Kind: token.Ident, Val: "BarC", Line: 29, Col: 2},
-		{Kind: token.Colon, Val: ":", Line: 29, Col: 6},
-		{Kind: token.String, Val: "\"bar C\"", Line: 29, Col: 8},
-		{Kind: token.Comma, Val: ",", Line: 29, Col: 15},
-		{Kind: token.Ident, Val: "BazA", Line: 30, Col: 2},
-		{Kind: token.Colon, Val: ":", Line: 30, Col: 6},
-		{Kind: token.String, Val: "\"baz A\"", Line: 30, Col: 8},
-		{Kind: token.Comma, Val: ",", Line: 30, Col: 15},
-		{Kind: token.Ident, Val: "BazB", Line: 31, Col: 2},
-		{Kind: token.Colon, Val: ":", Line: 31, Col: 6},
-		{Kind: token.String, Val: "\"baz B\"", Line: 31, Col: 8},
-		{Kind: token.Comma, Val: ",", Line: 31, Col: 15},
-		{Kind: token.Ident, Val: "BazC", Line: 32, Col: 2},
-		{Kind: token.Colon, Val: ":", Line: 32, Col: 6},
-		{Kind: token.String, Val: "\"baz C\"", Line: 32, Col: 8},
-		{Kind: token.Comma, Val: ",", Line: 32, Col: 15},
-		{Kind: token.Rbrace, Val: "}", Line: 33, Col: 1},
-		{Kind: token.Semicolon, Val: ";", Line: 33, Col: 2},
-		{Kind: token.Func, Val: "func", Line: 35, Col: 1},
-		{Kind: token.Lparen, Val: "(", Line: 35, Col: 6},
-		{Kind: token.Ident, Val: "t", Line: 35, Col: 7},
-		{Kind: token.Ident, Val: "T", Line: 35, Col: 9},
-		{Kind: token.Rparen, Val: ")", Line: 35, Col: 10},
-		{Kind: token.Ident, Val: "String", Line: 35, Col: 12},
-		{Kind: token.Lparen, Val: "(", Line: 35, Col: 18},
-		{Kind: token.Rparen, Val: ")", Line: 35, Col: 19},
-		{Kind: token.Ident, Val: "string", Line: 35, Col: 21},
-		{Kind: token.Lbrace, Val: "{", Line: 35, Col: 28},
-		{Kind: token.Var, Val: "var", Line: 36, Col: 2},
-		{Kind: token.Ident, Val: "ss", Line: 36, Col: 6},
-		{Kind: token.Lbrack, Val: "[", Line: 36, Col: 9},
-		{Kind: token.Rbrack, Val: "]", Line: 36, Col: 10},
-		{Kind: token.Ident, Val: "string", Line: 36, Col: 11},
-		{Kind: token.Semicolon, Val: ";", Line: 36, Col: 17},
-		{Kind: token.For, Val: "for", Line: 37, Col: 2},
-		{Kind: token.Ident, Val: "i", Line: 37, Col: 6},
-		{Kind: token.DeclAssign, Val: ":=", Line: 37, Col: 8},
-		{Kind: token.Ident, Val: "uint", Line: 37, Col: 11},
-		{Kind: token.Lparen, Val: "(", Line: 37, Col: 15},
-		{Kind: token.Int, Val: "0", Line: 37, Col: 16},
-		{Kind: token.Rparen, Val: ")", Line: 37, Col: 17},
-		{Kind: token.Semicolon, Val: ";", Line: 37, Col: 18},
-		{Kind: token.Ident, Val: "i", Line: 37, Col: 20},
-		{Kind: token.Lt, Val: "<", Line: 37, Col: 22},
-		{Kind: token.Int, Val: "16", Line: 37, Col: 24},
-		{Kind: token.Semicolon, Val: ";", Line: 37, Col: 26},
-		{Kind: token.Ident, Val: "i", Line: 37, Col: 28},
-		{Kind: token.Inc, Val: "++", Line: 37, Col: 29},
-		{Kind: token.Lbrace, Val: "{", Line: 37, Col: 32},
-		{Kind: token.Ident, Val: "mask", Line: 38, Col: 3},
-		{Kind: token.DeclAssign, Val: ":=", Line: 38, Col: 8},
-		{Kind: token.Ident, Val: "T", Line: 38, Col: 11},
-		{Kind: token.Lparen, Val: "(", Line: 38, Col: 12},
-		{Kind: token.Int, Val: "1", Line: 38, Col: 13},
-		{Kind: token.Shl, Val: "<<", Line: 38, Col: 15},
-		{Kind: token.Ident, Val: "i", Line: 38, Col: 18},
-		{Kind: token.Rparen, Val: ")", Line: 38, Col: 19},
-		{Kind: token.Semicolon, Val: ";", Line: 38, Col: 20},
-		{Kind: token.If, Val: "if", Line: 39, Col: 3},
-		{Kind: token.Ident, Val: "v", Line: 39, Col: 6},
-		{Kind: token.DeclAssign, Val: ":=", Line: 39, Col: 8},
-		{Kind: token.Ident, Val: "t", Line: 39, Col: 11},
-		{Kind: token.And, Val: "&", Line: 39, Col: 13},
-		{Kind: token.Ident, Val: "mask", Line: 39, Col: 15},
-		{Kind: token.Semicolon, Val: ";", Line: 39, Col: 19},
-		{Kind: token.Ident, Val: "v", Line: 39, Col: 21},
-		{Kind: token.Neq, Val: "!=", Line: 39, Col: 23},
-		{Kind: token.Int, Val: "0", Line: 39, Col: 26},
-		{Kind: token.Lbrace, Val: "{", Line: 39, Col: 28},
-		{Kind: token.If, Val: "if", Line: 40, Col: 4},
-		{Kind: token.Ident, Val: "s", Line: 40, Col: 7},
-		{Kind: token.Comma, Val: ",", Line: 40, Col: 8},
-		{Kind: token.Ident, Val: "ok", Line: 40, Col: 10},
-		{Kind: token.DeclAssign, Val: ":=", Line: 40, Col: 13},
-		{Kind: token.Ident, Val: "names", Line: 40, Col: 16},
-		{Kind: token.Lbrack, Val: "[", Line: 40, Col: 21},
-		{Kind: token.Ident, Val: "v", Line: 40, Col: 22},
-		{Kind: token.Rbrack, Val: "]", Line: 40, Col: 23},
-		{Kind: token.Semicolon, Val: ";", Line: 40, Col: 24},
-		{Kind: token.Ident, Val: "ok", Line: 40, Col: 26},
-		{Kind: token.Lbrace, Val: "{", Line: 40, Col: 29},
-		{Kind: token.Ident, Val: "ss", Line: 41, Col: 5},
-		{Kind: token.Assign, Val: "=", Line: 41, Col: 8},
-		{Kind: token.Ident, Val: "append", Line: 41, Col: 10},
-		{Kind: token.Lparen, Val: "(", Line: 41, Col: 16},
-		{Kind: token.Ident, Val: "ss", Line: 41, Col: 17},
-		{Kind: token.Comma, Val: ",", Line: 41, Col: 19},
-		{Kind: token.Ident, Val: "s", Line: 41, Col: 21},
-		{Kind: token.Rparen, Val: ")", Line: 41, Col: 22},
-		{Kind: token.Semicolon, Val: ";", Line: 41, Col: 23},
-		{Kind: token.Rbrace, Val: "}", Line: 42, Col: 4},
-		{Kind: token.Semicolon, Val: ";", Line: 42, Col: 5},
-		{Kind: token.Rbrace, Val: "}", Line: 43, Col: 3},
-		{Kind: token.Semicolon, Val: ";", Line: 43, Col: 4},
-		{Kind: token.Rbrace, Val: "}", Line: 44, Col: 2},
-		{Kind: token.Semicolon, Val: ";", Line: 44, Col: 3},
-		{Kind: token.Return, Val: "return", Line: 45, Col: 2},
-		{Kind: token.Ident, Val: "strings", Line: 45, Col: 9},
-		{Kind: token.Dot, Val: ".", Line: 45, Col: 16},
-		{Kind: token.Ident, Val: "Join", Line: 45, Col: 17},
-		{Kind: token.Lparen, Val: "(", Line: 45, Col: 21},
-		{Kind: token.Ident, Val: "ss", Line: 45, Col: 22},
-		{Kind: token.Comma, Val: ",", Line: 45, Col: 24},
-		{Kind: token.String, Val: "\" \"", Line: 45, Col: 26},
-		{Kind: token.Rparen, Val: ")", Line: 45, Col: 29},
-		{Kind: token.Semicolon, Val: ";", Line: 45, Col: 30},
-		{Kind: token.Rbrace, Val: "}", Line: 46, Col: 1},
-		{Kind: token.Semicolon, Val: ";", Line: 46, Col: 2},
-		{Kind: token.Comment, Val: "// Merge merges … into a single T.", Line: 48, Col: 1},
-		{Kind: token.Func, Val: "func", Line: 49, Col: 1},
-		{Kind: token.Ident, Val: "Merge", Line: 49, Col: 6},
-		{Kind: token.Lparen, Val: "(", Line: 49, Col: 11},
-		{Kind: token.Ident, Val: "ts", Line: 49, Col: 12},
-		{Kind: token.Ellipsis, Val: "...", Line: 49, Col: 15},
-		{Kind: token.Ident, Val: "T", Line: 49, Col: 18},
-		{Kind: token.Rparen, Val: ")", Line: 49, Col: 19},
-		{Kind: token.Ident, Val: "T", Line: 49, Col: 21},
-		{Kind: token.Lbrace, Val: "{", Line: 49, Col: 23},
-		{Kind: token.Var, Val: "var", Line: 50, Col: 2},
-		{Kind: token.Ident, Val: "t", Line: 50, Col: 6},
-		{Kind: token.Ident, Val: "T", Line: 50, Col: 8},
-		{Kind: token.Semicolon, Val: ";", Line: 50, Col: 9},
-		{Kind: token.For, Val: "for", Line: 51, Col: 2},
-		{Kind: token.Ident, Val: "i", Line: 51, Col: 6},
-		{Kind: token.DeclAssign, Val: ":=", Line: 51, Col: 8},
-		{Kind: token.Range, Val: "range", Line: 51, Col: 11},
-		{Kind: token.Ident, Val: "ts", Line: 51, Col: 17},
-		{Kind: token.Lbrace, Val: "{", Line: 51, Col: 20},
-		{Kind: token.Ident, Val: "t", Line: 52, Col: 3},
-		{Kind: token.OrAssign, Val: "|=", Line: 52, Col: 5},
-		{Kind: token.Ident, Val: "ts", Line: 52, Col: 8},
-		{Kind: token.Lbrack, Val: "[", Line: 52, Col: 10},
-		{Kind: token.Ident, Val: "i", Line: 52, Col: 11},
-		{Kind: token.Rbrack, Val: "]", Line: 52, Col: 12},
-		{Kind: token.Semicolon, Val: ";", Line: 52, Col: 13},
-		{Kind: token.Rbrace, Val: "}", Line: 53, Col: 2},
-		{Kind: token.Semicolon, Val: ";", Line: 53, Col: 3},
-		{Kind: token.Return, Val: "return", Line: 54, Col: 2},
-		{Kind: token.Ident, Val: "t", Line: 54, Col: 9},
-		{Kind: token.Semicolon, Val: ";", Line: 54, Col: 10},
-		{Kind: token.Rbrace, Val: "}", Line: 55, Col: 1},
-		{Kind: token.Semicolon, Val: ";", Line: 55, Col: 2},
+		{Kind: token.Comment, Val: "// Package p implements …", Line: 1, Col: 1, Offset: 0},
+		{Kind: token.Package, Val: "package", Line: 2, Col: 1, Offset: 28},
+		{Kind: token.Ident, Val: "p", Line: 2, Col: 9, Offset: 36},
+		{Kind: token.Semicolon, Val: ";", Line: 2, Col: 10, Offset: 37},
+		{Kind: token.Import, Val: "import", Line: 4, Col: 1, Offset: 39},
+		{Kind: token.String, Val: "\"strings\"", Line: 4, Col: 8, Offset: 46},
+		{Kind: token.Semicolon, Val: ";", Line: 4, Col: 17, Offset: 55},
+		{Kind: token.Comment, Val: "// T is a bitfield which specifies …", Line: 6, Col: 1, Offset: 57},
+		{Kind: token.Type, Val: "type", Line: 7, Col: 1, Offset: 96},
+		{Kind: token.Ident, Val: "T", Line: 7, Col: 6, Offset: 101},
+		{Kind: token.Ident, Val: "uint16", Line: 7, Col: 8, Offset: 103},
+		{Kind: token.Semicolon, Val: ";", Line: 7, Col: 14, Offset: 109},
+		{Kind: token.Comment, Val: "// T bitfield values.", Line: 9, Col: 1, Offset: 111},
+		{Kind: token.Const, Val: "const", Line: 10, Col: 1, Offset: 133},
+		{Kind: token.Lparen, Val: "(", Line: 10, Col: 7, Offset: 139},
+		{Kind: token.Ident, Val: "FooA", Line: 11, Col: 2, Offset: 142},
+		{Kind: token.Ident, Val: "T", Line: 11, Col: 7, Offset: 147},
+		{Kind: token.Assign, Val: "=", Line: 11, Col: 9, Offset: 149},
+		{Kind: token.Int, Val: "1", Line: 11, Col: 11, Offset: 151},
+		{Kind: token.Shl, Val: "<<", Line: 11, Col: 12, Offset: 152},
+		{Kind: token.Ident, Val: "iota", Line: 11, Col: 14, Offset: 154},
+		{Kind: token.Comment, Val: "/* bitfield … */", Line: 11, Col: 19, Offset: 159},
+		{Kind: token.Add, Val: "+", Line: 11, Col: 36, Offset: 178},
+		{Kind: token.Int, Val: "0x10", Line: 11, Col: 38, Offset: 180},
+		{Kind: token.Semicolon, Val: ";", Line: 11, Col: 42, Offset: 184},
+		{Kind: token.Comment, Val: "/* Foo start value */", Line: 11, Col: 45, Offset: 187},
+		{Kind: token.Ident, Val: "FooB", Line: 12, Col: 2, Offset: 210},
+		{Kind: token.Semicolon, Val: ";", Line: 12, Col: 6, Offset: 214},
+		{Kind: token.Comment, Val: "/* FooB specifies … */", Line: 12, Col: 45, Offset: 253},
+		{Kind: token.Ident, Val: "FooC", Line: 13, Col: 2, Offset: 279},
+		{Kind: token.Semicolon, Val: ";", Line: 13, Col: 6, Offset: 283},
+		{Kind: token.Comment, Val: "/* FooC specifies … */", Line: 13, Col: 45, Offset: 322},
+		{Kind: token.Ident, Val: "BarA", Line: 14, Col: 2, Offset: 348},
+		{Kind: token.Ident, Val: "T", Line: 14, Col: 7, Offset: 353},
+		{Kind: token.Assign, Val: "=", Line: 14, Col: 9, Offset: 355},
+		{Kind: token.Int, Val: "1", Line: 14, Col: 11, Offset: 357},
+		{Kind: token.Shl, Val: "<<", Line: 14, Col: 12, Offset: 358},
+		{Kind: token.Ident, Val: "iota", Line: 14, Col: 14, Offset: 360},
+		{Kind: token.Comment, Val: "/* bitfield … */", Line: 14, Col: 19, Offset: 365},
+		{Kind: token.Add, Val: "+", Line: 14, Col: 36, Offset: 384},
+		{Kind: token.Int, Val: "0x100", Line: 14, Col: 38, Offset: 386},
+		{Kind: token.Semicolon, Val: ";", Line: 14, Col: 43, Offset: 391},
+		{Kind: token.Comment, Val: "/* Bar start value */", Line: 14, Col: 45, Offset: 393},
+		{Kind: token.Ident, Val: "BarB", Line: 15, Col: 2, Offset: 416},
+		{Kind: token.Semicolon, Val: ";", Line: 15, Col: 6, Offset: 420},
+		{Kind: token.Comment, Val: "/* BarB specifies … */", Line: 15, Col: 45, Offset: 459},
+		{Kind: token.Ident, Val: "BarC", Line: 16, Col: 2, Offset: 485},
+		{Kind: token.Semicolon, Val: ";", Line: 16, Col: 6, Offset: 489},
+		{Kind: token.Comment, Val: "/* BarC specifies … */", Line: 16, Col: 45, Offset: 528},
+		{Kind: token.Ident, Val: "BazA", Line: 17, Col: 2, Offset: 554},
+		{Kind: token.Ident, Val: "T", Line: 17, Col: 7, Offset: 559},
+		{Kind: token.Assign, Val: "=", Line: 17, Col: 9, Offset: 561},
+		{Kind: token.Int, Val: "1", Line: 17, Col: 11, Offset: 563},
+		{Kind: token.Shl, Val: "<<", Line: 17, Col: 12, Offset: 564},
+		{Kind: token.Ident, Val: "iota", Line: 17, Col: 14, Offset: 566},
+		{Kind: token.Comment, Val: "/* bitfield … */", Line: 17, Col: 19, Offset: 571},
+		{Kind: token.Add, Val: "+", Line: 17, Col: 36, Offset: 590},
+		{Kind: token.Int, Val: "0x1000", Line: 17, Col: 38, Offset: 592},
+		{Kind: token.Semicolon, Val: ";", Line: 17, Col: 44, Offset: 598},
+		{Kind: token.Comment, Val: "/* Baz start value */", Line: 17, Col: 45, Offset: 599},
+		{Kind: token.Ident, Val: "BazB", Line: 18, Col: 2, Offset: 622},
+		{Kind: token.Semicolon, Val: ";", Line: 18, Col: 6, Offset: 626},
+		{Kind: token.Comment, Val: "/* BazB specifies … */", Line: 18, Col: 45, Offset: 665},
+		{Kind: token.Ident, Val: "BazC", Line: 19, Col: 2, Offset: 691},
+		{Kind: token.Semicolon, Val: ";", Line: 19, Col: 6, Offset: 695},
+		{Kind: token.Comment, Val: "/* BazC specifies … */", Line: 19, Col: 45, Offset: 734},
+		{Kind: token.Rparen, Val: ")", Line: 20, Col: 1, Offset: 759},
+		{Kind: token.Semicolon, Val: ";", Line: 20, Col: 2, Offset: 760},
+		{Kind: token.Comment, Val: "// names specifies the name of each …", Line: 22, Col: 1, Offset: 762},
+		{Kind: token.Var, Val: "var", Line: 23, Col: 1, Offset: 802},
+		{Kind: token.Ident, Val: "names", Line: 23, Col: 5, Offset: 806},
+		{Kind: token.Assign, Val: "=", Line: 23, Col: 11, Offset: 812},
+		{Kind: token.Map, Val: "map", Line: 23, Col: 13, Offset: 814},
+		{Kind: token.Lbrack, Val: "[", Line: 23, Col: 16, Offset: 817},
+		{Kind: token.Ident, Val: "T", Line: 23, Col: 17, Offset: 818},
+		{Kind: token.Rbrack, Val: "]", Line: 23, Col: 18, Offset: 819},
+		{Kind: token.Ident, Val: "string", Line: 23, Col: 19, Offset: 820},
+		{Kind: token.Lbrace, Val: "{", Line: 23, Col: 25, Offset: 826},
+		{Kind: token.Ident, Val: "FooA", Line: 24, Col: 2, Offset: 829},
+		{Kind: token.Colon, Val: ":", Line: 24, Col: 6, Offset: 833},
+		{Kind: token.String, Val: "\"foo A\"", Line: 24, Col: 8, Offset: 835},
+		{Kind: token.Comma, Val: ",", Line: 24, Col: 15, Offset: 842},
+		{Kind: token.Ident, Val: "FooB", Line: 25, Col: 2, Offset: 845},
+		{Kind: token.Colon, Val: ":", Line: 25, Col: 6, Offset: 849},
+		{Kind: token.String, Val: "\"foo B\"", Line: 25, Col: 8, Offset: 851},
+		{Kind: token.Comma, Val: ",", Line: 25, Col: 15, Offset: 858},
+		{Kind: token.Ident, Val: "FooC", Line: 26, Col: 2, Offset: 861},
+		{Kind: token.Colon, Val: ":", Line: 26, Col: 6, Offset: 865},
+		{Kind: token.String, Val: "\"foo C\"", Line: 26, Col: 8, Offset: 867},
+		{Kind: token.Comma, Val: ",", Line: 26, Col: 15, Offset: 874},
+		{Kind: token.Ident, Val: "BarA", Line: 27, Col: 2, Offset: 877},
+		{Kind: token.Colon, Val: ":", Line: 27, Col: 6, Offset: 881},
+		{Kind: token.String, Val: "\"bar A\"", Line: 27, Col: 8, Offset: 883},
+		{Kind: token.Comma, Val: ",", Line: 27, Col: 15, Offset: 890},
+		{Kind: token.Ident, Val: "BarB", Line: 28, Col: 2, Offset: 893},
+		{Kind: token.Colon, Val: ":", Line: 28, Col: 6, Offset: 897},
+		{Kind: token.String, Val: "\"bar B\"", Line: 28, Col: 8, Offset: 899},
+		{Kind: token.Comma, Val: ",", Line: 28, Col: 15, Offset: 906},
+		{Kind: token.Ident, Val: "BarC", Line: 29, Col: 2, Offset: 909},
+		{Kind: token.Colon, Val: ":", Line: 29, Col: 6, Offset: 913},
+		{Kind: token.String, Val: "\"bar C\"", Line: 29, Col: 8, Offset: 915},
+		{Kind: token.Comma, Val: ",", Line: 29, Col: 15, Offset: 922},
+		{Kind: token.Ident, Val: "BazA", Line: 30, Col: 2, Offset: 925},
+		{Kind: token.Colon, Val: ":", Line: 30, Col: 6, Offset: 929},
+		{Kind: token.String, Val: "\"baz A\"", Line: 30, Col: 8, Offset: 931},
+		{Kind: token.Comma, Val: ",", Line: 30, Col: 15, Offset: 938},
+		{Kind: token.Ident, Val: "BazB", Line: 31, Col: 2, Offset: 941},
+		{Kind: token.Colon, Val: ":", Line: 31, Col: 6, Offset: 945},
+		{Kind: token.String, Val: "\"baz B\"", Line: 31, Col: 8, Offset: 947},
+		{Kind: token.Comma, Val: ",", Line: 31, Col: 15, Offset: 954},
+		{Kind: token.Ident, Val: "BazC", Line: 32, Col: 2, Offset: 957},
+		{Kind: token.Colon, Val: ":", Line: 32, Col: 6, Offset: 961},
+		{Kind: token.String, Val: "\"baz C\"", Line: 32, Col: 8, Offset: 963},
+		{Kind: token.Comma, Val: ",", Line: 32, Col: 15, Offset: 970},
+		{Kind: token.Rbrace, Val: "}", Line: 33, Col: 1, Offset: 972},
+		{Kind: token.Semicolon, Val: ";", Line: 33, Col: 2, Offset: 973},
+		{Kind: token.Func, Val: "func", Line: 35, Col: 1, Offset: 975},
+		{Kind: token.Lparen, Val: "(", Line: 35, Col: 6, Offset: 980},
+		{Kind: token.Ident, Val: "t", Line: 35, Col: 7, Offset: 981},
+		{Kind: token.Ident, Val: "T", Line: 35, Col: 9, Offset: 983},
+		{Kind: token.Rparen, Val: ")", Line: 35, Col: 10, Offset: 984},
+		{Kind: token.Ident, Val: "String", Line: 35, Col: 12, Offset: 986},
+		{Kind: token.Lparen, Val: "(", Line: 35, Col: 18, Offset: 992},
+		{Kind: token.Rparen, Val: ")", Line: 35, Col: 19, Offset: 993},
+		{Kind: token.Ident, Val: "string", Line: 35, Col: 21, Offset: 995},
+		{Kind: token.Lbrace, Val: "{", Line: 35, Col: 28, Offset: 1002},
+		{Kind: token.Var, Val: "var", Line: 36, Col: 2, Offset: 1005},
+		{Kind: token.Ident, Val: "ss", Line: 36, Col: 6, Offset: 1009},
+		{Kind: token.Lbrack, Val: "[", Line: 36, Col: 9, Offset: 1012},
+		{Kind: token.Rbrack, Val: "]", Line: 36, Col: 10, Offset: 1013},
+		{Kind: token.Ident, Val: "string", Line: 36, Col: 11, Offset: 1014},
+		{Kind: token.Semicolon, Val: ";", Line: 36, Col: 17, Offset: 1020},
+		{Kind: token.For, Val: "for", Line: 37, Col: 2, Offset: 1022},
+		{Kind: token.Ident, Val: "i", Line: 37, Col: 6, Offset: 1026},
+		{Kind: token.DeclAssign, Val: ":=", Line: 37, Col: 8, Offset: 1028},
+		{Kind: token.Ident, Val: "uint", Line: 37, Col: 11, Offset: 1031},
+		{Kind: token.Lparen, Val: "(", Line: 37, Col: 15, Offset: 1035},
+		{Kind: token.Int, Val: "0", Line: 37, Col: 16, Offset: 1036},
+		{Kind: token.Rparen, Val: ")", Line: 37, Col: 17, Offset: 1037},
+		{Kind: token.Semicolon, Val: ";", Line: 37, Col: 18, Offset: 1038},
+		{Kind: token.Ident, Val: "i", Line: 37, Col: 20, Offset: 1040},
+		{Kind: token.Lt, Val: "<", Line: 37, Col: 22, Offset: 1042},
+		{Kind: token.Int, Val: "16", Line: 37, Col: 24, Offset: 1044},
+		{Kind: token.Semicolon, Val: ";", Line: 37, Col: 26, Offset: 1046},
+		{Kind: token.Ident, Val: "i", Line: 37, Col: 28, Offset: 1048},
+		{Kind: token.Inc, Val: "++", Line: 37, Col: 29, Offset: 1049},
+		{Kind: token.Lbrace, Val: "{", Line: 37, Col: 32, Offset: 1052},
+		{Kind: token.Ident, Val: "mask", Line: 38, Col: 3, Offset: 1056},
+		{Kind: token.DeclAssign, Val: ":=", Line: 38, Col: 8, Offset: 1061},
+		{Kind: token.Ident, Val: "T", Line: 38, Col: 11, Offset: 1064},
+		{Kind: token.Lparen, Val: "(", Line: 38, Col: 12, Offset: 1065},
+		{Kind: token.Int, Val: "1", Line: 38, Col: 13, Offset: 1066},
+		{Kind: token.Shl, Val: "<<", Line: 38, Col: 15, Offset: 1068},
+		{Kind: token.Ident, Val: "i", Line: 38, Col: 18, Offset: 1071},
+		{Kind: token.Rparen, Val: ")", Line: 38, Col: 19, Offset: 1072},
+		{Kind: token.Semicolon, Val: ";", Line: 38, Col: 20, Offset: 1073},
+		{Kind: token.If, Val: "if", Line: 39, Col: 3, Offset: 1076},
+		{Kind: token.Ident, Val: "v", Line: 39, Col: 6, Offset: 1079},
+		{Kind: token.DeclAssign, Val: ":=", Line: 39, Col: 8, Offset: 1081},
+		{Kind: token.Ident, Val: "t", Line: 39, Col: 11, Offset: 1084},
+		{Kind: token.And, Val: "&", Line: 39, Col: 13, Offset: 1086},
+		{Kind: token.Ident, Val: "mask", Line: 39, Col: 15, Offset: 1088},
+		{Kind: token.Semicolon, Val: ";", Line: 39, Col: 19, Offset: 1092},
+		{Kind: token.Ident, Val: "v", Line: 39, Col: 21, Offset: 1094},
+		{Kind: token.Neq, Val: "!=", Line: 39, Col: 23, Offset: 1096},
+		{Kind: token.Int, Val: "0", Line: 39, Col: 26, Offset: 1099},
+		{Kind: token.Lbrace, Val: "{", Line: 39, Col: 28, Offset: 1101},
+		{Kind: token.If, Val: "if", Line: 40, Col: 4, Offset: 1106},
+		{Kind: token.Ident, Val: "s", Line: 40, Col: 7, Offset: 1109},
+		{Kind: token.Comma, Val: ",", Line: 40, Col: 8, Offset: 1110},
+		{Kind: token.Ident, Val: "ok", Line: 40, Col: 10, Offset: 1112},
+		{Kind: token.DeclAssign, Val: ":=", Line: 40, Col: 13, Offset: 1115},
+		{Kind: token.Ident, Val: "names", Line: 40, Col: 16, Offset: 1118},
+		{Kind: token.Lbrack, Val: "[", Line: 40, Col: 21, Offset: 1123},
+		{Kind: token.Ident, Val: "v", Line: 40, Col: 22, Offset: 1124},
+		{Kind: token.Rbrack, Val: "]", Line: 40, Col: 23, Offset: 1125},
+		{Kind: token.Semicolon, Val: ";", Line: 40, Col: 24, Offset: 1126},
+		{Kind: token.Ident, Val: "ok", Line: 40, Col: 26, Offset: 1128},
+		{Kind: token.Lbrace, Val: "{", Line: 40, Col: 29, Offset: 1131},
+		{Kind: token.Ident, Val: "ss", Line: 41, Col: 5, Offset: 1137},
+		{Kind: token.Assign, Val: "=", Line: 41, Col: 8, Offset: 1140},
+		{Kind: token.Ident, Val: "append", Line: 41, Col: 10, Offset: 1142},
+		{Kind: token.Lparen, Val: "(", Line: 41, Col: 16, Offset: 1148},
+		{Kind: token.Ident, Val: "ss", Line: 41, Col: 17, Offset: 1149},
+		{Kind: token.Comma, Val: ",", Line: 41, Col: 19, Offset: 1151},
+		{Kind: token.Ident, Val: "s", Line: 41, Col: 21, Offset: 1153},
+		{Kind: token.Rparen, Val: ")", Line: 41, Col: 22, Offset: 1154},
+		{Kind: token.Semicolon, Val: ";", Line: 41, Col: 23, Offset: 1155},
+		{Kind: token.Rbrace, Val: "}", Line: 42, Col: 4, Offset: 1159},
+		{Kind: token.Semicolon, Val: ";", Line: 42, Col: 5, Offset: 1160},
+		{Kind: token.Rbrace, Val: "}", Line: 43, Col: 3, Offset: 1163},
+		{Kind: token.Semicolon, Val: ";", Line: 43, Col: 4, Offset: 1164},
+		{Kind: token.Rbrace, Val: "}", Line: 44, Col: 2, Offset: 1166},
+		{Kind: token.Semicolon, Val: ";", Line: 44, Col: 3, Offset: 1167},
+		{Kind: token.Return, Val: "return", Line: 45, Col: 2, Offset: 1169},
+		{Kind: token.Ident, Val: "strings", Line: 45, Col: 9, Offset: 1176},
+		{Kind: token.Dot, Val: ".", Line: 45, Col: 16, Offset: 1183},
+		{Kind: token.Ident, Val: "Join", Line: 45, Col: 17, Offset: 1184},
+		{Kind: token.Lparen, Val: "(", Line: 45, Col: 21, Offset: 1188},
+		{Kind: token.Ident, Val: "ss", Line: 45, Col: 22, Offset: 1189},
+		{Kind: token.Comma, Val: ",", Line: 45, Col: 24, Offset: 1191},
+		{Kind: token.String, Val: "\" \"", Line: 45, Col: 26, Offset: 1193},
+		{Kind: token.Rparen, Val: ")", Line: 45, Col: 29, Offset: 1196},
+		{Kind: token.Semicolon, Val: ";", Line: 45, Col: 30, Offset: 1197},
+		{Kind: token.Rbrace, Val: "}", Line: 46, Col: 1, Offset: 1198},
+		{Kind: token.Semicolon, Val: ";", Line: 46, Col: 2, Offset: 1199},
+		{Kind: token.Comment, Val: "// Merge merges … into a single T.", Line: 48, Col: 1, Offset: 1201},
+		{Kind: token.Func, Val: "func", Line: 49, Col: 1, Offset: 1238},
+		{Kind: token.Ident, Val: "Merge", Line: 49, Col: 6, Offset: 1243},
+		{Kind: token.Lparen, Val: "(", Line: 49, Col: 11, Offset: 1248},
+		{Kind: token.Ident, Val: "ts", Line: 49, Col: 12, Offset: 1249},
+		{Kind: token.Ellipsis, Val: "...", Line: 49, Col: 15, Offset: 1252},
+		{Kind: token.Ident, Val: "T", Line: 49, Col: 18, Offset: 1255},
+		{Kind: token.Rparen, Val: ")", Line: 49, Col: 19, Offset: 1256},
+		{Kind: token.Ident, Val: "T", Line: 49, Col: 21, Offset: 1258},
+		{Kind: token.Lbrace, Val: "{", Line: 49, Col: 23, Offset: 1260},
+		{Kind: token.Var, Val: "var", Line: 50, Col: 2, Offset: 1263},
+		{Kind: token.Ident, Val: "t", Line: 50, Col: 6, Offset: 1267},
+		{Kind: token.Ident, Val: "T", Line: 50, Col: 8, Offset: 1269},
+		{Kind: token.Semicolon, Val: ";", Line: 50, Col: 9, Offset: 1270},
+		{Kind: token.For, Val: "for", Line: 51, Col: 2, Offset: 1272},
+		{Kind: token.Ident, Val: "i", Line: 51, Col: 6, Offset: 1276},
+		{Kind: token.DeclAssign, Val: ":=", Line: 51, Col: 8, Offset: 1278},
+		{Kind: token.Range, Val: "range", Line: 51, Col: 11, Offset: 1281},
+		{Kind: token.Ident, Val: "ts", Line: 51, Col: 17, Offset: 1287},
+		{Kind: token.Lbrace, Val: "{", Line: 51, Col: 20, Offset: 1290},
+		{Kind: token.Ident, Val: "t", Line: 52, Col: 3, Offset: 1294},
+		{Kind: token.OrAssign, Val: "|=", Line: 52, Col: 5, Offset: 1296},
+		{Kind: token.Ident, Val: "ts", Line: 52, Col: 8, Offset: 1299},
+		{Kind: token.Lbrack, Val: "[", Line: 52, Col: 10, Offset: 1301},
+		{Kind: token.Ident, Val: "i", Line: 52, Col: 11, Offset: 1302},
+		{Kind: token.Rbrack, Val: "]", Line: 52, Col: 12, Offset: 1303},
+		{Kind: token.Semicolon, Val: ";", Line: 52, Col: 13, Offset: 1304},
+		{Kind: token.Rbrace, Val: "}", Line: 53, Col: 2, Offset: 1306},
+		{Kind: token.Semicolon, Val: ";", Line: 53, Col: 3, Offset: 1307},
+		{Kind: token.Return, Val: "return", Line: 54, Col: 2, Offset: 1309},
+		{Kind: token.Ident, Val: "t", Line: 54, Col: 9, Offset: 1316},
+		{Kind: token.Semicolon, Val: ";", Line: 54, Col: 10, Offset: 1317},
+		{Kind: token.Rbrace, Val: "}", Line: 55, Col: 1, Offset: 1318},
+		{Kind: token.Semicolon, Val: ";", Line: 55, Col: 2, Offset: 1319},
 	}
 	got, err := Parse(input)
 	if err != nil {
@@ -738,9 +854,1010 @@ func Merge(ts ...T) T {
 	}
 }
 
+func TestParseRange(t *testing.T) {
+	const input = "\ufeffpackage p\n\nvar (\n\tä, b = 1, \"日本\"\n)\n\nfunc f() { return }\n"
+	all, err := Parse(input)
+	if err != nil {
+		t.Fatalf("unexpected error; %v", err)
+	}
+	golden := []struct {
+		start, end int
+		// Index of the first and one past the last token of all.
+		first, last int
+	}{
+		// Entire input.
+		{start: 0, end: len(input), first: 0, last: len(all)},
+		// Start of line.
+		{start: strings.Index(input, "var"), end: len(input), first: 3, last: len(all)},
+		// Middle of line, after non-ASCII characters.
+		{start: strings.Index(input, "b ="), end: len(input), first: 7, last: len(all)},
+		{start: strings.Index(input, "1,"), end: len(input), first: 9, last: len(all)},
+		// Range ending at the end of a line.
+		{start: strings.Index(input, "func"), end: len(input) - 1, first: 15, last: len(all)},
+		// Range ending before the end of input.
+		{start: strings.Index(input, "var"), end: strings.Index(input, "\n)"), first: 3, last: 13},
+	}
+
+	for i, g := range golden {
+		got, err := ParseRange(input, g.start, g.end)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		want := all[g.first:g.last]
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("i=%d: tokens mismatch; expected %#v, got %#v.", i, want, got)
+		}
+	}
+
+	// Invalid ranges.
+	for _, r := range [][2]int{{-1, 0}, {2, 1}, {0, len(input) + 1}} {
+		if _, err := ParseRange(input, r[0], r[1]); err == nil {
+			t.Errorf("expected error for range [%d:%d], got nil.", r[0], r[1])
+		}
+	}
+}
+
+func TestParseLineDirective(t *testing.T) {
+	golden := []struct {
+		input string
+		// Identifier tokens of the input.
+		want []token.Token
+	}{
+		// No directive.
+		{
+			input: "a\nb",
+			want: []token.Token{
+				{Kind: token.Ident, Val: "a", Line: 1, Col: 1, Offset: 0},
+				{Kind: token.Ident, Val: "b", Line: 2, Col: 1, Offset: 2},
+			},
+		},
+		// path:line form.
+		{
+			input: "a\n//line foo.y:42\nb\nc",
+			want: []token.Token{
+				{Kind: token.Ident, Val: "a", Line: 1, Col: 1, Offset: 0},
+				{Kind: token.Ident, Val: "b", Line: 42, Col: 1, Offset: 18, Filename: "foo.y"},
+				{Kind: token.Ident, Val: "c", Line: 43, Col: 1, Offset: 20, Filename: "foo.y"},
+			},
+		},
+		// path:line:col form.
+		{
+			input: "//line foo.y:10:5\nb c\nd",
+			want: []token.Token{
+				{Kind: token.Ident, Val: "b", Line: 10, Col: 5, Offset: 18, Filename: "foo.y"},
+				{Kind: token.Ident, Val: "c", Line: 10, Col: 7, Offset: 20, Filename: "foo.y"},
+				{Kind: token.Ident, Val: "d", Line: 11, Col: 1, Offset: 22, Filename: "foo.y"},
+			},
+		},
+		// Paths containing colons.
+		{
+			input: "//line C:\\foo.y:7\nb",
+			want: []token.Token{
+				{Kind: token.Ident, Val: "b", Line: 7, Col: 1, Offset: 18, Filename: "C:\\foo.y"},
+			},
+		},
+		// Subsequent directives.
+		{
+			input: "//line a.y:10\nb\n//line c.y:20\nd",
+			want: []token.Token{
+				{Kind: token.Ident, Val: "b", Line: 10, Col: 1, Offset: 14, Filename: "a.y"},
+				{Kind: token.Ident, Val: "d", Line: 20, Col: 1, Offset: 30, Filename: "c.y"},
+			},
+		},
+		// Invalid directives are ignored.
+		{
+			input: "//line foo.y\na\n//line foo.y:0\nb\n//line :3\nc\n// line foo.y:4\nd\n//line foo.y:x\ne",
+			want: []token.Token{
+				{Kind: token.Ident, Val: "a", Line: 2, Col: 1, Offset: 13},
+				{Kind: token.Ident, Val: "b", Line: 4, Col: 1, Offset: 30},
+				{Kind: token.Ident, Val: "c", Line: 6, Col: 1, Offset: 42},
+				{Kind: token.Ident, Val: "d", Line: 8, Col: 1, Offset: 60},
+				{Kind: token.Ident, Val: "e", Line: 10, Col: 1, Offset: 77},
+			},
+		},
+		// Directives must start at column 1.
+		{
+			input: "x //line foo.y:10\ny\n\t//line foo.y:20\nz",
+			want: []token.Token{
+				{Kind: token.Ident, Val: "x", Line: 1, Col: 1, Offset: 0},
+				{Kind: token.Ident, Val: "y", Line: 2, Col: 1, Offset: 18},
+				{Kind: token.Ident, Val: "z", Line: 4, Col: 1, Offset: 37},
+			},
+		},
+		// The byte order mark at the start of the input is ignored.
+		{
+			input: "\ufeff//line foo.y:10\nb",
+			want: []token.Token{
+				{Kind: token.Ident, Val: "b", Line: 10, Col: 1, Offset: 19, Filename: "foo.y"},
+			},
+		},
+	}
+
+	for i, g := range golden {
+		tokens, err := Parse(g.input)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		var got []token.Token
+		for _, tok := range tokens {
+			if tok.Kind == token.Ident {
+				got = append(got, tok)
+			}
+		}
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: tokens mismatch; expected %v, got %v.", i, g.want, got)
+		}
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	const input = "package p\n//line gen.y:10\nvar x\n"
+	tokens, err := ParseFile("p.go", input)
+	if err != nil {
+		t.Fatalf("unexpected error; %v", err)
+	}
+	want := []string{"p.go", "p.go", "p.go", "p.go", "gen.y", "gen.y", "gen.y"}
+	if len(tokens) != len(want) {
+		t.Fatalf("token count mismatch; expected %d, got %d (%v).", len(want), len(tokens), tokens)
+	}
+	for i, tok := range tokens {
+		if tok.Filename != want[i] {
+			t.Errorf("i=%d: filename mismatch for %q; expected %q, got %q.", i, tok.Val, want[i], tok.Filename)
+		}
+	}
+
+	// Parse leaves the filename empty.
+	tokens, err = Parse("package p\n")
+	if err != nil {
+		t.Fatalf("unexpected error; %v", err)
+	}
+	for i, tok := range tokens {
+		if tok.Filename != "" {
+			t.Errorf("i=%d: filename mismatch for %q; expected \"\", got %q.", i, tok.Val, tok.Filename)
+		}
+	}
+}
+
+func TestParseCapacity(t *testing.T) {
+	// The preallocated token slice is not grown while lexing source.
+	tokens, err := Parse(source)
+	if err != nil {
+		t.Fatalf("unexpected error; %v", err)
+	}
+	if want := tokenCap(len(source)); cap(tokens) != want {
+		t.Errorf("capacity mismatch; expected %d, got %d for %d tokens.", want, cap(tokens), len(tokens))
+	}
+}
+
+func TestParseReader(t *testing.T) {
+	const input = "package p\n"
+	want, err := Parse(input)
+	if err != nil {
+		t.Fatalf("unexpected error; %v", err)
+	}
+	got, err := ParseReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error; %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tokens mismatch; expected %v, got %v.", want, got)
+	}
+
+	// Lexical errors are reported as an ErrorList.
+	_, err = ParseReader(strings.NewReader("'a"))
+	if _, ok := err.(ErrorList); !ok {
+		t.Errorf("error type mismatch; expected ErrorList, got %T.", err)
+	}
+}
+
+func TestParseFileReader(t *testing.T) {
+	const input = "package p\n//line b.go:10\nvar x\n"
+	want, err := ParseFile("a.go", input)
+	if err != nil {
+		t.Fatalf("unexpected error; %v", err)
+	}
+	got, err := ParseFileReader("a.go", strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error; %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tokens mismatch; expected %v, got %v.", want, got)
+	}
+	if got[0].Filename != "a.go" {
+		t.Errorf("filename mismatch; expected %q, got %q.", "a.go", got[0].Filename)
+	}
+}
+
+func TestParseFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lexer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	inputs := []string{
+		source,
+		"package p\n\nfunc f() int {\n\treturn 42 // comment\n}\n",
+		"x := 'a\ny := \"b",
+		"",
+		"package q\n",
+	}
+	var paths []string
+	for i, input := range inputs {
+		path := filepath.Join(dir, fmt.Sprintf("%d.go", i))
+		if err := ioutil.WriteFile(path, []byte(input), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	missing := filepath.Join(dir, "missing.go")
+	paths = append(paths, missing)
+
+	for _, workers := range []int{0, 1, 3, 16} {
+		files, errs := ParseFiles(paths, workers)
+		for i, input := range inputs {
+			want, wantErr := ParseFile(paths[i], input)
+			got, ok := files[paths[i]]
+			if !ok {
+				t.Errorf("workers=%d, i=%d: missing tokens of %q.", workers, i, paths[i])
+			}
+			if !token.TokensEqual(got, want) {
+				t.Errorf("workers=%d, i=%d: tokens mismatch; expected %v, got %v.", workers, i, want, got)
+			}
+			if err := errs[paths[i]]; !reflect.DeepEqual(err, wantErr) {
+				t.Errorf("workers=%d, i=%d: error mismatch; expected %v, got %v.", workers, i, wantErr, err)
+			}
+		}
+		if _, ok := files[missing]; ok {
+			t.Errorf("workers=%d: unexpected tokens of missing file.", workers)
+		}
+		if !os.IsNotExist(errs[missing]) {
+			t.Errorf("workers=%d: error mismatch for missing file; expected not exist error, got %v.", workers, errs[missing])
+		}
+		if len(files) != len(inputs) || len(errs) != 2 {
+			t.Errorf("workers=%d: result count mismatch; expected %d files and 2 errors, got %d files and %d errors.", workers, len(inputs), len(files), len(errs))
+		}
+	}
+}
+
+// TestParseConcurrent lexes inputs from multiple goroutines, using both Parse
+// and Lexers with different options; run with -race to detect shared mutable
+// state.
+func TestParseConcurrent(t *testing.T) {
+	inputs := []string{
+		source,
+		identSource,
+		numberSource,
+		"x := 'a\ny := \"b",
+		"//line foo.go:10:5\nx\r\ny\rz",
+	}
+	// parse lexes the input with the provided options.
+	parse := func(input string, noSemicolons, emitTrivia bool) ([]token.Token, ErrorList) {
+		lx := New(input)
+		lx.NoSemicolons = noSemicolons
+		lx.EmitTrivia = emitTrivia
+		var tokens []token.Token
+		for {
+			tok, ok := lx.Next()
+			if !ok {
+				break
+			}
+			tokens = append(tokens, tok)
+		}
+		return tokens, lx.Errors()
+	}
+	type result struct {
+		tokens []token.Token
+		errs   ErrorList
+	}
+	// Sequential results, indexed by input and option combination.
+	want := make([][4]result, len(inputs))
+	for i, input := range inputs {
+		for opt := 0; opt < 4; opt++ {
+			want[i][opt].tokens, want[i][opt].errs = parse(input, opt&1 != 0, opt&2 != 0)
+		}
+	}
+
+	const goroutines = 16
+	var wg sync.WaitGroup
+	errc := make(chan error, goroutines)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for n := 0; n < 10; n++ {
+				i := (g + n) % len(inputs)
+				opt := (g + n) % 4
+				tokens, errs := parse(inputs[i], opt&1 != 0, opt&2 != 0)
+				if !reflect.DeepEqual(tokens, want[i][opt].tokens) || !reflect.DeepEqual(errs, want[i][opt].errs) {
+					errc <- fmt.Errorf("goroutine %d: result mismatch of input %d with options %d", g, i, opt)
+					return
+				}
+				// Parse inserts semicolons and emits no trivia.
+				tokens, err := Parse(inputs[i])
+				var perrs ErrorList
+				if err != nil {
+					perrs = err.(ErrorList)
+				}
+				if !token.TokensEqual(tokens, want[i][0].tokens) || !reflect.DeepEqual(perrs, want[i][0].errs) {
+					errc <- fmt.Errorf("goroutine %d: Parse result mismatch of input %d", g, i)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errc)
+	for err := range errc {
+		t.Error(err)
+	}
+}
+
+func TestParseChan(t *testing.T) {
+	inputs := []string{
+		source,
+		"package p\n\nfunc f() int {\n\treturn 42 // comment\n}\n",
+		"x := 'a\ny := \"b",
+	}
+	for i, input := range inputs {
+		want, wantErr := Parse(input)
+		var got []token.Token
+		tokens, errc := ParseChan(input)
+		for tok := range tokens {
+			got = append(got, tok)
+		}
+		err := <-errc
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("i=%d: tokens mismatch; expected %v, got %v.", i, want, got)
+		}
+		if !reflect.DeepEqual(err, wantErr) {
+			t.Errorf("i=%d: error mismatch; expected %v, got %v.", i, wantErr, err)
+		}
+	}
+}
+
+func TestParseChanContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	tokens, errc := ParseChanContext(ctx, source)
+	<-tokens
+	cancel()
+	// Drain the buffered tokens; the channel is closed once the lexer stops.
+	for range tokens {
+	}
+	if err := <-errc; err != context.Canceled {
+		t.Errorf("error mismatch; expected %v, got %v.", context.Canceled, err)
+	}
+}
+
+func TestLexer(t *testing.T) {
+	inputs := []string{
+		source,
+		"package p\n\nfunc f() int {\n\treturn 42 // comment\n}\n",
+		"x := 'a\ny := \"b",
+		"",
+	}
+	lx := New("")
+	for i, input := range inputs {
+		want, wantErr := Parse(input)
+		for j, fresh := range []bool{true, false} {
+			if fresh {
+				lx = New(input)
+			} else {
+				lx.Reset(input)
+			}
+			var got []token.Token
+			for {
+				tok, ok := lx.Next()
+				if !ok {
+					break
+				}
+				got = append(got, tok)
+			}
+			if len(got) != len(want) || (len(got) > 0 && !reflect.DeepEqual(got, want)) {
+				t.Errorf("i=%d, j=%d: tokens mismatch; expected %v, got %v.", i, j, want, got)
+			}
+			var err error
+			if errs := lx.Errors(); len(errs) > 0 {
+				err = errs
+			}
+			if !reflect.DeepEqual(err, wantErr) {
+				t.Errorf("i=%d, j=%d: error mismatch; expected %v, got %v.", i, j, wantErr, err)
+			}
+		}
+	}
+}
+
+func TestLexerStrictNumbers(t *testing.T) {
+	golden := []struct {
+		in     string
+		strict bool
+		errs   []string
+	}{
+		{in: "078.", strict: false},
+		{in: "078.", strict: true, errs: []string{`leading zero in non-octal mantissa of floating-point constant "078."`}},
+		{in: "0123", strict: false},
+		{in: "0123", strict: true},
+		{in: "0123.", strict: true},
+		{in: "078.5", strict: true},
+		{in: "078e1", strict: true},
+		{in: "78.", strict: true},
+	}
+
+	for i, g := range golden {
+		want, err := Parse(g.in)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		lx := New(g.in)
+		lx.StrictNumbers = g.strict
+		var got []token.Token
+		for {
+			tok, ok := lx.Next()
+			if !ok {
+				break
+			}
+			got = append(got, tok)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("i=%d: tokens mismatch; expected %v, got %v.", i, want, got)
+		}
+		var errs []string
+		for _, err := range lx.Errors() {
+			errs = append(errs, err.Error())
+		}
+		if !reflect.DeepEqual(errs, g.errs) {
+			t.Errorf("i=%d: errors mismatch; expected %q, got %q.", i, g.errs, errs)
+		}
+	}
+}
+
+func TestLexerNoSemicolons(t *testing.T) {
+	const input = "x := f(1)\nreturn x\n"
+	golden := []struct {
+		noSemicolons bool
+		want         []token.Token
+	}{
+		{
+			noSemicolons: false,
+			want: []token.Token{
+				{Kind: token.Ident, Val: "x", Line: 1, Col: 1, Offset: 0},
+				{Kind: token.DeclAssign, Val: ":=", Line: 1, Col: 3, Offset: 2},
+				{Kind: token.Ident, Val: "f", Line: 1, Col: 6, Offset: 5},
+				{Kind: token.Lparen, Val: "(", Line: 1, Col: 7, Offset: 6},
+				{Kind: token.Int, Val: "1", Line: 1, Col: 8, Offset: 7},
+				{Kind: token.Rparen, Val: ")", Line: 1, Col: 9, Offset: 8},
+				{Kind: token.Semicolon, Val: ";", Line: 1, Col: 10, Offset: 9},
+				{Kind: token.Return, Val: "return", Line: 2, Col: 1, Offset: 10},
+				{Kind: token.Ident, Val: "x", Line: 2, Col: 8, Offset: 17},
+				{Kind: token.Semicolon, Val: ";", Line: 2, Col: 9, Offset: 18},
+			},
+		},
+		{
+			noSemicolons: true,
+			want: []token.Token{
+				{Kind: token.Ident, Val: "x", Line: 1, Col: 1, Offset: 0},
+				{Kind: token.DeclAssign, Val: ":=", Line: 1, Col: 3, Offset: 2},
+				{Kind: token.Ident, Val: "f", Line: 1, Col: 6, Offset: 5},
+				{Kind: token.Lparen, Val: "(", Line: 1, Col: 7, Offset: 6},
+				{Kind: token.Int, Val: "1", Line: 1, Col: 8, Offset: 7},
+				{Kind: token.Rparen, Val: ")", Line: 1, Col: 9, Offset: 8},
+				{Kind: token.Return, Val: "return", Line: 2, Col: 1, Offset: 10},
+				{Kind: token.Ident, Val: "x", Line: 2, Col: 8, Offset: 17},
+			},
+		},
+	}
+
+	for i, g := range golden {
+		lx := New(input)
+		lx.NoSemicolons = g.noSemicolons
+		var got []token.Token
+		for {
+			tok, ok := lx.Next()
+			if !ok {
+				break
+			}
+			got = append(got, tok)
+		}
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: tokens mismatch; expected %v, got %v.", i, g.want, got)
+		}
+	}
+	// Parse inserts semicolons.
+	tokens, err := Parse(input)
+	if err != nil {
+		t.Fatalf("unexpected error; %v", err)
+	}
+	if !reflect.DeepEqual(tokens, golden[0].want) {
+		t.Errorf("Parse tokens mismatch; expected %v, got %v.", golden[0].want, tokens)
+	}
+}
+
+func TestLexerAllowShebang(t *testing.T) {
+	golden := []struct {
+		in    string
+		allow bool
+		want  []token.Token
+		err   string
+	}{
+		{
+			in:    "#!/usr/bin/env gorun\npackage main\n",
+			allow: true,
+			want: []token.Token{
+				{Kind: token.Package, Val: "package", Line: 2, Col: 1, Offset: 21},
+				{Kind: token.Ident, Val: "main", Line: 2, Col: 9, Offset: 29},
+				{Kind: token.Semicolon, Val: ";", Line: 2, Col: 13, Offset: 33},
+			},
+		},
+		{
+			in:    "#!/usr/bin/env gorun",
+			allow: true,
+		},
+		{
+			in:    "package main\n",
+			allow: true,
+			want: []token.Token{
+				{Kind: token.Package, Val: "package", Line: 1, Col: 1, Offset: 0},
+				{Kind: token.Ident, Val: "main", Line: 1, Col: 9, Offset: 8},
+				{Kind: token.Semicolon, Val: ";", Line: 1, Col: 13, Offset: 12},
+			},
+		},
+		// Option off.
+		{
+			in: "#!/usr/bin/env gorun\n",
+			want: []token.Token{
+				{Kind: token.Invalid, Val: "#", Line: 1, Col: 1, Offset: 0},
+				{Kind: token.Not, Val: "!", Line: 1, Col: 2, Offset: 1},
+				{Kind: token.Div, Val: "/", Line: 1, Col: 3, Offset: 2},
+				{Kind: token.Ident, Val: "usr", Line: 1, Col: 4, Offset: 3},
+				{Kind: token.Div, Val: "/", Line: 1, Col: 7, Offset: 6},
+				{Kind: token.Ident, Val: "bin", Line: 1, Col: 8, Offset: 7},
+				{Kind: token.Div, Val: "/", Line: 1, Col: 11, Offset: 10},
+				{Kind: token.Ident, Val: "env", Line: 1, Col: 12, Offset: 11},
+				{Kind: token.Ident, Val: "gorun", Line: 1, Col: 16, Offset: 15},
+				{Kind: token.Semicolon, Val: ";", Line: 1, Col: 21, Offset: 20},
+			},
+			err: "syntax error: unexpected U+0023 '#'",
+		},
+		// Shebang not at the start of the input.
+		{
+			in:    " #!x",
+			allow: true,
+			want: []token.Token{
+				{Kind: token.Invalid, Val: "#", Line: 1, Col: 2, Offset: 1},
+				{Kind: token.Not, Val: "!", Line: 1, Col: 3, Offset: 2},
+				{Kind: token.Ident, Val: "x", Line: 1, Col: 4, Offset: 3},
+				{Kind: token.Semicolon, Val: ";", Line: 1, Col: 5, Offset: 4},
+			},
+			err: "syntax error: unexpected U+0023 '#'",
+		},
+	}
+
+	for i, g := range golden {
+		lx := New(g.in)
+		lx.AllowShebang = g.allow
+		var got []token.Token
+		for {
+			tok, ok := lx.Next()
+			if !ok {
+				break
+			}
+			got = append(got, tok)
+		}
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: tokens mismatch; expected %v, got %v.", i, g.want, got)
+		}
+		var err string
+		if errs := lx.Errors(); len(errs) > 0 {
+			err = errs.Error()
+		}
+		if err != g.err {
+			t.Errorf("i=%d: error mismatch; expected %q, got %q.", i, g.err, err)
+		}
+	}
+}
+
+func TestLexerEmitTrivia(t *testing.T) {
+	inputs := []string{
+		source,
+		"",
+		"package p\n\nfunc f() int {\n\treturn 42 // comment\n}\n",
+		"x  \t/* a */  // b\n\n\ty /* c\n */ z  ",
+		"x := 'a\ny := \"b\n",
+		"//line foo.go:10\nx\n",
+		"x \r\ny\r\n",
+	}
+	for i, input := range inputs {
+		want, wantErr := Parse(input)
+		wantSrc := input
+		if i == 0 {
+			// Carriage returns are stripped from comments and raw string
+			// literals.
+			wantSrc = strings.Replace(input, "\r", "", -1)
+		}
+		lx := New(input)
+		lx.EmitTrivia = true
+		var src string
+		var got []token.Token
+		for {
+			tok, ok := lx.Next()
+			if !ok {
+				break
+			}
+			src += tok.Val
+			switch tok.Kind {
+			case token.Whitespace, token.Newline:
+				// Drop trivia.
+				continue
+			case token.Semicolon:
+				if tok.Val == "" {
+					// Restore automatically inserted semicolons.
+					tok.Val = ";"
+				}
+			}
+			got = append(got, tok)
+		}
+		if src != wantSrc {
+			t.Errorf("i=%d: source mismatch; expected %q, got %q.", i, wantSrc, src)
+		}
+		if len(got) != len(want) || (len(got) > 0 && !reflect.DeepEqual(got, want)) {
+			t.Errorf("i=%d: tokens mismatch; expected %v, got %v.", i, want, got)
+		}
+		var err error
+		if errs := lx.Errors(); len(errs) > 0 {
+			err = errs
+		}
+		if !reflect.DeepEqual(err, wantErr) {
+			t.Errorf("i=%d: error mismatch; expected %v, got %v.", i, wantErr, err)
+		}
+	}
+
+	// Positions of trivia.
+	lx := New("x \t// c\n")
+	lx.EmitTrivia = true
+	golden := []token.Token{
+		{Kind: token.Ident, Val: "x", Line: 1, Col: 1, Offset: 0},
+		{Kind: token.Semicolon, Val: "", Line: 1, Col: 2, Offset: 1},
+		{Kind: token.Whitespace, Val: " \t", Line: 1, Col: 2, Offset: 1},
+		{Kind: token.Comment, Val: "// c", Line: 1, Col: 4, Offset: 3},
+		{Kind: token.Newline, Val: "\n", Line: 1, Col: 8, Offset: 7},
+	}
+	for i, g := range golden {
+		got, ok := lx.Next()
+		if !ok {
+			t.Errorf("i=%d: unexpected end of tokens", i)
+			break
+		}
+		if got != g {
+			t.Errorf("i=%d: token mismatch; expected %#v, got %#v.", i, g, got)
+		}
+	}
+	if tok, ok := lx.Next(); ok {
+		t.Errorf("unexpected token %v", tok)
+	}
+}
+
+func TestLexerNormalizeCRLF(t *testing.T) {
+	golden := []struct {
+		in        string
+		normalize bool
+		want      []token.Token
+	}{
+		{
+			in:        "x := 1\ry := 2\r",
+			normalize: true,
+			want: []token.Token{
+				{Kind: token.Ident, Val: "x", Line: 1, Col: 1, Offset: 0},
+				{Kind: token.DeclAssign, Val: ":=", Line: 1, Col: 3, Offset: 2},
+				{Kind: token.Int, Val: "1", Line: 1, Col: 6, Offset: 5},
+				{Kind: token.Semicolon, Val: ";", Line: 1, Col: 7, Offset: 6},
+				{Kind: token.Ident, Val: "y", Line: 2, Col: 1, Offset: 7},
+				{Kind: token.DeclAssign, Val: ":=", Line: 2, Col: 3, Offset: 9},
+				{Kind: token.Int, Val: "2", Line: 2, Col: 6, Offset: 12},
+				{Kind: token.Semicolon, Val: ";", Line: 2, Col: 7, Offset: 13},
+			},
+		},
+		{
+			in:        "x\r\ny\r\n",
+			normalize: true,
+			want: []token.Token{
+				{Kind: token.Ident, Val: "x", Line: 1, Col: 1, Offset: 0},
+				{Kind: token.Semicolon, Val: ";", Line: 1, Col: 2, Offset: 1},
+				{Kind: token.Ident, Val: "y", Line: 2, Col: 1, Offset: 3},
+				{Kind: token.Semicolon, Val: ";", Line: 2, Col: 2, Offset: 4},
+			},
+		},
+		{
+			in:        "a // c\rb /* d\re */ `f\r\rg`\r",
+			normalize: true,
+			want: []token.Token{
+				{Kind: token.Ident, Val: "a", Line: 1, Col: 1, Offset: 0},
+				{Kind: token.Semicolon, Val: ";", Line: 1, Col: 2, Offset: 1},
+				{Kind: token.Comment, Val: "// c", Line: 1, Col: 3, Offset: 2},
+				{Kind: token.Ident, Val: "b", Line: 2, Col: 1, Offset: 7},
+				{Kind: token.Semicolon, Val: ";", Line: 2, Col: 2, Offset: 8},
+				{Kind: token.Comment, Val: "/* d\ne */", Line: 2, Col: 3, Offset: 9},
+				{Kind: token.String, Val: "`f\n\ng`", Line: 3, Col: 6, Offset: 19},
+				{Kind: token.Semicolon, Val: ";", Line: 3, Col: 12, Offset: 25},
+			},
+		},
+		// Option off.
+		{
+			in: "x := 1\ry := 2\r",
+			want: []token.Token{
+				{Kind: token.Ident, Val: "x", Line: 1, Col: 1, Offset: 0},
+				{Kind: token.DeclAssign, Val: ":=", Line: 1, Col: 3, Offset: 2},
+				{Kind: token.Int, Val: "1", Line: 1, Col: 6, Offset: 5},
+				{Kind: token.Ident, Val: "y", Line: 1, Col: 8, Offset: 7},
+				{Kind: token.DeclAssign, Val: ":=", Line: 1, Col: 10, Offset: 9},
+				{Kind: token.Int, Val: "2", Line: 1, Col: 13, Offset: 12},
+				{Kind: token.Semicolon, Val: ";", Line: 1, Col: 14, Offset: 13},
+			},
+		},
+		{
+			in: "a // c\rb /* d\re */ `f\r\rg`\r",
+			want: []token.Token{
+				{Kind: token.Ident, Val: "a", Line: 1, Col: 1, Offset: 0},
+				{Kind: token.Semicolon, Val: ";", Line: 1, Col: 2, Offset: 1},
+				{Kind: token.Comment, Val: "// cb /* de */ `fg`", Line: 1, Col: 3, Offset: 2},
+			},
+		},
+	}
+
+	for i, g := range golden {
+		lx := New(g.in)
+		lx.NormalizeCRLF = g.normalize
+		var got []token.Token
+		for {
+			tok, ok := lx.Next()
+			if !ok {
+				break
+			}
+			got = append(got, tok)
+		}
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: tokens mismatch; expected %v, got %v.", i, g.want, got)
+		}
+		if errs := lx.Errors(); len(errs) > 0 {
+			t.Errorf("i=%d: unexpected error; %v", i, errs)
+		}
+	}
+}
+
+func TestParseNoComments(t *testing.T) {
+	golden := []struct {
+		in   string
+		want []token.Token
+		err  string
+	}{
+		{
+			in: "x // comment\ny",
+			want: []token.Token{
+				{Kind: token.Ident, Val: "x", Line: 1, Col: 1, Offset: 0},
+				{Kind: token.Semicolon, Val: ";", Line: 1, Col: 2, Offset: 1},
+				{Kind: token.Ident, Val: "y", Line: 2, Col: 1, Offset: 13},
+				{Kind: token.Semicolon, Val: ";", Line: 2, Col: 2, Offset: 14},
+			},
+		},
+		{
+			in: "x /* multi-line\ncomment */ y",
+			want: []token.Token{
+				{Kind: token.Ident, Val: "x", Line: 1, Col: 1, Offset: 0},
+				{Kind: token.Semicolon, Val: ";", Line: 1, Col: 2, Offset: 1},
+				{Kind: token.Ident, Val: "y", Line: 2, Col: 12, Offset: 27},
+				{Kind: token.Semicolon, Val: ";", Line: 2, Col: 13, Offset: 28},
+			},
+		},
+		{
+			in: "f(/* a */ x) /* b */",
+			want: []token.Token{
+				{Kind: token.Ident, Val: "f", Line: 1, Col: 1, Offset: 0},
+				{Kind: token.Lparen, Val: "(", Line: 1, Col: 2, Offset: 1},
+				{Kind: token.Ident, Val: "x", Line: 1, Col: 11, Offset: 10},
+				{Kind: token.Rparen, Val: ")", Line: 1, Col: 12, Offset: 11},
+				{Kind: token.Semicolon, Val: ";", Line: 1, Col: 13, Offset: 12},
+			},
+		},
+		{
+			in: "x /* unterminated",
+			want: []token.Token{
+				{Kind: token.Ident, Val: "x", Line: 1, Col: 1, Offset: 0},
+				{Kind: token.Semicolon, Val: ";", Line: 1, Col: 2, Offset: 1},
+			},
+			err: "unexpected eof in comment",
+		},
+	}
+
+	for i, g := range golden {
+		got, err := ParseNoComments(g.in)
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: tokens mismatch; expected %v, got %v.", i, g.want, got)
+		}
+		var gotErr string
+		if err != nil {
+			gotErr = err.Error()
+		}
+		if gotErr != g.err {
+			t.Errorf("i=%d: error mismatch; expected %q, got %q.", i, g.err, gotErr)
+		}
+	}
+}
+
+func TestASCIISet(t *testing.T) {
+	for _, chars := range []string{whitespace, decimal, octal, hex, ""} {
+		set := makeASCIISet(chars)
+		for c := 0; c < utf8.RuneSelf; c++ {
+			want := strings.IndexByte(chars, byte(c)) != -1
+			if got := set.contains(byte(c)); got != want {
+				t.Errorf("membership mismatch of %q in set %q; expected %v, got %v.", rune(c), chars, want, got)
+			}
+		}
+	}
+}
+
+func TestParseStrict(t *testing.T) {
+	golden := []struct {
+		in string
+		// Number of tokens, including invalid tokens.
+		n   int
+		err string
+	}{
+		{in: "x + y", n: 4},
+		{in: "x # y", n: 4, err: "lexer.ParseStrict: 1 invalid token; first at 1:3: syntax error: unexpected U+0023 '#'"},
+		{in: "x # y\nz := '12'", n: 7, err: "lexer.ParseStrict: 2 invalid tokens; first at 1:3: syntax error: unexpected U+0023 '#'"},
+		{in: "a := `x` + \"\\q\"\n\tb # c", n: 9, err: "lexer.ParseStrict: 2 invalid tokens; first at 1:12: unknown escape sequence U+0071 'q'"},
+	}
+
+	for i, g := range golden {
+		tokens, err := ParseStrict(g.in)
+		errstr := ""
+		if err != nil {
+			errstr = err.Error()
+		}
+		if errstr != g.err {
+			t.Errorf("i=%d: error mismatch; expected %q, got %q.", i, g.err, errstr)
+		}
+		if len(tokens) != g.n {
+			t.Errorf("i=%d: number of tokens mismatch; expected %d, got %d.", i, g.n, len(tokens))
+		}
+	}
+}
+
+func TestParseLimited(t *testing.T) {
+	input := "package p\n\n" + strings.Repeat("var x = a + b\n", 1000)
+	all, err := Parse(input)
+	if err != nil {
+		t.Fatalf("unexpected error; %v", err)
+	}
+	golden := []struct {
+		maxTokens int
+		n         int
+		err       error
+	}{
+		{maxTokens: 1, n: 1, err: ErrInputTooLarge},
+		{maxTokens: 10, n: 10, err: ErrInputTooLarge},
+		{maxTokens: len(all) - 1, n: len(all) - 1, err: ErrInputTooLarge},
+		{maxTokens: len(all), n: len(all), err: nil},
+		{maxTokens: len(all) + 1, n: len(all), err: nil},
+		{maxTokens: 0, n: len(all), err: nil},
+		{maxTokens: -1, n: len(all), err: nil},
+	}
+
+	for i, g := range golden {
+		got, err := ParseLimited(input, g.maxTokens)
+		if err != g.err {
+			t.Errorf("i=%d: error mismatch; expected %v, got %v.", i, g.err, err)
+		}
+		if !reflect.DeepEqual(got, all[:g.n]) {
+			t.Errorf("i=%d: tokens mismatch; expected %d tokens, got %d.", i, g.n, len(got))
+		}
+	}
+}
+
 func BenchmarkParse(b *testing.B) {
+	b.ReportAllocs()
 	b.SetBytes(int64(len(source)))
 	for i := 0; i < b.N; i++ {
 		Parse(source)
 	}
 }
+
+// identSource is ASCII-heavy source text, consisting mostly of identifiers and
+// keywords.
+var identSource = strings.Repeat("func (buf *Buffer) WriteString(s string) (n int, err error) {\n\tbuf.lastRead = opInvalid\n\treturn buf.grow64(len(s))\n}\n", 100)
+
+func BenchmarkParseIdents(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(identSource)))
+	for i := 0; i < b.N; i++ {
+		Parse(identSource)
+	}
+}
+
+// rawSource contains a large raw string literal and a license header block
+// comment.
+var rawSource = "/*\n" + strings.Repeat(" * Licensed under the Apache License, Version 2.0.\n", 200) + " */\n\npackage p\n\nconst s = `" + strings.Repeat("The quick brown fox jumps over the lazy dog.\n", 2000) + "`\n"
+
+func BenchmarkParseRaw(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(rawSource)))
+	for i := 0; i < b.N; i++ {
+		Parse(rawSource)
+	}
+}
+
+// numberSource is numeric-heavy source text, consisting mostly of integer,
+// floating-point and imaginary literals.
+var numberSource = strings.Repeat("var t = [...]float64{0, 1, 42, 0x7FFF, 0xdeadBEEF, 0o755, 0644, 3.14159, 1e-15, 6.022e+23, .5, 1., 2i, 0x1Fi, 123456789}\n", 100)
+
+func BenchmarkParseNumbers(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(numberSource)))
+	for i := 0; i < b.N; i++ {
+		Parse(numberSource)
+	}
+}
+
+// BenchmarkParseStdlib lexes a sample of real Go source, consisting of a few
+// large files of the standard library in testdata/stdlib.
+func BenchmarkParseStdlib(b *testing.B) {
+	b.ReportAllocs()
+	paths, err := filepath.Glob(filepath.Join("testdata", "stdlib", "*.go"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	var inputs []string
+	var n int64
+	for _, path := range paths {
+		buf, err := ioutil.ReadFile(path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		input := string(buf)
+		if _, err := Parse(input); err != nil {
+			b.Fatalf("%s: unexpected error; %v", path, err)
+		}
+		inputs = append(inputs, input)
+		n += int64(len(input))
+	}
+	if len(inputs) == 0 {
+		b.Fatal("no source files in testdata/stdlib")
+	}
+	b.SetBytes(n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, input := range inputs {
+			Parse(input)
+		}
+	}
+}
+
+// lexAll lexes each of the golden inputs using the provided function to obtain
+// a lexer for the input.
+func lexAll(b *testing.B, lexer func(input string) *Lexer) {
+	for i := 0; i < b.N; i++ {
+		for _, g := range golden {
+			lx := lexer(g.in)
+			for {
+				if _, ok := lx.Next(); !ok {
+					break
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkLexerNew(b *testing.B) {
+	b.ReportAllocs()
+	lexAll(b, New)
+}
+
+func BenchmarkLexerReset(b *testing.B) {
+	b.ReportAllocs()
+	lx := New("")
+	lexAll(b, func(input string) *Lexer {
+		lx.Reset(input)
+		return lx
+	})
+}
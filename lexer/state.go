@@ -1,18 +1,14 @@
 package lexer
 
 import (
-	"errors"
 	"fmt"
-	"strconv"
 	"strings"
-	"unicode"
 	"unicode/utf8"
 
 	"github.com/mewlang/go/token"
 )
 
-// TODO(u): Optimize lexString, lexStringRaw, lexLineComment and lexBlockComment
-// using strings.IndexAny.
+// TODO(u): Optimize lexString and lexLineComment using strings.IndexAny.
 
 const (
 	// whitespace specifies the white space characters (except newline), which
@@ -27,6 +23,14 @@ const (
 	hex = "0123456789ABCDEFabcdef"
 )
 
+// Character sets of acceptRun. The sets are shared by all lexers and must not be
+// modified.
+var (
+	whitespaceSet = makeASCIISet(whitespace)
+	decimalSet    = makeASCIISet(decimal)
+	hexSet        = makeASCIISet(hex)
+)
+
 // A stateFn represents the state of the lexer as a function that returns a
 // state function.
 type stateFn func(l *lexer) stateFn
@@ -35,7 +39,13 @@ type stateFn func(l *lexer) stateFn
 // state function of the lexer.
 func lexToken(l *lexer) stateFn {
 	// Ignore white space characters (except newline).
-	l.ignoreRun(whitespace)
+	if l.emitTrivia {
+		if l.acceptRun(whitespaceSet) {
+			l.emit(token.Whitespace)
+		}
+	} else {
+		l.ignoreRun(whitespaceSet)
+	}
 
 	r := l.next()
 	switch r {
@@ -44,8 +54,12 @@ func lexToken(l *lexer) stateFn {
 		// Terminate the lexer with a nil state function.
 		return nil
 	case '\n':
-		l.ignore()
 		insertSemicolon(l)
+		if l.emitTrivia {
+			l.emit(token.Newline)
+		} else {
+			l.ignore()
+		}
 		// Update the index to the first token of the current line.
 		l.first = len(l.tokens)
 		return lexToken
@@ -108,10 +122,14 @@ func lexToken(l *lexer) stateFn {
 		return lexString
 	case '`':
 		return lexRawString
+	case '#':
+		if l.allowShebang && l.start == 0 && strings.HasPrefix(l.input, "#!") {
+			return lexShebang
+		}
 	}
 
 	// Check if r is a Unicode letter or an underscore character.
-	if isLetter(r) {
+	if IsIdentStart(r) {
 		return lexKeywordOrIdent
 	}
 
@@ -122,10 +140,26 @@ func lexToken(l *lexer) stateFn {
 	return lexToken
 }
 
-// isLetter returns true if r is a Unicode letter or an underscore, and false
-// otherwise.
-func isLetter(r rune) bool {
-	return unicode.IsLetter(r) || r == '_'
+// lexShebang ignores a shebang line (#!/usr/bin/env gorun) at the start of the
+// input. A hash character (#) has already been consumed.
+func lexShebang(l *lexer) stateFn {
+	for {
+		switch l.next() {
+		case '\n':
+			// Leave the newline to lexToken.
+			l.backup()
+			fallthrough
+		case eof:
+			l.ignore()
+			return lexToken
+		}
+	}
+}
+
+// isASCIIIdent returns true if b is an ASCII letter, digit or underscore, and
+// false otherwise.
+func isASCIIIdent(b byte) bool {
+	return 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9' || b == '_'
 }
 
 // isValid returns true if r is a valid Unicode code point in a Go source text,
@@ -157,24 +191,42 @@ func lexDivOrComment(l *lexer) stateFn {
 	}
 }
 
-// lexLineComment lexes a line comment. A line comment acts like a newline.
+// lexLineComment lexes a line comment. A line comment acts like a newline. A
+// line comment which starts at column 1 may be a line directive, which remaps
+// the position of subsequent tokens.
 func lexLineComment(l *lexer) stateFn {
 	kind := token.Comment
+	// Line directives must start at column 1; the byte order mark at the start
+	// of the input is ignored.
+	directive := l.start == 0 || l.input[l.start-1] == '\n' || (l.start == len(string(bom)) && strings.HasPrefix(l.input, string(bom)))
 	insertSemicolon(l)
 	for {
 		r := l.next()
 		switch r {
 		case eof:
 			// Strip carriage returns.
-			s := strings.Replace(l.input[l.start:l.pos], "\r", "", -1)
+			s := l.stripCR(l.input[l.start:l.pos])
 			l.emitCustom(kind, s)
 
 			// Terminate the lexer with a nil state function.
 			return nil
 		case '\n':
 			// Strip carriage returns and trailing newline.
-			s := strings.Replace(l.input[l.start:l.pos-1], "\r", "", -1)
+			s := l.stripCR(l.input[l.start : l.pos-1])
 			l.emitCustom(kind, s)
+			if l.emitTrivia {
+				// The trailing newline precedes the current position.
+				l.tokens = append(l.tokens, token.Token{Kind: token.Newline, Val: l.input[l.pos-1 : l.pos], Line: l.line, Col: l.prevCol + 1, Offset: l.pos - 1, Filename: l.filename})
+			}
+
+			// Remap the position of subsequent tokens.
+			if filename, line, col, ok := parseLineDirective(s); directive && ok {
+				l.filename = filename
+				l.line, l.startLine = line-1, line-1
+				if col > 0 {
+					l.col, l.startCol = col-1, col-1
+				}
+			}
 
 			// Update the index to the first token of the current line.
 			l.first = len(l.tokens)
@@ -187,38 +239,108 @@ func lexLineComment(l *lexer) stateFn {
 	}
 }
 
+// parseLineDirective parses the provided line comment as a line directive,
+// which specifies the file name, line and optionally the column of the source
+// text immediately following the directive. The directive must be the entire
+// comment, and have one of the following forms:
+//
+//    //line path:line
+//    //line path:line:col
+//
+// The column is 0 if not specified.
+func parseLineDirective(s string) (filename string, line, col int, ok bool) {
+	const prefix = "//line "
+	if !strings.HasPrefix(s, prefix) {
+		return "", 0, 0, false
+	}
+	s = s[len(prefix):]
+	filename, line, ok = splitLineNum(s)
+	if !ok {
+		return "", 0, 0, false
+	}
+	// Try the path:line:col form before the path:line form, since the path may
+	// contain colons.
+	if path, n, ok := splitLineNum(filename); ok {
+		return path, n, line, true
+	}
+	return filename, line, 0, true
+}
+
+// splitLineNum splits s into a non-empty prefix and a positive number, which
+// are separated by the last colon of s.
+func splitLineNum(s string) (prefix string, n int, ok bool) {
+	i := strings.LastIndex(s, ":")
+	if i < 1 {
+		return "", 0, false
+	}
+	n, ok = parseNum(s[i+1:])
+	if !ok || n <= 0 {
+		return "", 0, false
+	}
+	return s[:i], n, true
+}
+
+// parseNum parses s as a decimal number, and returns its value and a boolean
+// indicating success. Unlike strconv.Atoi, parseNum does not allocate an error
+// for invalid input, which is common as comments such as "//line foo:bar" are
+// tried as line directives.
+func parseNum(s string) (n int, ok bool) {
+	const max = 1<<31 - 1
+	if len(s) == 0 {
+		return 0, false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, false
+		}
+		n = n*10 + int(s[i]-'0')
+		if n > max {
+			return 0, false
+		}
+	}
+	return n, true
+}
+
 // lexBlockComment lexes a block comment. A block comment containing one or more
 // newlines acts like a newline, otherwise it acts like a space.
 func lexBlockComment(l *lexer) stateFn {
-	hasNewline := false
+	// Skip directly past the closing */, or to the end of input.
+	end := len(l.input)
+	i := strings.Index(l.input[l.pos:], "*/")
+	if i != -1 {
+		end = l.pos + i + len("*/")
+	}
+	newlines := "\n"
+	if l.normalizeCRLF {
+		newlines = "\n\r"
+	}
+	hasNewline := strings.IndexAny(l.input[l.pos:end], newlines) != -1
 	kind := token.Comment
-	for !strings.HasSuffix(l.input[l.start:l.pos], "*/") {
-		r := l.next()
-		switch r {
-		case eof:
-			insertSemicolon(l)
-
-			// Strip carriage returns.
-			s := strings.Replace(l.input[l.start:l.pos], "\r", "", -1)
-			l.emitCustom(token.Comment|token.Invalid, s)
-
-			// Terminate the lexer with a nil state function.
-			l.errorf("unexpected eof in comment")
-			return nil
-		case '\n':
-			hasNewline = true
-		default:
-			if !isValid(r) {
+	if !l.advance(end) {
+		// Record the errors of each invalid code point.
+		for l.pos < end {
+			if r := l.next(); !isValid(r) {
 				kind |= token.Invalid
 			}
 		}
 	}
+	if i == -1 {
+		insertSemicolon(l)
+
+		// Strip carriage returns.
+		s := l.stripCR(l.input[l.start:l.pos])
+		l.emitCustom(token.Comment|token.Invalid, s)
+
+		// Terminate the lexer with a nil state function.
+		l.errorf("unexpected eof in comment")
+		return nil
+	}
 	if hasNewline {
 		insertSemicolon(l)
 	}
 
 	// Strip carriage returns.
-	s := strings.Replace(l.input[l.start:l.pos], "\r", "", -1)
+	s := l.stripCR(l.input[l.start:l.pos])
 	l.emitCustom(kind, s)
 
 	if hasNewline {
@@ -462,7 +584,7 @@ func lexSubOrDec(l *lexer) stateFn {
 }
 
 // lexDotOrNumber lexes a dot delimiter (.), an ellipsis delimiter (...), or a
-// number (123, 0x7B, 0173, .123, 123.45, 1e-15, 2i).
+// number (123, 0x7B, 0173, 0o173, .123, 123.45, 1e-15, 2i, 0x7Bi).
 func lexDotOrNumber(l *lexer) stateFn {
 	// Integer part.
 	var kind token.Kind
@@ -470,18 +592,39 @@ func lexDotOrNumber(l *lexer) stateFn {
 		kind = token.Int
 		// Early return for hexadecimal constant.
 		if l.accept("xX") {
-			if !l.acceptRun(hex) {
+			if !l.acceptRun(hexSet) {
 				l.emit(token.Int | token.Invalid)
 
 				// Append error but continue lexing.
 				l.errorf("missing digits in hexadecimal constant")
 				return lexToken
 			}
-			l.emit(token.Int)
+			l.emit(imagSuffix(l))
+			return lexToken
+		}
+		// Early return for octal constant with an explicit prefix.
+		if l.accept("oO") {
+			if !l.acceptRun(decimalSet) {
+				l.emit(token.Int | token.Invalid)
+
+				// Append error but continue lexing.
+				l.errorf("octal literal has no digits")
+				return lexToken
+			}
+			s := l.input[l.start+2 : l.pos]
+			kind = imagSuffix(l)
+			if pos := strings.IndexAny(s, "89"); pos != -1 {
+				l.emit(kind | token.Invalid)
+
+				// Append error but continue lexing.
+				l.errorf("invalid digit %q in octal literal", s[pos])
+				return lexToken
+			}
+			l.emit(kind)
 			return lexToken
 		}
 	}
-	if l.acceptRun(decimal) {
+	if l.acceptRun(decimalSet) {
 		kind = token.Int
 	}
 
@@ -495,7 +638,7 @@ func lexDotOrNumber(l *lexer) stateFn {
 	}
 
 	// Fraction part.
-	if l.acceptRun(decimal) {
+	if l.acceptRun(decimalSet) {
 		kind = token.Float
 	}
 
@@ -518,7 +661,7 @@ func lexDotOrNumber(l *lexer) stateFn {
 		// Optional sign.
 		l.accept("+-")
 
-		if !l.acceptRun(decimal) {
+		if !l.acceptRun(decimalSet) {
 			l.emit(token.Float | token.Invalid)
 
 			// Append error but continue lexing.
@@ -532,6 +675,16 @@ func lexDotOrNumber(l *lexer) stateFn {
 		kind = token.Imag
 	}
 
+	// Report leading-zero decimal mantissas in strict mode, e.g. "078.".
+	if l.strictNumbers && kind == token.Float {
+		if s := l.input[l.start:l.pos]; len(s) > 2 && s[0] == '0' && s[len(s)-1] == '.' {
+			if strings.ContainsAny(s, "89") {
+				// Append warning but emit a valid token.
+				l.errorf("leading zero in non-octal mantissa of floating-point constant %q", s)
+			}
+		}
+	}
+
 	// Validate octal numbers.
 	if kind == token.Int {
 		if s := l.input[l.start:l.pos]; s[0] == '0' {
@@ -549,6 +702,19 @@ func lexDotOrNumber(l *lexer) stateFn {
 	return lexToken
 }
 
+// imagSuffix accepts the imaginary suffix (i) of a prefixed integer literal
+// (0x1Fi, 0o17i), and returns the token type of the literal.
+//
+//    imaginary_lit = (decimal_digits | int_lit | float_lit) "i" .
+//
+// ref: https://golang.org/ref/spec#Imaginary_literals
+func imagSuffix(l *lexer) token.Kind {
+	if l.accept("i") {
+		return token.Imag
+	}
+	return token.Int
+}
+
 // lexRune lexes a rune literal ('a'). A single quote character (') has already
 // been consumed.
 func lexRune(l *lexer) stateFn {
@@ -659,6 +825,14 @@ func lexString(l *lexer) stateFn {
 // lexRawString lexes a raw string literal (`foo`). A back quote character (`)
 // has already been consumed.
 func lexRawString(l *lexer) stateFn {
+	// Skip directly to the closing backtick, or to the end of input. Should the
+	// skipped text contain invalid code points, it is instead lexed one rune at
+	// a time below.
+	end := len(l.input)
+	if i := strings.IndexByte(l.input[l.pos:], '`'); i != -1 {
+		end = l.pos + i
+	}
+	l.advance(end)
 	kind := token.String
 	for {
 		r := l.next()
@@ -673,7 +847,7 @@ func lexRawString(l *lexer) stateFn {
 			return nil
 		case '`':
 			// Strip carriage returns.
-			s := strings.Replace(l.input[l.start:l.pos], "\r", "", -1)
+			s := l.stripCR(l.input[l.start:l.pos])
 			l.emitCustom(kind, s)
 			return lexToken
 		default:
@@ -684,7 +858,8 @@ func lexRawString(l *lexer) stateFn {
 	}
 }
 
-// keywords specifies the reserved keywords of the Go programming language.
+// keywords specifies the reserved keywords of the Go programming language. The
+// map is shared by all lexers and must not be modified.
 var keywords = map[string]token.Kind{
 	"break":       token.Break,
 	"case":        token.Case,
@@ -716,12 +891,19 @@ var keywords = map[string]token.Kind{
 // lexKeywordOrIdent lexes a keyword, or an identifier. A Unicode letter or an
 // underscore character (_) has already been consumed.
 func lexKeywordOrIdent(l *lexer) stateFn {
+	// Fast path for ASCII letters, digits and underscores, which avoids the
+	// overhead of decoding runes and Unicode table lookups.
+	for l.pos < len(l.input) && isASCIIIdent(l.input[l.pos]) {
+		l.pos++
+		l.col++
+	}
+	// Fall back to Unicode letters and digits for multibyte runes.
 	for {
 		r := l.next()
 		if r == eof {
 			break
 		}
-		if !isLetter(r) && !unicode.IsDigit(r) {
+		if !IsIdentCont(r) {
 			l.backup()
 			break
 		}
@@ -735,110 +917,39 @@ func lexKeywordOrIdent(l *lexer) stateFn {
 	return lexToken
 }
 
+// DecodeEscape decodes the backslash escape sequence at the start of s, and
+// returns the value it represents and its width in bytes; as specified by
+// token.DecodeEscape. The lexer and token.Token.Unquote decode the escape
+// sequences of literals using token.DecodeQuotedEscape.
+//
+// ref: http://golang.org/ref/spec#Rune_literals
+func DecodeEscape(s string) (r rune, width int, err error) {
+	return token.DecodeEscape(s)
+}
+
 // consumeEscape consumes an escape sequence. A valid single-character escape
 // sequence is specified by valid. Single quotes are only valid within rune
 // literals and double quotes are only valid within string literals. A backslash
 // character (\) has already been consumed.
 //
-// Several backslash escapes allow arbitrary values to be encoded as ASCII text.
-// There are four ways to represent the integer value as a numeric constant: \x
-// followed by exactly two hexadecimal digits; \u followed by exactly four
-// hexadecimal digits; \U followed by exactly eight hexadecimal digits, and a
-// plain backslash \ followed by exactly three octal digits. In each case the
-// value of the literal is the value represented by the digits in the
-// corresponding base.
-//
-// Although these representations all result in an integer, they have different
-// valid ranges. Octal escapes must represent a value between 0 and 255
-// inclusive. Hexadecimal escapes satisfy this condition by construction. The
-// escapes \u and \U represent Unicode code points so within them some values
-// are illegal, in particular those above 0x10FFFF and surrogate halves.
-//
-// After a backslash, certain single-character escapes represent special values:
-//    \a   U+0007 alert or bell
-//    \b   U+0008 backspace
-//    \f   U+000C form feed
-//    \n   U+000A line feed or newline
-//    \r   U+000D carriage return
-//    \t   U+0009 horizontal tab
-//    \v   U+000b vertical tab
-//    \\   U+005c backslash
-//    \'   U+0027 single quote  (valid escape only within rune literals)
-//    \"   U+0022 double quote  (valid escape only within string literals)
-//
-// All other sequences starting with a backslash are illegal inside rune and
-// string literals.
-//
-// ref: http://golang.org/ref/spec#Rune_literals
+// An invalid escape sequence is consumed up to and including its first
+// offending character.
 func consumeEscape(l *lexer, valid rune) error {
-	r := l.next()
-	switch r {
-	case eof:
-		return errors.New("unexpected eof in escape sequence")
-	case '0', '1', '2', '3':
-		// Octal escape.
-		for i := 0; i < 2; i++ {
-			if !l.accept(octal) {
-				r := l.next()
-				switch r {
-				case eof:
-					return errors.New("unexpected eof in octal escape")
-				case valid:
-					return fmt.Errorf("too few digits in octal escape; expected 3, got %d", 1+i)
-				}
-				return fmt.Errorf("non-octal character %#U in octal escape", r)
-			}
-		}
-		s := l.input[l.pos-3 : l.pos]
-		_, err := strconv.ParseUint(s, 8, 8)
-		if err != nil {
-			return fmt.Errorf("invalid octal escape; %v", err)
-		}
-	case 'x':
-		// Hexadecimal escape.
-		for i := 0; i < 2; i++ {
-			if !l.accept(hex) {
-				r := l.next()
-				switch r {
-				case eof:
-					return errors.New("unexpected eof in hex escape")
-				case valid:
-					return fmt.Errorf("too few digits in hex escape; expected 2, got %d", i)
-				}
-				return fmt.Errorf("non-hex character %#U in hex escape", r)
-			}
-		}
-	case 'u', 'U':
-		// Unicode escape.
-		n := 4
-		if r == 'U' {
-			n = 8
-		}
-		for i := 0; i < n; i++ {
-			if !l.accept(hex) {
-				r := l.next()
-				switch r {
-				case eof:
-					return errors.New("unexpected eof in Unicode escape")
-				case valid:
-					return fmt.Errorf("too few digits in Unicode escape; expected %d, got %d", n, i)
-				}
-				return fmt.Errorf("non-hex character %#U in Unicode escape", r)
-			}
-		}
-		s := l.input[l.pos-n : l.pos]
-		x, err := strconv.ParseUint(s, 16, 32)
-		if err != nil {
-			return fmt.Errorf("invalid Unicode escape; %v", err)
-		}
-		r := rune(x)
-		if !utf8.ValidRune(r) {
-			return fmt.Errorf("invalid Unicode code point %#U in escape sequence", r)
+	start := l.pos - 1
+	_, width, err := token.DecodeQuotedEscape(l.input[start:], valid)
+	// Consume the escape sequence using next, to track line and column numbers
+	// and to record the errors of invalid code points.
+	for l.pos < start+width {
+		l.next()
+	}
+	if err != nil {
+		return err
+	}
+	switch r := rune(l.input[start+1]); r {
+	case '\'', '"':
+		if r != valid {
+			return fmt.Errorf("unknown escape sequence %#U", r)
 		}
-	case 'a', 'b', 'f', 'n', 'r', 't', 'v', '\\', valid:
-		// Single-character escape.
-	default:
-		return fmt.Errorf("unknown escape sequence %#U", r)
 	}
 	return nil
 }
@@ -853,8 +964,13 @@ func consumeEscape(l *lexer, valid rune) error {
 //    * one of the keywords break, continue, fallthrough, or return
 //    * one of the operators and delimiters ++, --, ), ], or }
 //
+// Semicolons are not inserted if disabled by Lexer.NoSemicolons.
+//
 // ref: http://golang.org/ref/spec#Semicolons
-var insertSemicolon = func(l *lexer) {
+func insertSemicolon(l *lexer) {
+	if l.noSemicolons {
+		return
+	}
 	insert := false
 	trailingComments := false
 	tok := token.Token{
@@ -865,8 +981,8 @@ var insertSemicolon = func(l *lexer) {
 	for pos = len(l.tokens) - 1; pos >= l.first; pos-- {
 		last := l.tokens[pos]
 		switch last.Kind {
-		case token.Comment:
-			// Ignore trailing comments.
+		case token.Comment, token.Whitespace:
+			// Ignore trailing comments and white space.
 			trailingComments = true
 			continue
 		case token.Ident:
@@ -883,11 +999,17 @@ var insertSemicolon = func(l *lexer) {
 		insert = true
 		tok.Line = last.Line
 		tok.Col = last.Col + utf8.RuneCountInString(last.Val)
+		tok.Offset = l.endOffset(last)
+		tok.Filename = last.Filename
 		break
 	}
 
 	// Insert a semicolon.
 	if insert {
+		if l.emitTrivia {
+			// The semicolon is not present in the source.
+			tok.Val = ""
+		}
 		l.tokens = append(l.tokens, tok)
 
 		if trailingComments {
@@ -898,3 +1020,20 @@ var insertSemicolon = func(l *lexer) {
 		}
 	}
 }
+
+// endOffset returns the byte offset directly after the source text of the
+// provided token. Carriage returns stripped from the value of the token, or
+// normalized into newlines, are accounted for.
+func (l *lexer) endOffset(tok token.Token) int {
+	end := tok.Offset
+	for i := 0; i < len(tok.Val) && end < len(l.input); end++ {
+		switch {
+		case l.input[end] == tok.Val[i]:
+			i++
+		case l.input[end] == '\r' && tok.Val[i] == '\n' && !strings.HasPrefix(l.input[end+1:], "\n"):
+			// Lone carriage return normalized into a newline.
+			i++
+		}
+	}
+	return end
+}
@@ -0,0 +1,123 @@
+package lexer
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mewlang/go/token"
+)
+
+// Unparse returns source text which lexes to the provided tokens, ignoring
+// their positions. The token values are concatenated with a space between two
+// tokens only where they would otherwise merge, such as two identifiers or the
+// operators < and <; a newline is written after each line comment.
+//
+// The returned source text is not formatted; every semicolon is written
+// explicitly, even those which were inserted automatically while lexing. Use
+// UnparseImplicit to omit them.
+func Unparse(tokens []token.Token) string {
+	return unparse(tokens, false)
+}
+
+// UnparseImplicit is like Unparse, but writes a newline in place of each
+// semicolon which is inserted automatically when the source text is lexed
+// again; i.e. a semicolon which directly follows an identifier, a basic
+// literal, one of the keywords break, continue, fallthrough or return, or one
+// of the operators and delimiters ++, --, ), ] or }.
+func UnparseImplicit(tokens []token.Token) string {
+	return unparse(tokens, true)
+}
+
+// unparse returns source text which lexes to the provided tokens. Semicolons
+// which are inserted automatically are omitted if implicit is true.
+func unparse(tokens []token.Token, implicit bool) string {
+	buf := new(bytes.Buffer)
+	// Preceding token, if any; or a zero token at the start of a line.
+	var prev token.Token
+	for i, tok := range tokens {
+		if implicit && tok.Kind == token.Semicolon && insertsSemicolon(prev.Kind) {
+			if i < len(tokens)-1 {
+				buf.WriteString("\n")
+			}
+			prev = token.Token{}
+			continue
+		}
+		if needsSpace(prev, tok) {
+			buf.WriteString(" ")
+		}
+		buf.WriteString(tok.Val)
+		prev = tok
+		if tok.Kind == token.Comment && strings.HasPrefix(tok.Val, "//") {
+			buf.WriteString("\n")
+			prev = token.Token{}
+		}
+	}
+	return buf.String()
+}
+
+// insertsSemicolon reports whether a semicolon is automatically inserted at a
+// line break which follows a token of the provided kind.
+//
+// ref: https://golang.org/ref/spec#Semicolons
+func insertsSemicolon(kind token.Kind) bool {
+	switch kind {
+	case token.Ident, token.Int, token.Float, token.Imag, token.Rune, token.String:
+		return true
+	case token.Break, token.Continue, token.Fallthrough, token.Return:
+		return true
+	case token.Inc, token.Dec, token.Rparen, token.Rbrack, token.Rbrace:
+		return true
+	}
+	return false
+}
+
+// needsSpace reports whether a space is required between the values of two
+// adjacent tokens, to prevent them from being lexed as a single token.
+func needsSpace(prev, tok token.Token) bool {
+	if prev.Val == "" || tok.Val == "" {
+		return false
+	}
+	last, _ := utf8.DecodeLastRuneInString(prev.Val)
+	first, _ := utf8.DecodeRuneInString(tok.Val)
+	switch {
+	case IsIdentCont(last) && IsIdentCont(first):
+		// Identifiers, keywords and numbers; e.g. "x y", "1 e5".
+		return true
+	case isNumber(prev.Kind) && first == '.':
+		// Numbers followed by a dot or an ellipsis; e.g. "1 .".
+		return true
+	case last == '.' && unicode.IsDigit(first):
+		// Dots followed by a number; e.g. ". 5".
+		return true
+	case last == '/' && (first == '/' || first == '*'):
+		// Division followed by a comment or an operator; e.g. "/ /* */".
+		return true
+	case prev.Kind.IsOperator() && tok.Kind.IsOperator():
+		// Operators which form a longer operator; e.g. "< <", "& ^", ". .".
+		return isOperatorPrefix(prev.Val + string(first))
+	}
+	return false
+}
+
+// isNumber reports whether kind is an integer, floating-point or imaginary
+// literal.
+func isNumber(kind token.Kind) bool {
+	switch kind &^ token.Invalid {
+	case token.Int, token.Float, token.Imag:
+		return true
+	}
+	return false
+}
+
+// isOperatorPrefix reports whether s is a prefix of an operator or delimiter.
+// The least significant bit of token kinds is reserved for the Invalid flag.
+func isOperatorPrefix(s string) bool {
+	for kind := token.Not; kind <= token.Ellipsis; kind += 2 {
+		if strings.HasPrefix(kind.String(), s) {
+			return true
+		}
+	}
+	return false
+}
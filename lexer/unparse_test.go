@@ -0,0 +1,87 @@
+package lexer
+
+import (
+	"testing"
+
+	"github.com/mewlang/go/token"
+)
+
+func TestUnparse(t *testing.T) {
+	golden := []struct {
+		in       string
+		want     string
+		implicit string
+	}{
+		{in: "", want: "", implicit: ""},
+		{in: "package p\n", want: "package p;", implicit: "package p"},
+		{in: "x<-y", want: "x<-y;", implicit: "x<-y"},
+		{in: "a < <b", want: "a< <b;", implicit: "a< <b"},
+		{in: "a & ^b", want: "a& ^b;", implicit: "a& ^b"},
+		{in: "a / /* c */ b", want: "a/ /* c */b;", implicit: "a/ /* c */b"},
+		{in: "1 .String()", want: "1 .String();", implicit: "1 .String()"},
+		{in: "x . 5", want: "x. 5;", implicit: "x. 5"},
+		{in: "f(x)\ng(y)\n", want: "f(x);g(y);", implicit: "f(x)\ng(y)"},
+		{in: "x // c\ny", want: "x;// c\ny;", implicit: "x\n// c\ny"},
+		{in: "for {\n\tbreak\n}\n", want: "for{break;};", implicit: "for{break\n}"},
+		{in: "x /* c */ ;", want: "x/* c */;", implicit: "x/* c */;"},
+	}
+
+	for i, g := range golden {
+		tokens, err := Parse(g.in)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		got := Unparse(tokens)
+		if got != g.want {
+			t.Errorf("i=%d: source mismatch; expected %q, got %q.", i, g.want, got)
+		}
+		got = UnparseImplicit(tokens)
+		if got != g.implicit {
+			t.Errorf("i=%d: implicit source mismatch; expected %q, got %q.", i, g.implicit, got)
+		}
+	}
+}
+
+func TestUnparseRoundTrip(t *testing.T) {
+	inputs := []string{
+		source,
+		"package p\n\nimport \"fmt\"\n\n// f prints x.\nfunc f(x ...int) {\n\tfor i := range x {\n\t\tx[i] <<= 1 // double\n\t\tx[i]++\n\t}\n\tfmt.Println(x, 1.5e3, 2i, 'a', `raw\nstring`, a &^ b, -(-c), !!d)\n}\n",
+	}
+	for _, g := range golden {
+		inputs = append(inputs, g.in)
+	}
+
+	for i, input := range inputs {
+		want, err := Parse(input)
+		if err != nil {
+			// Skip lexically invalid input.
+			continue
+		}
+		for j, unparse := range []func([]token.Token) string{Unparse, UnparseImplicit} {
+			src := unparse(want)
+			got, err := Parse(src)
+			if err != nil {
+				t.Errorf("i=%d, j=%d: unexpected error for %q; %v", i, j, src, err)
+				continue
+			}
+			if !equalKindVals(got, want) {
+				t.Errorf("i=%d, j=%d: tokens mismatch for %q; expected %v, got %v.", i, j, src, want, got)
+			}
+		}
+	}
+}
+
+// equalKindVals reports whether the two token slices have the same token types
+// and values, ignoring positions.
+func equalKindVals(a, b []token.Token) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Kind != b[i].Kind || a[i].Val != b[i].Val {
+			return false
+		}
+	}
+	return true
+}
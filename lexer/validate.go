@@ -0,0 +1,48 @@
+package lexer
+
+import (
+	"fmt"
+
+	"github.com/mewlang/go/token"
+)
+
+// Validate checks the basic invariants of a token slice, as produced by Parse.
+// It returns an error identifying the first violated invariant and the index of
+// the offending token. The invariants are:
+//
+//    - no token has the token type NONE.
+//    - line and column numbers start at 1.
+//    - positions are non-decreasing.
+//
+// The lexer does not emit an end of file token, so the end of the slice denotes
+// the end of input. Positions are only compared between consecutive tokens of
+// the same file, and the position of a token following a line directive is not
+// compared, as the directive may remap it to an earlier position.
+func Validate(tokens []token.Token) error {
+	var prev token.Token
+	for i, tok := range tokens {
+		if tok.Kind == token.None {
+			return fmt.Errorf("lexer.Validate: token %d has token type NONE", i)
+		}
+		if tok.Line < 1 || tok.Col < 1 {
+			return fmt.Errorf("lexer.Validate: token %d (%v) has invalid position %d:%d", i, tok, tok.Line, tok.Col)
+		}
+		if i > 0 && !isLineDirective(prev) && tok.Filename == prev.Filename {
+			if tok.Line < prev.Line || (tok.Line == prev.Line && tok.Col < prev.Col) {
+				return fmt.Errorf("lexer.Validate: token %d (%v) at %d:%d precedes token %d (%v) at %d:%d", i, tok, tok.Line, tok.Col, i-1, prev, prev.Line, prev.Col)
+			}
+		}
+		prev = tok
+	}
+	return nil
+}
+
+// isLineDirective reports whether the provided token is a line directive. As
+// applied by the lexer, line directives must start at column 1.
+func isLineDirective(tok token.Token) bool {
+	if tok.Kind != token.Comment || tok.Col != 1 {
+		return false
+	}
+	_, _, _, ok := parseLineDirective(tok.Val)
+	return ok
+}
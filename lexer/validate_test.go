@@ -0,0 +1,106 @@
+package lexer
+
+import (
+	"testing"
+
+	"github.com/mewlang/go/token"
+)
+
+func TestValidate(t *testing.T) {
+	golden := []struct {
+		tokens []token.Token
+		err    string
+	}{
+		{tokens: nil, err: ""},
+		{
+			tokens: []token.Token{
+				{Kind: token.Package, Val: "package", Line: 1, Col: 1},
+				{Kind: token.Ident, Val: "p", Line: 1, Col: 9},
+				{Kind: token.Semicolon, Val: ";", Line: 1, Col: 10},
+			},
+			err: "",
+		},
+		// Lexically invalid tokens.
+		{
+			tokens: []token.Token{
+				{Kind: token.Rune | token.Invalid, Val: "'a", Line: 1, Col: 1},
+			},
+			err: "",
+		},
+		// Token type NONE.
+		{
+			tokens: []token.Token{
+				{Kind: token.Ident, Val: "a", Line: 1, Col: 1},
+				{Line: 1, Col: 2},
+			},
+			err: "lexer.Validate: token 1 has token type NONE",
+		},
+		// Invalid positions.
+		{
+			tokens: []token.Token{
+				{Kind: token.Ident, Val: "a", Line: 0, Col: 1},
+			},
+			err: "lexer.Validate: token 0 (a) has invalid position 0:1",
+		},
+		// Decreasing positions.
+		{
+			tokens: []token.Token{
+				{Kind: token.Ident, Val: "a", Line: 2, Col: 1},
+				{Kind: token.Ident, Val: "b", Line: 1, Col: 5},
+			},
+			err: "lexer.Validate: token 1 (b) at 1:5 precedes token 0 (a) at 2:1",
+		},
+		{
+			tokens: []token.Token{
+				{Kind: token.Ident, Val: "a", Line: 1, Col: 3},
+				{Kind: token.Ident, Val: "b", Line: 1, Col: 1},
+			},
+			err: "lexer.Validate: token 1 (b) at 1:1 precedes token 0 (a) at 1:3",
+		},
+		// Line directives, which must start at column 1.
+		{
+			tokens: []token.Token{
+				{Kind: token.Comment, Val: "//line a.go:1", Line: 7, Col: 1},
+				{Kind: token.Ident, Val: "a", Line: 1, Col: 1},
+			},
+			err: "",
+		},
+		{
+			tokens: []token.Token{
+				{Kind: token.Comment, Val: "//line a.go:1", Line: 7, Col: 3},
+				{Kind: token.Ident, Val: "a", Line: 1, Col: 1},
+			},
+			err: "lexer.Validate: token 1 (a) at 1:1 precedes token 0 (//line a.go:1) at 7:3",
+		},
+		// Tokens of different files.
+		{
+			tokens: []token.Token{
+				{Kind: token.Ident, Val: "a", Line: 7, Col: 1, Filename: "a.go"},
+				{Kind: token.Ident, Val: "b", Line: 1, Col: 1, Filename: "b.go"},
+			},
+			err: "",
+		},
+	}
+
+	for i, g := range golden {
+		err := Validate(g.tokens)
+		errstr := ""
+		if err != nil {
+			errstr = err.Error()
+		}
+		if errstr != g.err {
+			t.Errorf("i=%d: error mismatch; expected %q, got %q.", i, g.err, errstr)
+		}
+	}
+
+	// Output of the lexer, including a line directive which remaps positions to
+	// an earlier line.
+	const input = "package p\n\nvar x = 1 // x\n//line p.go:1\nvar y = \"y\"\n"
+	tokens, err := ParseFile("p.go", input)
+	if err != nil {
+		t.Fatalf("unexpected error; %v", err)
+	}
+	if err := Validate(tokens); err != nil {
+		t.Errorf("unexpected error; %v", err)
+	}
+}
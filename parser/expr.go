@@ -0,0 +1,352 @@
+package parser
+
+import (
+	"github.com/mewlang/go/ast"
+	"github.com/mewlang/go/token"
+	"github.com/mewlang/go/types"
+)
+
+// ParseExpr parses the provided tokens into an expression. A single trailing
+// semicolon, as automatically inserted by the lexer at the end of the input, is
+// permitted.
+func ParseExpr(tokens []token.Token) (ast.Expr, error) {
+	p := newParser(tokens)
+	x, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	p.accept(token.Semicolon)
+	if tok := p.peek(); tok.Kind != token.None {
+		return nil, errorf(tok, "expected end of expression, got %s", tok.Kind.Describe())
+	}
+	return x, nil
+}
+
+// parseExpr parses an expression.
+//
+//    Expression = UnaryExpr | Expression binary_op UnaryExpr .
+//
+// ref: http://golang.org/ref/spec#Operators
+func (p *parser) parseExpr() (ast.Expr, error) {
+	return p.parseBinaryExpr(1)
+}
+
+// parseBinaryExpr parses a binary expression whose operators have a precedence
+// of at least prec1. Binary operators of the same precedence associate from
+// left to right.
+func (p *parser) parseBinaryExpr(prec1 int) (ast.Expr, error) {
+	x, err := p.parseUnaryExpr()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		prec := op.Kind.Precedence()
+		if prec < prec1 {
+			return x, nil
+		}
+		p.next()
+		y, err := p.parseBinaryExpr(prec + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = ast.BinaryExpr{Left: x, Op: op, Right: y}
+	}
+}
+
+// parseUnaryExpr parses an unary expression.
+//
+//    UnaryExpr  = PrimaryExpr | unary_op UnaryExpr .
+//
+//    unary_op   = "+" | "-" | "!" | "^" | "*" | "&" | "<-" .
+//
+// ref: http://golang.org/ref/spec#Operators
+func (p *parser) parseUnaryExpr() (ast.Expr, error) {
+	switch op := p.peek(); op.Kind {
+	case token.Add, token.Sub, token.Not, token.Xor, token.Mul, token.And, token.Arrow:
+		p.next()
+		x, err := p.parseUnaryExpr()
+		if err != nil {
+			return nil, err
+		}
+		return ast.UnaryExpr{Op: op, Expr: x}, nil
+	}
+	return p.parsePrimaryExpr()
+}
+
+// parsePrimaryExpr parses a primary expression.
+//
+//    PrimaryExpr =
+//       Operand |
+//       PrimaryExpr Selector |
+//       PrimaryExpr Index |
+//       PrimaryExpr Slice |
+//       PrimaryExpr Call .
+//
+// A (possibly qualified) type name followed by a left brace starts a composite
+// literal; e.g. T{1, 2} or pkg.T{X: 1}.
+//
+// ref: http://golang.org/ref/spec#Primary_expressions
+func (p *parser) parsePrimaryExpr() (ast.PrimaryExpr, error) {
+	x, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek().Kind {
+		case token.Dot:
+			x, err = p.parseSelector(x)
+		case token.Lbrack:
+			x, err = p.parseIndexOrSlice(x)
+		case token.Lparen:
+			x, err = p.parseCall(x)
+		case token.Lbrace:
+			typ, ok := literalTypeName(x)
+			if !ok {
+				return x, nil
+			}
+			x, err = p.parseCompositeLit(typ)
+		default:
+			return x, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseOperand parses an operand.
+//
+//    Operand     = Literal | OperandName | "(" Expression ")" .
+//    Literal     = BasicLit | CompositeLit .
+//    BasicLit    = int_lit | float_lit | imaginary_lit | rune_lit | string_lit .
+//    OperandName = identifier .
+//
+// Composite literals of type names are parsed by parsePrimaryExpr, as the type
+// name is indistinguishable from an operand name until the left brace.
+//
+// ref: http://golang.org/ref/spec#Operands
+func (p *parser) parseOperand() (ast.PrimaryExpr, error) {
+	switch p.peek().Kind {
+	case token.Lbrack, token.Struct, token.Map:
+		// Composite literal of a type literal.
+		typ, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		return p.parseCompositeLit(typ)
+	}
+	tok := p.next()
+	switch tok.Kind {
+	case token.Ident:
+		return ast.OperandName(tok), nil
+	case token.Int, token.Float, token.Imag, token.Rune, token.String:
+		return ast.BasicLit(tok), nil
+	case token.Lparen:
+		x, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(token.Rparen); err != nil {
+			return nil, err
+		}
+		return ast.ParenExpr{Expr: x}, nil
+	}
+	return nil, errorf(tok, "expected operand, got %s", tok.Kind.Describe())
+}
+
+// literalTypeName returns the type name denoted by the provided operand name or
+// qualified identifier, and a boolean indicating success.
+func literalTypeName(x ast.PrimaryExpr) (types.Name, bool) {
+	switch x := x.(type) {
+	case ast.OperandName:
+		return types.Name{Name: token.Token(x)}, true
+	case ast.SelectorExpr:
+		if pkg, ok := x.Expr.(ast.OperandName); ok {
+			return types.Name{Pkg: token.Token(pkg), Name: x.Selector}, true
+		}
+	}
+	return types.Name{}, false
+}
+
+// parseCompositeLit parses the literal value of a composite literal of the
+// provided type.
+//
+//    CompositeLit  = LiteralType LiteralValue .
+//    LiteralType   = StructType | ArrayType | "[" "..." "]" ElementType |
+//                    SliceType | MapType | TypeName .
+//
+// ref: http://golang.org/ref/spec#Composite_literals
+func (p *parser) parseCompositeLit(typ types.Type) (ast.PrimaryExpr, error) {
+	vals, err := p.parseLiteralValue()
+	if err != nil {
+		return nil, err
+	}
+	return ast.CompositeLit{Type: typ, Vals: vals}, nil
+}
+
+// parseLiteralValue parses the brace-bound list of elements of a composite
+// literal. The value of an element may itself be a literal value, whose type is
+// elided; e.g. [][]int{{1}, {2}}, and so may a key; e.g.
+// map[[2]int]string{{1, 2}: "a"}. A key which consists of a single identifier
+// is recorded as a field name, as it is indistinguishable from an element index
+// without type information.
+//
+//    LiteralValue  = "{" [ ElementList [ "," ] ] "}" .
+//    ElementList   = Element { "," Element } .
+//    Element       = [ Key ":" ] Value .
+//    Key           = FieldName | ElementIndex | LiteralValue .
+//    FieldName     = identifier .
+//    ElementIndex  = Expression .
+//    Value         = Expression | LiteralValue .
+//
+// ref: http://golang.org/ref/spec#Composite_literals
+func (p *parser) parseLiteralValue() ([]ast.CompositeElement, error) {
+	if _, err := p.expect(token.Lbrace); err != nil {
+		return nil, err
+	}
+	var elems []ast.CompositeElement
+	for p.peek().Kind != token.Rbrace {
+		val, err := p.parseElementValue()
+		if err != nil {
+			return nil, err
+		}
+		var elem ast.CompositeElement
+		if p.accept(token.Colon) {
+			switch key := val.(type) {
+			case ast.OperandName:
+				elem.Key = token.Token(key)
+			case ast.Expr:
+				elem.Key = key
+			case []ast.CompositeElement:
+				elem.Key = key
+			}
+			if val, err = p.parseElementValue(); err != nil {
+				return nil, err
+			}
+		}
+		elem.Val = val
+		elems = append(elems, elem)
+		if !p.accept(token.Comma) {
+			break
+		}
+	}
+	if _, err := p.expect(token.Rbrace); err != nil {
+		return nil, err
+	}
+	return elems, nil
+}
+
+// parseElementValue parses the value of a composite literal element, and
+// returns an ast.Expr or an []ast.CompositeElement.
+//
+//    Value = Expression | LiteralValue .
+func (p *parser) parseElementValue() (interface{}, error) {
+	if p.peek().Kind == token.Lbrace {
+		return p.parseLiteralValue()
+	}
+	return p.parseExpr()
+}
+
+// parseSelector parses a selector of the primary expression x.
+//
+//    Selector = "." identifier .
+//
+// ref: http://golang.org/ref/spec#Selectors
+func (p *parser) parseSelector(x ast.PrimaryExpr) (ast.PrimaryExpr, error) {
+	if _, err := p.expect(token.Dot); err != nil {
+		return nil, err
+	}
+	sel, err := p.expect(token.Ident)
+	if err != nil {
+		return nil, err
+	}
+	return ast.SelectorExpr{Expr: x, Selector: sel}, nil
+}
+
+// parseIndexOrSlice parses an index or a slice of the primary expression x.
+//
+//    Index = "[" Expression "]" .
+//    Slice = "[" ( [ Expression ] ":" [ Expression ] ) |
+//                ( [ Expression ] ":" Expression ":" Expression )
+//            "]" .
+//
+// ref: http://golang.org/ref/spec#Index_expressions
+// ref: http://golang.org/ref/spec#Slice_expressions
+func (p *parser) parseIndexOrSlice(x ast.PrimaryExpr) (ast.PrimaryExpr, error) {
+	if _, err := p.expect(token.Lbrack); err != nil {
+		return nil, err
+	}
+	// Index expressions, and the colons separating them.
+	var index [3]ast.Expr
+	var colons [2]token.Token
+	ncolons := 0
+	if p.peek().Kind != token.Colon {
+		var err error
+		index[0], err = p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+	}
+	for ncolons < len(colons) && p.peek().Kind == token.Colon {
+		colons[ncolons] = p.next()
+		ncolons++
+		if kind := p.peek().Kind; kind != token.Colon && kind != token.Rbrack {
+			var err error
+			index[ncolons], err = p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	rbrack, err := p.expect(token.Rbrack)
+	if err != nil {
+		return nil, err
+	}
+
+	switch ncolons {
+	case 0:
+		// Index expression.
+		return ast.IndexExpr{Expr: x, Index: index[0]}, nil
+	case 2:
+		// Full slice expression; both the high and the max index are required.
+		if index[1] == nil {
+			return nil, errorf(colons[1], "middle index required in 3-index slice")
+		}
+		if index[2] == nil {
+			return nil, errorf(rbrack, "final index required in 3-index slice")
+		}
+	}
+	return ast.SliceExpr{Expr: x, Low: index[0], High: index[1], Cap: index[2]}, nil
+}
+
+// parseCall parses a function call or a method invocation of the primary
+// expression x.
+//
+//    Call          = "(" [ ArgumentList [ "," ] ] ")" .
+//    ArgumentList  = ExpressionList [ "..." ] .
+//
+// ref: http://golang.org/ref/spec#Calls
+func (p *parser) parseCall(x ast.PrimaryExpr) (ast.PrimaryExpr, error) {
+	if _, err := p.expect(token.Lparen); err != nil {
+		return nil, err
+	}
+	call := ast.CallExpr{Func: x}
+	for p.peek().Kind != token.Rparen && !call.HasEllipsis {
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		call.Args = append(call.Args, arg)
+		if p.accept(token.Ellipsis) {
+			call.HasEllipsis = true
+		}
+		if !p.accept(token.Comma) {
+			break
+		}
+	}
+	if _, err := p.expect(token.Rparen); err != nil {
+		return nil, err
+	}
+	return call, nil
+}
@@ -0,0 +1,386 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/mewlang/go/ast"
+	"github.com/mewlang/go/lexer"
+	"github.com/mewlang/go/token"
+	"github.com/mewlang/go/types"
+)
+
+func TestParseExpr(t *testing.T) {
+	var (
+		a   = token.Token{Kind: token.Ident, Val: "a", Line: 1, Col: 1, Offset: 0}
+		b5  = token.Token{Kind: token.Ident, Val: "b", Line: 1, Col: 5, Offset: 4}
+		c9  = token.Token{Kind: token.Ident, Val: "c", Line: 1, Col: 9, Offset: 8}
+		add = token.Token{Kind: token.Add, Val: "+", Line: 1, Col: 3, Offset: 2}
+	)
+	golden := []struct {
+		in   string
+		want ast.Expr
+	}{
+		// Operands.
+		{in: "a", want: ast.OperandName(a)},
+		{in: "42", want: ast.BasicLit{Kind: token.Int, Val: "42", Line: 1, Col: 1, Offset: 0}},
+		{in: `"foo"`, want: ast.BasicLit{Kind: token.String, Val: `"foo"`, Line: 1, Col: 1, Offset: 0}},
+		{in: "(a)", want: ast.ParenExpr{Expr: ast.OperandName{Kind: token.Ident, Val: "a", Line: 1, Col: 2, Offset: 1}}},
+
+		// Binary expressions; operators of the same precedence associate from
+		// left to right.
+		{
+			in: "a + b - c",
+			want: ast.BinaryExpr{
+				Left:  ast.BinaryExpr{Left: ast.OperandName(a), Op: add, Right: ast.OperandName(b5)},
+				Op:    token.Token{Kind: token.Sub, Val: "-", Line: 1, Col: 7, Offset: 6},
+				Right: ast.OperandName(c9),
+			},
+		},
+		// Multiplication binds tighter than addition.
+		{
+			in: "a + b * c",
+			want: ast.BinaryExpr{
+				Left: ast.OperandName(a),
+				Op:   add,
+				Right: ast.BinaryExpr{
+					Left:  ast.OperandName(b5),
+					Op:    token.Token{Kind: token.Mul, Val: "*", Line: 1, Col: 7, Offset: 6},
+					Right: ast.OperandName(c9),
+				},
+			},
+		},
+		{
+			in: "a || b && c",
+			want: ast.BinaryExpr{
+				Left: ast.OperandName(a),
+				Op:   token.Token{Kind: token.Lor, Val: "||", Line: 1, Col: 3, Offset: 2},
+				Right: ast.BinaryExpr{
+					Left:  ast.OperandName{Kind: token.Ident, Val: "b", Line: 1, Col: 6, Offset: 5},
+					Op:    token.Token{Kind: token.Land, Val: "&&", Line: 1, Col: 8, Offset: 7},
+					Right: ast.OperandName{Kind: token.Ident, Val: "c", Line: 1, Col: 11, Offset: 10},
+				},
+			},
+		},
+
+		// Unary expressions.
+		{
+			in: "-a * <-b",
+			want: ast.BinaryExpr{
+				Left: ast.UnaryExpr{
+					Op:   token.Token{Kind: token.Sub, Val: "-", Line: 1, Col: 1, Offset: 0},
+					Expr: ast.OperandName{Kind: token.Ident, Val: "a", Line: 1, Col: 2, Offset: 1},
+				},
+				Op: token.Token{Kind: token.Mul, Val: "*", Line: 1, Col: 4, Offset: 3},
+				Right: ast.UnaryExpr{
+					Op:   token.Token{Kind: token.Arrow, Val: "<-", Line: 1, Col: 6, Offset: 5},
+					Expr: ast.OperandName{Kind: token.Ident, Val: "b", Line: 1, Col: 8, Offset: 7},
+				},
+			},
+		},
+		{
+			in: "!*&a",
+			want: ast.UnaryExpr{
+				Op: token.Token{Kind: token.Not, Val: "!", Line: 1, Col: 1, Offset: 0},
+				Expr: ast.UnaryExpr{
+					Op: token.Token{Kind: token.Mul, Val: "*", Line: 1, Col: 2, Offset: 1},
+					Expr: ast.UnaryExpr{
+						Op:   token.Token{Kind: token.And, Val: "&", Line: 1, Col: 3, Offset: 2},
+						Expr: ast.OperandName{Kind: token.Ident, Val: "a", Line: 1, Col: 4, Offset: 3},
+					},
+				},
+			},
+		},
+
+		// Parenthesized expressions.
+		{
+			in: "(a + b) * c",
+			want: ast.BinaryExpr{
+				Left: ast.ParenExpr{
+					Expr: ast.BinaryExpr{
+						Left:  ast.OperandName{Kind: token.Ident, Val: "a", Line: 1, Col: 2, Offset: 1},
+						Op:    token.Token{Kind: token.Add, Val: "+", Line: 1, Col: 4, Offset: 3},
+						Right: ast.OperandName{Kind: token.Ident, Val: "b", Line: 1, Col: 6, Offset: 5},
+					},
+				},
+				Op:    token.Token{Kind: token.Mul, Val: "*", Line: 1, Col: 9, Offset: 8},
+				Right: ast.OperandName{Kind: token.Ident, Val: "c", Line: 1, Col: 11, Offset: 10},
+			},
+		},
+
+		// Selectors, index and slice expressions.
+		{
+			in: "a.b.c",
+			want: ast.SelectorExpr{
+				Expr: ast.SelectorExpr{
+					Expr:     ast.OperandName(a),
+					Selector: token.Token{Kind: token.Ident, Val: "b", Line: 1, Col: 3, Offset: 2},
+				},
+				Selector: token.Token{Kind: token.Ident, Val: "c", Line: 1, Col: 5, Offset: 4},
+			},
+		},
+		{
+			in: "a[b5]",
+			want: ast.IndexExpr{
+				Expr:  ast.OperandName(a),
+				Index: ast.OperandName{Kind: token.Ident, Val: "b5", Line: 1, Col: 3, Offset: 2},
+			},
+		},
+		{
+			in: "a[:]",
+			want: ast.SliceExpr{
+				Expr: ast.OperandName(a),
+			},
+		},
+		{
+			in: "a[1:b]",
+			want: ast.SliceExpr{
+				Expr: ast.OperandName(a),
+				Low:  ast.BasicLit{Kind: token.Int, Val: "1", Line: 1, Col: 3, Offset: 2},
+				High: ast.OperandName{Kind: token.Ident, Val: "b", Line: 1, Col: 5, Offset: 4},
+			},
+		},
+		{
+			in: "a[:b:c]",
+			want: ast.SliceExpr{
+				Expr: ast.OperandName(a),
+				High: ast.OperandName{Kind: token.Ident, Val: "b", Line: 1, Col: 4, Offset: 3},
+				Cap:  ast.OperandName{Kind: token.Ident, Val: "c", Line: 1, Col: 6, Offset: 5},
+			},
+		},
+
+		// Calls.
+		{
+			in: "f()",
+			want: ast.CallExpr{
+				Func: ast.OperandName{Kind: token.Ident, Val: "f", Line: 1, Col: 1, Offset: 0},
+			},
+		},
+		{
+			in: "f(a, b...)",
+			want: ast.CallExpr{
+				Func: ast.OperandName{Kind: token.Ident, Val: "f", Line: 1, Col: 1, Offset: 0},
+				Args: []interface{}{
+					ast.OperandName{Kind: token.Ident, Val: "a", Line: 1, Col: 3, Offset: 2},
+					ast.OperandName{Kind: token.Ident, Val: "b", Line: 1, Col: 6, Offset: 5},
+				},
+				HasEllipsis: true,
+			},
+		},
+		{
+			in: "x.f(1,)[0]",
+			want: ast.IndexExpr{
+				Expr: ast.CallExpr{
+					Func: ast.SelectorExpr{
+						Expr:     ast.OperandName{Kind: token.Ident, Val: "x", Line: 1, Col: 1, Offset: 0},
+						Selector: token.Token{Kind: token.Ident, Val: "f", Line: 1, Col: 3, Offset: 2},
+					},
+					Args: []interface{}{
+						ast.BasicLit{Kind: token.Int, Val: "1", Line: 1, Col: 5, Offset: 4},
+					},
+				},
+				Index: ast.BasicLit{Kind: token.Int, Val: "0", Line: 1, Col: 9, Offset: 8},
+			},
+		},
+	}
+
+	for i, g := range golden {
+		tokens, err := lexer.Parse(g.in)
+		if err != nil {
+			t.Errorf("i=%d: lexer.Parse failed; %v", i, err)
+			continue
+		}
+		got, err := ParseExpr(tokens)
+		if err != nil {
+			t.Errorf("i=%d: ParseExpr(%q) failed; %v", i, g.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: expression mismatch for %q; expected %#v, got %#v.", i, g.in, g.want, got)
+		}
+	}
+}
+
+func TestParseOperand(t *testing.T) {
+	golden := []struct {
+		in string
+		// Type name of the operand; BasicLit or OperandName.
+		typ  string
+		kind token.Kind
+	}{
+		{in: "a", typ: "OperandName", kind: token.Ident},
+		{in: "true", typ: "OperandName", kind: token.Ident},
+		{in: "iota", typ: "OperandName", kind: token.Ident},
+		{in: "42", typ: "BasicLit", kind: token.Int},
+		{in: "4.2", typ: "BasicLit", kind: token.Float},
+		{in: "4.2i", typ: "BasicLit", kind: token.Imag},
+		{in: "'a'", typ: "BasicLit", kind: token.Rune},
+		{in: `"a"`, typ: "BasicLit", kind: token.String},
+		{in: "`a`", typ: "BasicLit", kind: token.String},
+	}
+
+	for i, g := range golden {
+		tokens, err := lexer.Parse(g.in)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		x, err := ParseExpr(tokens)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		var typ string
+		var kind token.Kind
+		switch x := x.(type) {
+		case ast.BasicLit:
+			typ, kind = "BasicLit", x.Kind
+		case ast.OperandName:
+			typ, kind = "OperandName", x.Kind
+		default:
+			typ = fmt.Sprintf("%T", x)
+		}
+		if typ != g.typ || kind != g.kind {
+			t.Errorf("i=%d: operand mismatch for %q; expected %s of token type %v, got %s of token type %v.", i, g.in, g.typ, g.kind, typ, kind)
+		}
+	}
+}
+
+func TestParseCompositeLit(t *testing.T) {
+	golden := []struct {
+		in string
+		// Type of the composite literal.
+		typ string
+	}{
+		{in: "[]int{1, 2, 3}", typ: "types.Slice"},
+		{in: "[3]int{}", typ: "types.Array"},
+		{in: "[...]string{2: \"a\", \"b\"}", typ: "types.Array"},
+		{in: "map[string]int{\"a\": 1}", typ: "types.Map"},
+		{in: "struct{X int}{1}", typ: "types.Struct"},
+		{in: "Point{X: 1, Y: 2}", typ: "types.Name"},
+		{in: "image.Point{X: 1}", typ: "types.Name"},
+		{in: "[][]int{{1}, {2}, {}}", typ: "types.Slice"},
+		{in: "map[Point]string{Point{1, 2}: \"a\"}", typ: "types.Map"},
+		{in: "[]int{n + 1: f(x), len(s)}", typ: "types.Slice"},
+		{in: "map[[2]int]string{{1, 2}: \"a\"}", typ: "types.Map"},
+		{in: "map[Point]string{{1, 2}: \"a\", {X: 3}: \"b\"}", typ: "types.Map"},
+		{in: "[]map[[2]int]string{{{1, 2}: \"a\"}, {{3, 4}: \"b\"}}", typ: "types.Slice"},
+	}
+
+	for i, g := range golden {
+		tokens, err := lexer.Parse(g.in)
+		if err != nil {
+			t.Errorf("i=%d: lexer.Parse failed; %v", i, err)
+			continue
+		}
+		x, err := ParseExpr(tokens)
+		if err != nil {
+			t.Errorf("i=%d: ParseExpr(%q) failed; %v", i, g.in, err)
+			continue
+		}
+		lit, ok := x.(ast.CompositeLit)
+		if !ok {
+			t.Errorf("i=%d: expression type mismatch for %q; expected ast.CompositeLit, got %T.", i, g.in, x)
+			continue
+		}
+		if typ := fmt.Sprintf("%T", lit.Type); typ != g.typ {
+			t.Errorf("i=%d: literal type mismatch for %q; expected %s, got %s.", i, g.in, g.typ, typ)
+		}
+		buf := new(bytes.Buffer)
+		if err := ast.Fprint(buf, x); err != nil {
+			t.Errorf("i=%d: ast.Fprint failed; %v", i, err)
+			continue
+		}
+		if got := buf.String(); got != g.in {
+			t.Errorf("i=%d: output mismatch; expected %q, got %q.", i, g.in, got)
+		}
+	}
+
+	// Keys consisting of a single identifier are recorded as field names, other
+	// keys as expressions; elided types are recorded as nested elements.
+	tokens, err := lexer.Parse("T{X: 1, x + 1: {2}}")
+	if err != nil {
+		t.Fatalf("lexer.Parse failed; %v", err)
+	}
+	x, err := ParseExpr(tokens)
+	if err != nil {
+		t.Fatalf("ParseExpr failed; %v", err)
+	}
+	want := ast.CompositeLit{
+		Type: types.Name{Name: token.Token{Kind: token.Ident, Val: "T", Line: 1, Col: 1, Offset: 0}},
+		Vals: []ast.CompositeElement{
+			{
+				Key: token.Token{Kind: token.Ident, Val: "X", Line: 1, Col: 3, Offset: 2},
+				Val: ast.BasicLit{Kind: token.Int, Val: "1", Line: 1, Col: 6, Offset: 5},
+			},
+			{
+				Key: ast.BinaryExpr{
+					Left:  ast.OperandName{Kind: token.Ident, Val: "x", Line: 1, Col: 9, Offset: 8},
+					Op:    token.Token{Kind: token.Add, Val: "+", Line: 1, Col: 11, Offset: 10},
+					Right: ast.BasicLit{Kind: token.Int, Val: "1", Line: 1, Col: 13, Offset: 12},
+				},
+				Val: []ast.CompositeElement{
+					{Val: ast.BasicLit{Kind: token.Int, Val: "2", Line: 1, Col: 17, Offset: 16}},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(x, want) {
+		t.Errorf("composite literal mismatch; expected %#v, got %#v.", want, x)
+	}
+}
+
+func TestParseExprErrors(t *testing.T) {
+	golden := []struct {
+		in  string
+		err string
+	}{
+		{in: "", err: `1:1: expected operand, got EOF`},
+		{in: "a +", err: `1:4: expected operand, got EOF`},
+		{in: "(a + b", err: `1:7: expected ')', got ';'`},
+		{in: "a.(b)", err: `1:3: expected an identifier, got '('`},
+		{in: "a[]", err: `1:3: expected operand, got ']'`},
+		{in: "a[1:2:]", err: `1:7: final index required in 3-index slice`},
+		{in: "a[1::3]", err: `1:5: middle index required in 3-index slice`},
+		{in: "f(a..., b)", err: `1:9: expected ')', got an identifier`},
+		{in: "a b", err: `1:3: expected end of expression, got an identifier`},
+		{in: "[]int{1 2}", err: `1:9: expected '}', got an int literal`},
+		{in: "[]int", err: `1:6: expected '{', got ';'`},
+	}
+
+	for i, g := range golden {
+		tokens, _ := lexer.Parse(g.in)
+		_, err := ParseExpr(tokens)
+		errstr := ""
+		if err != nil {
+			errstr = err.Error()
+		}
+		if errstr != g.err {
+			t.Errorf("i=%d: error mismatch for %q; expected %v, got %v.", i, g.in, g.err, errstr)
+		}
+	}
+}
+
+func TestSyntaxError(t *testing.T) {
+	tokens, err := lexer.Parse("f(a,\n\tb")
+	if err != nil {
+		t.Fatalf("lexer.Parse failed; %v", err)
+	}
+	_, err = ParseExpr(tokens)
+	e, ok := err.(*SyntaxError)
+	if !ok {
+		t.Fatalf("error type mismatch; expected *SyntaxError, got %T (%v).", err, err)
+	}
+	// Missing closing parenthesis; the semicolon is inserted after b.
+	if want := (token.Position{Offset: 7, Line: 2, Col: 3}); e.Pos != want {
+		t.Errorf("position mismatch; expected %v, got %v.", want, e.Pos)
+	}
+	if want := `expected ')', got ';'`; e.Msg != want {
+		t.Errorf("message mismatch; expected %q, got %q.", want, e.Msg)
+	}
+	if want := `2:3: expected ')', got ';'`; e.Error() != want {
+		t.Errorf("error mismatch; expected %q, got %q.", want, e.Error())
+	}
+}
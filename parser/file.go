@@ -0,0 +1,376 @@
+package parser
+
+import (
+	"github.com/mewlang/go/ast"
+	"github.com/mewlang/go/token"
+	"github.com/mewlang/go/types"
+)
+
+// ParseFile parses the provided tokens of a source file into a file node.
+//
+//    SourceFile    = PackageClause ";" { ImportDecl ";" } { TopLevelDecl ";" } .
+//
+//    PackageClause = "package" PackageName .
+//    PackageName   = identifier .
+//
+// ref: http://golang.org/ref/spec#Source_file_organization
+func ParseFile(tokens []token.Token) (*ast.File, error) {
+	p := newParser(tokens)
+	f := new(ast.File)
+
+	// Package clause.
+	if _, err := p.expect(token.Package); err != nil {
+		return nil, err
+	}
+	name, err := p.expect(token.Ident)
+	if err != nil {
+		return nil, err
+	}
+	f.PkgName = name
+	if _, err := p.expect(token.Semicolon); err != nil {
+		return nil, err
+	}
+
+	// Import declarations.
+	for p.peek().Kind == token.Import {
+		imp, err := p.parseImportDecl()
+		if err != nil {
+			return nil, err
+		}
+		f.Imps = append(f.Imps, imp)
+		if _, err := p.expect(token.Semicolon); err != nil {
+			return nil, err
+		}
+	}
+
+	// Top level declarations.
+	for p.peek().Kind != token.None {
+		decl, err := p.parseTopLevelDecl()
+		if err != nil {
+			return nil, err
+		}
+		f.Decls = append(f.Decls, decl)
+		if _, err := p.expect(token.Semicolon); err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+// parseGroup parses either a single specifier or a parenthesized group of zero
+// or more specifiers separated by semicolons. The provided function is invoked
+// to parse each specifier.
+func (p *parser) parseGroup(parseSpec func() error) error {
+	if !p.accept(token.Lparen) {
+		return parseSpec()
+	}
+	for kind := p.peek().Kind; kind != token.Rparen && kind != token.None; kind = p.peek().Kind {
+		if err := parseSpec(); err != nil {
+			return err
+		}
+		if p.peek().Kind != token.Rparen {
+			if _, err := p.expect(token.Semicolon); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := p.expect(token.Rparen)
+	return err
+}
+
+// parseImportDecl parses an import declaration.
+//
+//    ImportDecl = "import" ( ImportSpec | "(" { ImportSpec ";" } ")" ) .
+//    ImportSpec = [ "." | PackageName ] ImportPath .
+//    ImportPath = string_lit .
+//
+// ref: http://golang.org/ref/spec#Import_declarations
+func (p *parser) parseImportDecl() (ast.ImportDecl, error) {
+	if _, err := p.expect(token.Import); err != nil {
+		return nil, err
+	}
+	decl := ast.ImportDecl{}
+	err := p.parseGroup(func() error {
+		var spec ast.ImportSpec
+		if kind := p.peek().Kind; kind == token.Dot || kind == token.Ident {
+			spec.Name = p.next()
+		}
+		path, err := p.expect(token.String)
+		if err != nil {
+			return err
+		}
+		spec.Path = path
+		decl = append(decl, spec)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decl, nil
+}
+
+// parseTopLevelDecl parses a top level declaration.
+//
+//    TopLevelDecl = Declaration | FunctionDecl | MethodDecl .
+//    Declaration  = ConstDecl | TypeDecl | VarDecl .
+//
+// ref: http://golang.org/ref/spec#Declarations_and_scope
+func (p *parser) parseTopLevelDecl() (ast.TopLevelDecl, error) {
+	switch tok := p.peek(); tok.Kind {
+	case token.Const:
+		return p.parseConstDecl()
+	case token.Type:
+		return p.parseTypeDecl()
+	case token.Var:
+		return p.parseVarDecl()
+	case token.Func:
+		return p.parseFuncOrMethodDecl()
+	default:
+		return nil, errorf(tok, "expected declaration, got %s", tok.Kind.Describe())
+	}
+}
+
+// parseConstDecl parses a constant declaration. A constant specifier without a
+// type and value expressions implicitly repeats the type and value expressions
+// of the previous specifier, and each specifier records its value of iota.
+//
+//    ConstDecl      = "const" ( ConstSpec | "(" { ConstSpec ";" } ")" ) .
+//    ConstSpec      = IdentifierList [ [ Type ] "=" ExpressionList ] .
+//
+// ref: http://golang.org/ref/spec#Constant_declarations
+// ref: http://golang.org/ref/spec#Iota
+func (p *parser) parseConstDecl() (ast.ConstDecl, error) {
+	if _, err := p.expect(token.Const); err != nil {
+		return nil, err
+	}
+	decl := ast.ConstDecl{}
+	err := p.parseGroup(func() error {
+		spec, err := p.parseValueSpec()
+		if err != nil {
+			return err
+		}
+		spec.Iota = len(decl)
+		if spec.Vals == nil {
+			if spec.Type != nil || len(decl) == 0 {
+				return errorf(spec.Names[0], "missing constant value")
+			}
+			prev := decl[len(decl)-1]
+			spec.Type, spec.Vals = prev.Type, prev.Vals
+			spec.Implicit = true
+		}
+		decl = append(decl, spec)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decl, nil
+}
+
+// parseVarDecl parses a variable declaration.
+//
+//    VarDecl = "var" ( VarSpec | "(" { VarSpec ";" } ")" ) .
+//    VarSpec = IdentifierList ( Type [ "=" ExpressionList ] | "=" ExpressionList ) .
+//
+// ref: http://golang.org/ref/spec#Variable_declarations
+func (p *parser) parseVarDecl() (ast.VarDecl, error) {
+	if _, err := p.expect(token.Var); err != nil {
+		return nil, err
+	}
+	decl := ast.VarDecl{}
+	err := p.parseGroup(func() error {
+		spec, err := p.parseValueSpec()
+		if err != nil {
+			return err
+		}
+		if spec.Type == nil && spec.Vals == nil {
+			return errorf(spec.Names[0], "missing variable type or initialization")
+		}
+		decl = append(decl, spec)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decl, nil
+}
+
+// parseValueSpec parses a constant or variable specifier.
+//
+//    ValueSpec      = IdentifierList [ [ Type ] "=" ExpressionList ] .
+//    IdentifierList = identifier { "," identifier } .
+//    ExpressionList = Expression { "," Expression } .
+func (p *parser) parseValueSpec() (ast.ValueSpec, error) {
+	var spec ast.ValueSpec
+	names, err := p.parseIdentList()
+	if err != nil {
+		return ast.ValueSpec{}, err
+	}
+	spec.Names = names
+	if isTypeStart(p.peek().Kind) {
+		spec.Type, err = p.parseType()
+		if err != nil {
+			return ast.ValueSpec{}, err
+		}
+	}
+	if p.accept(token.Assign) {
+		spec.Vals, err = p.parseExprList()
+		if err != nil {
+			return ast.ValueSpec{}, err
+		}
+	}
+	return spec, nil
+}
+
+// parseIdentList parses a list of identifiers.
+//
+//    IdentifierList = identifier { "," identifier } .
+func (p *parser) parseIdentList() ([]token.Token, error) {
+	var names []token.Token
+	for {
+		name, err := p.expect(token.Ident)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !p.accept(token.Comma) {
+			return names, nil
+		}
+	}
+}
+
+// parseExprList parses a list of expressions.
+//
+//    ExpressionList = Expression { "," Expression } .
+func (p *parser) parseExprList() ([]ast.Expr, error) {
+	var list []ast.Expr
+	for {
+		x, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, x)
+		if !p.accept(token.Comma) {
+			return list, nil
+		}
+	}
+}
+
+// parseTypeDecl parses a type declaration.
+//
+//    TypeDecl = "type" ( TypeSpec | "(" { TypeSpec ";" } ")" ) .
+//    TypeSpec = identifier Type .
+//
+// ref: http://golang.org/ref/spec#Type_declarations
+func (p *parser) parseTypeDecl() (ast.TypeDecl, error) {
+	if _, err := p.expect(token.Type); err != nil {
+		return nil, err
+	}
+	decl := ast.TypeDecl{}
+	err := p.parseGroup(func() error {
+		name, err := p.expect(token.Ident)
+		if err != nil {
+			return err
+		}
+		typ, err := p.parseType()
+		if err != nil {
+			return err
+		}
+		decl = append(decl, types.Name{Name: name, Type: typ})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decl, nil
+}
+
+// parseFuncOrMethodDecl parses a function declaration or a method declaration.
+//
+//    FunctionDecl = "func" FunctionName ( Function | Signature ) .
+//    FunctionName = identifier .
+//    Function     = Signature FunctionBody .
+//    FunctionBody = Block .
+//
+//    MethodDecl   = "func" Receiver MethodName ( Function | Signature ) .
+//    Receiver     = Parameters .
+//
+// ref: http://golang.org/ref/spec#Function_declarations
+// ref: http://golang.org/ref/spec#Method_declarations
+func (p *parser) parseFuncOrMethodDecl() (ast.TopLevelDecl, error) {
+	fn, err := p.expect(token.Func)
+	if err != nil {
+		return nil, err
+	}
+
+	// Receiver.
+	var recv []types.Parameter
+	isMethod := p.peek().Kind == token.Lparen
+	if isMethod {
+		var ellipsis token.Token
+		recv, ellipsis, err = p.parseParameters()
+		if err != nil {
+			return nil, err
+		}
+		if ellipsis.Kind != token.None {
+			return nil, errorf(ellipsis, "cannot use ... in receiver")
+		}
+		switch {
+		case len(recv) == 0:
+			return nil, errorf(fn, "method has no receiver")
+		case len(recv) > 1 || len(recv[0].Names) > 1:
+			return nil, errorf(fn, "method has multiple receivers")
+		}
+	}
+
+	name, err := p.expect(token.Ident)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := p.parseSignature()
+	if err != nil {
+		return nil, err
+	}
+	var body ast.Block
+	if p.peek().Kind == token.Lbrace {
+		body, err = p.parseBody()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if isMethod {
+		return ast.MethodDecl{Receiver: recv[0], Name: name, Sig: sig, Body: body}, nil
+	}
+	return ast.FuncDecl{Name: name, Sig: sig, Body: body}, nil
+}
+
+// parseBody parses a function body.
+//
+//    FunctionBody = Block .
+//    Block        = "{" StatementList "}" .
+//
+// TODO(u): Parse the statement list of the function body. For now, the tokens
+// of a non-empty body are recorded by a single ast.BadStmt.
+func (p *parser) parseBody() (ast.Block, error) {
+	if _, err := p.expect(token.Lbrace); err != nil {
+		return nil, err
+	}
+	start := p.pos
+	for depth := 1; depth > 0; {
+		switch tok := p.next(); tok.Kind {
+		case token.Lbrace:
+			depth++
+		case token.Rbrace:
+			depth--
+		case token.None:
+			return nil, errorf(tok, "expected %s, got %s", token.Rbrace.Describe(), tok.Kind.Describe())
+		}
+	}
+	body := ast.Block{}
+	if end := p.pos - 1; end > start {
+		body = append(body, ast.BadStmt{Tokens: p.tokens[start:end]})
+	}
+	return body, nil
+}
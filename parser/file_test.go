@@ -0,0 +1,429 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mewlang/go/ast"
+	"github.com/mewlang/go/lexer"
+	"github.com/mewlang/go/token"
+	"github.com/mewlang/go/types"
+)
+
+func TestParseFile(t *testing.T) {
+	const input = `// Package p implements …
+package p
+
+import "fmt"
+
+import (
+	"io"
+	str "strings"
+	. "unicode"
+)
+
+const A = 1
+
+const (
+	B, C int = 2, 3
+	D
+)
+
+var x, y = f(1), B + C
+
+type (
+	T struct {
+		X, Y int "tag"
+		*Z
+		io.Reader
+	}
+	I interface {
+		io.Writer
+		M(a, b int, c ...string) (n int, err error)
+		T
+	}
+)
+
+type F func(int, string) error
+
+type M map[string][]chan<- int
+
+func main() {
+	if x {
+		fmt.Println(str.ToUpper("foo"))
+	}
+}
+
+func (t *T) String() string
+
+func (I) N(<-chan bool) bool { return true }
+`
+	tokens, err := lexer.Parse(input)
+	if err != nil {
+		t.Fatalf("lexer.Parse failed; %v", err)
+	}
+	f, err := ParseFile(tokens)
+	if err != nil {
+		t.Fatalf("ParseFile failed; %v", err)
+	}
+
+	// Package clause.
+	if want := (token.Token{Kind: token.Ident, Val: "p", Line: 2, Col: 9, Offset: 36}); f.PkgName != want {
+		t.Errorf("package name mismatch; expected %#v, got %#v.", want, f.PkgName)
+	}
+
+	// Import declarations.
+	wantImps := []ast.ImportDecl{
+		{
+			{Path: token.Token{Kind: token.String, Val: `"fmt"`, Line: 4, Col: 8, Offset: 46}},
+		},
+		{
+			{Path: token.Token{Kind: token.String, Val: `"io"`, Line: 7, Col: 2, Offset: 63}},
+			{Name: token.Token{Kind: token.Ident, Val: "str", Line: 8, Col: 2, Offset: 69}, Path: token.Token{Kind: token.String, Val: `"strings"`, Line: 8, Col: 6, Offset: 73}},
+			{Name: token.Token{Kind: token.Dot, Val: ".", Line: 9, Col: 2, Offset: 84}, Path: token.Token{Kind: token.String, Val: `"unicode"`, Line: 9, Col: 4, Offset: 86}},
+		},
+	}
+	if !reflect.DeepEqual(f.Imps, wantImps) {
+		t.Errorf("import declarations mismatch; expected %#v, got %#v.", wantImps, f.Imps)
+	}
+
+	// Top level declarations.
+	if len(f.Decls) != 9 {
+		t.Fatalf("top level declaration count mismatch; expected 9, got %d.", len(f.Decls))
+	}
+	ident := func(val string, line, col, offset int) token.Token {
+		return token.Token{Kind: token.Ident, Val: val, Line: line, Col: col, Offset: offset}
+	}
+	tok := func(kind token.Kind, val string, line, col, offset int) token.Token {
+		return token.Token{Kind: kind, Val: val, Line: line, Col: col, Offset: offset}
+	}
+	want := []ast.TopLevelDecl{
+		ast.ConstDecl{
+			{Names: []token.Token{ident("A", 12, 7, 105)}, Vals: []ast.Expr{ast.BasicLit{Kind: token.Int, Val: "1", Line: 12, Col: 11, Offset: 109}}},
+		},
+		ast.ConstDecl{
+			{
+				Names: []token.Token{ident("B", 15, 2, 121), ident("C", 15, 5, 124)},
+				Type:  types.Name{Name: ident("int", 15, 7, 126)},
+				Vals: []ast.Expr{
+					ast.BasicLit{Kind: token.Int, Val: "2", Line: 15, Col: 13, Offset: 132},
+					ast.BasicLit{Kind: token.Int, Val: "3", Line: 15, Col: 16, Offset: 135},
+				},
+			},
+			// Implicit repetition of the previous specifier.
+			{
+				Names: []token.Token{ident("D", 16, 2, 138)},
+				Type:  types.Name{Name: ident("int", 15, 7, 126)},
+				Vals: []ast.Expr{
+					ast.BasicLit{Kind: token.Int, Val: "2", Line: 15, Col: 13, Offset: 132},
+					ast.BasicLit{Kind: token.Int, Val: "3", Line: 15, Col: 16, Offset: 135},
+				},
+				Iota:     1,
+				Implicit: true,
+			},
+		},
+		ast.VarDecl{
+			{
+				Names: []token.Token{ident("x", 19, 5, 147), ident("y", 19, 8, 150)},
+				Vals: []ast.Expr{
+					ast.CallExpr{
+						Func: ast.OperandName(ident("f", 19, 12, 154)),
+						Args: []interface{}{ast.BasicLit{Kind: token.Int, Val: "1", Line: 19, Col: 14, Offset: 156}},
+					},
+					ast.BinaryExpr{
+						Left:  ast.OperandName(ident("B", 19, 18, 160)),
+						Op:    token.Token{Kind: token.Add, Val: "+", Line: 19, Col: 20, Offset: 162},
+						Right: ast.OperandName(ident("C", 19, 22, 164)),
+					},
+				},
+			},
+		},
+		ast.TypeDecl{
+			{
+				Name: ident("T", 22, 2, 175),
+				Type: types.Struct{
+					{
+						Names: []token.Token{ident("X", 23, 3, 188), ident("Y", 23, 6, 191)},
+						Type:  types.Name{Name: ident("int", 23, 8, 193)},
+						Tag:   token.Token{Kind: token.String, Val: `"tag"`, Line: 23, Col: 12, Offset: 197},
+					},
+					{Type: types.Pointer{Base: types.Name{Name: ident("Z", 24, 4, 206)}}},
+					{Type: types.Name{Pkg: ident("io", 25, 3, 210), Name: ident("Reader", 25, 6, 213)}},
+				},
+			},
+			{
+				Name: ident("I", 27, 2, 224),
+				Type: types.Interface{
+					{Pkg: ident("io", 28, 3, 240), Name: ident("Writer", 28, 6, 243)},
+					{
+						Name: ident("M", 29, 3, 252),
+						Sig: &types.Func{
+							Params: []types.Parameter{
+								{Names: []token.Token{ident("a", 29, 5, 254), ident("b", 29, 8, 257)}, Type: types.Name{Name: ident("int", 29, 10, 259)}},
+								{Names: []token.Token{ident("c", 29, 15, 264)}, Type: types.Name{Name: ident("string", 29, 20, 269)}},
+							},
+							Results: []types.Parameter{
+								{Names: []token.Token{ident("n", 29, 29, 278)}, Type: types.Name{Name: ident("int", 29, 31, 280)}},
+								{Names: []token.Token{ident("err", 29, 36, 285)}, Type: types.Name{Name: ident("error", 29, 40, 289)}},
+							},
+							IsVariadic: true,
+						},
+					},
+					{Name: ident("T", 30, 3, 298)},
+				},
+			},
+		},
+		ast.TypeDecl{
+			{
+				Name: ident("F", 34, 6, 311),
+				Type: types.Func{
+					Params: []types.Parameter{
+						{Type: types.Name{Name: ident("int", 34, 13, 318)}},
+						{Type: types.Name{Name: ident("string", 34, 18, 323)}},
+					},
+					Results: []types.Parameter{
+						{Type: types.Name{Name: ident("error", 34, 26, 331)}},
+					},
+				},
+			},
+		},
+		ast.TypeDecl{
+			{
+				Name: ident("M", 36, 6, 343),
+				Type: types.Map{
+					Key: types.Name{Name: ident("string", 36, 12, 349)},
+					Elem: types.Slice{
+						Elem: types.Chan{Dir: types.Send, Elem: types.Name{Name: ident("int", 36, 28, 365)}},
+					},
+				},
+			},
+		},
+		ast.FuncDecl{
+			Name: ident("main", 38, 6, 375),
+			Body: ast.Block{
+				ast.BadStmt{Tokens: []token.Token{
+					tok(token.If, "if", 39, 2, 385), ident("x", 39, 5, 388), tok(token.Lbrace, "{", 39, 7, 390),
+					ident("fmt", 40, 3, 394), tok(token.Dot, ".", 40, 6, 397), ident("Println", 40, 7, 398), tok(token.Lparen, "(", 40, 14, 405),
+					ident("str", 40, 15, 406), tok(token.Dot, ".", 40, 18, 409), ident("ToUpper", 40, 19, 410), tok(token.Lparen, "(", 40, 26, 417),
+					tok(token.String, `"foo"`, 40, 27, 418), tok(token.Rparen, ")", 40, 32, 423), tok(token.Rparen, ")", 40, 33, 424), tok(token.Semicolon, ";", 40, 34, 425),
+					tok(token.Rbrace, "}", 41, 2, 427), tok(token.Semicolon, ";", 41, 3, 428),
+				}},
+			},
+		},
+		ast.MethodDecl{
+			Receiver: types.Parameter{
+				Names: []token.Token{ident("t", 44, 7, 438)},
+				Type:  types.Pointer{Base: types.Name{Name: ident("T", 44, 10, 441)}},
+			},
+			Name: ident("String", 44, 13, 444),
+			Sig: types.Func{
+				Results: []types.Parameter{{Type: types.Name{Name: ident("string", 44, 22, 453)}}},
+			},
+		},
+		ast.MethodDecl{
+			Receiver: types.Parameter{Type: types.Name{Name: ident("I", 46, 7, 467)}},
+			Name:     ident("N", 46, 10, 470),
+			Sig: types.Func{
+				Params: []types.Parameter{
+					{Type: types.Chan{Dir: types.Recv, Elem: types.Name{Name: ident("bool", 46, 19, 479)}}},
+				},
+				Results: []types.Parameter{{Type: types.Name{Name: ident("bool", 46, 25, 485)}}},
+			},
+			Body: ast.Block{
+				ast.BadStmt{Tokens: []token.Token{tok(token.Return, "return", 46, 32, 492), ident("true", 46, 39, 499)}},
+			},
+		},
+	}
+	for i := range want {
+		got := f.Decls[i]
+		if !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("i=%d: declaration mismatch; expected %#v, got %#v.", i, want[i], got)
+		}
+	}
+}
+
+func TestParseFileErrors(t *testing.T) {
+	golden := []struct {
+		in  string
+		err string
+	}{
+		{in: "", err: `1:1: expected keyword 'package', got EOF`},
+		{in: "package", err: `1:8: expected an identifier, got EOF`},
+		{in: "package p; import fmt", err: `1:22: expected a string literal, got ';'`},
+		{in: "package p\nimport (\n\t\"fmt\"\n", err: `3:8: expected ')', got EOF`},
+		{in: "package p\nx := 1", err: `2:1: expected declaration, got an identifier`},
+		{in: "package p\nvar x", err: `2:5: missing variable type or initialization`},
+		{in: "package p\nconst x", err: `2:7: missing constant value`},
+		{in: "package p\nconst (\n\tx int\n)", err: `3:2: missing constant value`},
+		{in: "package p\nconst (\n\tx\n\ty = 1\n)", err: `3:2: missing constant value`},
+		{in: "package p\ntype T struct { X int", err: `2:23: expected '}', got EOF`},
+		{in: "package p\nfunc (a, b T) f()", err: `2:1: method has multiple receivers`},
+		{in: "package p\nfunc () f()", err: `2:1: method has no receiver`},
+		{in: "package p\nfunc f(a int, string)", err: `2:21: mixed named and unnamed parameters`},
+		{in: "package p\nfunc f() {", err: `2:11: expected '}', got EOF`},
+		{in: "package p\nfunc f(a ...int, b int)", err: `2:10: can only use ... with final parameter`},
+		{in: "package p\nfunc f(...int, ...string)", err: `2:8: can only use ... with final parameter`},
+		{in: "package p\nfunc f(a, b ...int)", err: `2:13: can only use ... with final parameter`},
+		{in: "package p\nfunc f() (a ...int)", err: `2:13: cannot use ... in result parameter list`},
+		{in: "package p\nfunc (t ...T) f()", err: `2:9: cannot use ... in receiver`},
+	}
+
+	for i, g := range golden {
+		tokens, _ := lexer.Parse(g.in)
+		_, err := ParseFile(tokens)
+		errstr := ""
+		if err != nil {
+			errstr = err.Error()
+		}
+		if errstr != g.err {
+			t.Errorf("i=%d: error mismatch for %q; expected %v, got %v.", i, g.in, g.err, errstr)
+		}
+	}
+}
+
+func TestParseConstDeclIota(t *testing.T) {
+	const input = `package p
+
+const (
+	FooA T = 1<<iota /* bitfield … */ + 0x10   /* Foo start value */
+	FooB                                       /* FooB specifies … */
+	FooC                                       /* FooC specifies … */
+	BarA T = 1<<iota /* bitfield … */ + 0x100  /* Bar start value */
+	BarB                                       /* BarB specifies … */
+	BarC                                       /* BarC specifies … */
+)
+`
+	tokens, err := lexer.Parse(input)
+	if err != nil {
+		t.Fatalf("lexer.Parse failed; %v", err)
+	}
+	f, err := ParseFile(tokens)
+	if err != nil {
+		t.Fatalf("ParseFile failed; %v", err)
+	}
+	if len(f.Decls) != 1 {
+		t.Fatalf("top level declaration count mismatch; expected 1, got %d.", len(f.Decls))
+	}
+	decl, ok := f.Decls[0].(ast.ConstDecl)
+	if !ok {
+		t.Fatalf("declaration type mismatch; expected ast.ConstDecl, got %T.", f.Decls[0])
+	}
+
+	ident := func(val string, line, col, offset int) token.Token {
+		return token.Token{Kind: token.Ident, Val: val, Line: line, Col: col, Offset: offset}
+	}
+	// 1<<iota + start, at the given line starting at the given byte offset. The
+	// ellipsis (…) preceding the + operator occupies 3 bytes.
+	bitfield := func(line, lineStart int, start string) []ast.Expr {
+		return []ast.Expr{
+			ast.BinaryExpr{
+				Left: ast.BinaryExpr{
+					Left:  ast.BasicLit{Kind: token.Int, Val: "1", Line: line, Col: 11, Offset: lineStart + 10},
+					Op:    token.Token{Kind: token.Shl, Val: "<<", Line: line, Col: 12, Offset: lineStart + 11},
+					Right: ast.OperandName(ident("iota", line, 14, lineStart+13)),
+				},
+				Op:    token.Token{Kind: token.Add, Val: "+", Line: line, Col: 36, Offset: lineStart + 37},
+				Right: ast.BasicLit{Kind: token.Int, Val: start, Line: line, Col: 38, Offset: lineStart + 39},
+			},
+		}
+	}
+	typ := func(line, lineStart int) types.Type {
+		return types.Name{Name: ident("T", line, 7, lineStart+6)}
+	}
+	golden := []struct {
+		name     string
+		typ      types.Type
+		vals     []ast.Expr
+		iota     int
+		implicit bool
+	}{
+		{name: "FooA", typ: typ(4, 19), vals: bitfield(4, 19, "0x10"), iota: 0},
+		{name: "FooB", typ: typ(4, 19), vals: bitfield(4, 19, "0x10"), iota: 1, implicit: true},
+		{name: "FooC", typ: typ(4, 19), vals: bitfield(4, 19, "0x10"), iota: 2, implicit: true},
+		{name: "BarA", typ: typ(7, 225), vals: bitfield(7, 225, "0x100"), iota: 3},
+		{name: "BarB", typ: typ(7, 225), vals: bitfield(7, 225, "0x100"), iota: 4, implicit: true},
+		{name: "BarC", typ: typ(7, 225), vals: bitfield(7, 225, "0x100"), iota: 5, implicit: true},
+	}
+	if len(decl) != len(golden) {
+		t.Fatalf("constant specifier count mismatch; expected %d, got %d.", len(golden), len(decl))
+	}
+
+	for i, g := range golden {
+		spec := decl[i]
+		if len(spec.Names) != 1 || spec.Names[0].Val != g.name {
+			t.Errorf("i=%d: names mismatch; expected [%s], got %v.", i, g.name, spec.Names)
+		}
+		if !reflect.DeepEqual(spec.Type, g.typ) {
+			t.Errorf("i=%d: type mismatch; expected %#v, got %#v.", i, g.typ, spec.Type)
+		}
+		if !reflect.DeepEqual(spec.Vals, g.vals) {
+			t.Errorf("i=%d: values mismatch; expected %#v, got %#v.", i, g.vals, spec.Vals)
+		}
+		if spec.Iota != g.iota {
+			t.Errorf("i=%d: iota mismatch; expected %d, got %d.", i, g.iota, spec.Iota)
+		}
+		if spec.Implicit != g.implicit {
+			t.Errorf("i=%d: implicit mismatch; expected %v, got %v.", i, g.implicit, spec.Implicit)
+		}
+	}
+}
+
+func TestParseVariadic(t *testing.T) {
+	// The inputs are ASCII, and the byte offset of each token thus precedes its
+	// column number by one.
+	ident := func(val string, col int) token.Token {
+		return token.Token{Kind: token.Ident, Val: val, Line: 1, Col: col, Offset: col - 1}
+	}
+	golden := []struct {
+		in   string
+		want types.Func
+	}{
+		{
+			in: "package p; type F func(a ...int)",
+			want: types.Func{
+				Params:     []types.Parameter{{Names: []token.Token{ident("a", 24)}, Type: types.Name{Name: ident("int", 29)}}},
+				IsVariadic: true,
+			},
+		},
+		{
+			in: "package p; type F func(...int)",
+			want: types.Func{
+				Params:     []types.Parameter{{Type: types.Name{Name: ident("int", 27)}}},
+				IsVariadic: true,
+			},
+		},
+		{
+			in: "package p; type F func(a, b int, c ...string,)",
+			want: types.Func{
+				Params: []types.Parameter{
+					{Names: []token.Token{ident("a", 24), ident("b", 27)}, Type: types.Name{Name: ident("int", 29)}},
+					{Names: []token.Token{ident("c", 34)}, Type: types.Name{Name: ident("string", 39)}},
+				},
+				IsVariadic: true,
+			},
+		},
+		{
+			in: "package p; type F func(int, string)",
+			want: types.Func{
+				Params: []types.Parameter{{Type: types.Name{Name: ident("int", 24)}}, {Type: types.Name{Name: ident("string", 29)}}},
+			},
+		},
+	}
+
+	for i, g := range golden {
+		tokens, err := lexer.Parse(g.in)
+		if err != nil {
+			t.Errorf("i=%d: lexer.Parse failed; %v", i, err)
+			continue
+		}
+		f, err := ParseFile(tokens)
+		if err != nil {
+			t.Errorf("i=%d: ParseFile failed; %v", i, err)
+			continue
+		}
+		got := f.Decls[0].(ast.TypeDecl)[0].Type
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: function type mismatch; expected %#v, got %#v.", i, g.want, got)
+		}
+	}
+}
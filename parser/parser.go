@@ -1,2 +1,99 @@
 // Package parser implements syntactical analysis of Go source code.
 package parser
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/mewlang/go/token"
+)
+
+// A parser parses a slice of tokens into an abstract syntax tree.
+type parser struct {
+	// The input tokens, excluding comments.
+	tokens []token.Token
+	// Current position in the input.
+	pos int
+}
+
+// newParser returns a new parser for the provided tokens. Comment tokens are
+// ignored.
+func newParser(tokens []token.Token) *parser {
+	p := &parser{tokens: make([]token.Token, 0, len(tokens))}
+	for _, tok := range tokens {
+		if tok.Kind&^token.Invalid == token.Comment {
+			continue
+		}
+		p.tokens = append(p.tokens, tok)
+	}
+	return p
+}
+
+// peek returns the next token of the input without consuming it. A token of
+// kind NONE, positioned directly after the last token, is returned when no more
+// input is available.
+func (p *parser) peek() token.Token {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	eof := token.Token{Line: 1, Col: 1}
+	if n := len(p.tokens); n > 0 {
+		last := p.tokens[n-1]
+		eof.Line = last.Line
+		eof.Col = last.Col + utf8.RuneCountInString(last.Val)
+		eof.Offset = last.Offset + len(last.Val)
+	}
+	return eof
+}
+
+// next consumes and returns the next token of the input.
+func (p *parser) next() token.Token {
+	tok := p.peek()
+	if p.pos < len(p.tokens) {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it's of the specified token type. It
+// returns true if a token was consumed and false otherwise.
+func (p *parser) accept(kind token.Kind) bool {
+	if p.peek().Kind == kind {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// expect consumes and returns the next token, which must be of the specified
+// token type.
+func (p *parser) expect(kind token.Kind) (token.Token, error) {
+	tok := p.next()
+	if tok.Kind != kind {
+		return tok, errorf(tok, "expected %s, got %s", kind.Describe(), tok.Kind.Describe())
+	}
+	return tok, nil
+}
+
+// A SyntaxError describes a syntax error and the position of the offending
+// token. The position, including its byte offset, is copied from the token.
+type SyntaxError struct {
+	// Error message.
+	Msg string
+	// Position of the offending token.
+	Pos token.Position
+}
+
+// Error returns the error message prefixed with the position of the offending
+// token, in the form "line:col: message".
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%v: %s", e.Pos, e.Msg)
+}
+
+// errorf returns a syntax error positioned at the provided token.
+func errorf(tok token.Token, format string, args ...interface{}) error {
+	return &SyntaxError{
+		Msg: fmt.Sprintf(format, args...),
+		Pos: tok.Pos(),
+	}
+}
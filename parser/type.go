@@ -0,0 +1,545 @@
+package parser
+
+import (
+	"github.com/mewlang/go/token"
+	"github.com/mewlang/go/types"
+)
+
+// ParseType parses a type from the start of the provided tokens, and returns the
+// type and the number of tokens consumed; the remaining tokens are left for the
+// caller, e.g. the semicolon following the type. Type literals of any kind and
+// (possibly qualified) type names are recognized; e.g.
+//
+//    []map[string]*T
+//    chan<- int
+//    struct{ X int }
+//    func(int) error
+//
+// The package types has no parser of its own, as the length of array types is
+// an expression.
+func ParseType(tokens []token.Token) (types.Type, int, error) {
+	p := newParser(tokens)
+	typ, err := p.parseType()
+	if err != nil {
+		return nil, 0, err
+	}
+	// Translate the position of the parser, which excludes comments, into an
+	// index of the provided tokens.
+	n := 0
+	for i := 0; i < p.pos; n++ {
+		if tokens[n].Kind&^token.Invalid != token.Comment {
+			i++
+		}
+	}
+	return typ, n, nil
+}
+
+// parseType parses a type.
+//
+//    Type     = TypeName | TypeLit | "(" Type ")" .
+//    TypeName = identifier | QualifiedIdent .
+//    TypeLit  = ArrayType | StructType | PointerType | FunctionType | InterfaceType |
+//               SliceType | MapType | ChannelType .
+//
+// ref: http://golang.org/ref/spec#Types
+func (p *parser) parseType() (types.Type, error) {
+	switch tok := p.peek(); tok.Kind {
+	case token.Ident:
+		return p.parseTypeName()
+	case token.Lparen:
+		p.next()
+		typ, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(token.Rparen); err != nil {
+			return nil, err
+		}
+		return typ, nil
+	case token.Lbrack:
+		return p.parseArrayOrSliceType()
+	case token.Struct:
+		return p.parseStructType()
+	case token.Mul:
+		p.next()
+		base, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		return types.Pointer{Base: base}, nil
+	case token.Func:
+		p.next()
+		sig, err := p.parseSignature()
+		if err != nil {
+			return nil, err
+		}
+		return sig, nil
+	case token.Interface:
+		return p.parseInterfaceType()
+	case token.Map:
+		return p.parseMapType()
+	case token.Chan, token.Arrow:
+		return p.parseChanType()
+	default:
+		return nil, errorf(tok, "expected type, got %s", tok.Kind.Describe())
+	}
+}
+
+// isTypeStart returns true if kind may start a type, and false otherwise.
+func isTypeStart(kind token.Kind) bool {
+	switch kind {
+	case token.Ident, token.Lparen, token.Lbrack, token.Struct, token.Mul, token.Func, token.Interface, token.Map, token.Chan, token.Arrow:
+		return true
+	}
+	return false
+}
+
+// ParseQualifiedIdent recognizes a qualified identifier starting at index pos
+// of the provided tokens, and returns its package name and identifier, the
+// index of the token following it, and a boolean indicating success. Comment
+// tokens are skipped.
+//
+//    QualifiedIdent = PackageName "." identifier .
+//
+// Whether the package name actually denotes an imported package is not
+// verified; e.g. the selector expression x.f is recognized as well. A bare
+// identifier is not a qualified identifier.
+//
+// ref: http://golang.org/ref/spec#Qualified_identifiers
+func ParseQualifiedIdent(tokens []token.Token, pos int) (pkg, name token.Token, next int, ok bool) {
+	if pos < 0 {
+		return token.Token{}, token.Token{}, pos, false
+	}
+	var toks [3]token.Token
+	next = pos
+	for i, kind := range []token.Kind{token.Ident, token.Dot, token.Ident} {
+		for next < len(tokens) && tokens[next].Kind&^token.Invalid == token.Comment {
+			next++
+		}
+		if next >= len(tokens) || tokens[next].Kind != kind {
+			return token.Token{}, token.Token{}, pos, false
+		}
+		toks[i] = tokens[next]
+		next++
+	}
+	return toks[0], toks[2], next, true
+}
+
+// parseTypeName parses a (possibly qualified) type name.
+//
+//    TypeName       = identifier | QualifiedIdent .
+//    QualifiedIdent = PackageName "." identifier .
+//
+// ref: http://golang.org/ref/spec#Types
+// ref: http://golang.org/ref/spec#Qualified_identifiers
+func (p *parser) parseTypeName() (types.Name, error) {
+	if pkg, name, next, ok := ParseQualifiedIdent(p.tokens, p.pos); ok {
+		p.pos = next
+		return types.Name{Pkg: pkg, Name: name}, nil
+	}
+	name, err := p.expect(token.Ident)
+	if err != nil {
+		return types.Name{}, err
+	}
+	if !p.accept(token.Dot) {
+		return types.Name{Name: name}, nil
+	}
+	sel, err := p.expect(token.Ident)
+	if err != nil {
+		return types.Name{}, err
+	}
+	return types.Name{Pkg: name, Name: sel}, nil
+}
+
+// parseArrayOrSliceType parses an array type or a slice type.
+//
+//    ArrayType   = "[" ArrayLength "]" ElementType .
+//    ArrayLength = Expression .
+//    SliceType   = "[" "]" ElementType .
+//
+// ref: http://golang.org/ref/spec#Array_types
+// ref: http://golang.org/ref/spec#Slice_types
+func (p *parser) parseArrayOrSliceType() (types.Type, error) {
+	if _, err := p.expect(token.Lbrack); err != nil {
+		return nil, err
+	}
+	if p.accept(token.Rbrack) {
+		elem, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		return types.Slice{Elem: elem}, nil
+	}
+	var length interface{}
+	if tok := p.peek(); tok.Kind == token.Ellipsis {
+		// The array length is specified by the composite literal.
+		length = p.next()
+	} else {
+		x, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		length = x
+	}
+	if _, err := p.expect(token.Rbrack); err != nil {
+		return nil, err
+	}
+	elem, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	return types.Array{Len: length, Elem: elem}, nil
+}
+
+// parseStructType parses a struct type.
+//
+//    StructType     = "struct" "{" { FieldDecl ";" } "}" .
+//    FieldDecl      = (IdentifierList Type | AnonymousField) [ Tag ] .
+//    AnonymousField = [ "*" ] TypeName .
+//    Tag            = string_lit .
+//
+// ref: http://golang.org/ref/spec#Struct_types
+func (p *parser) parseStructType() (types.Type, error) {
+	if _, err := p.expect(token.Struct); err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(token.Lbrace); err != nil {
+		return nil, err
+	}
+	var s types.Struct
+	for kind := p.peek().Kind; kind != token.Rbrace && kind != token.None; kind = p.peek().Kind {
+		field, err := p.parseFieldDecl()
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, field)
+		if p.peek().Kind != token.Rbrace {
+			if _, err := p.expect(token.Semicolon); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if _, err := p.expect(token.Rbrace); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// parseFieldDecl parses a field declaration of a struct type.
+//
+//    FieldDecl      = (IdentifierList Type | AnonymousField) [ Tag ] .
+//    AnonymousField = [ "*" ] TypeName .
+//    Tag            = string_lit .
+//
+// ref: http://golang.org/ref/spec#Struct_types
+func (p *parser) parseFieldDecl() (types.Field, error) {
+	var field types.Field
+	switch p.peek().Kind {
+	case token.Mul:
+		// Anonymous field with a pointer type.
+		p.next()
+		name, err := p.parseTypeName()
+		if err != nil {
+			return types.Field{}, err
+		}
+		field.Type = types.Pointer{Base: name}
+	default:
+		name, err := p.expect(token.Ident)
+		if err != nil {
+			return types.Field{}, err
+		}
+		switch p.peek().Kind {
+		case token.Dot:
+			// Anonymous field with a qualified type name.
+			p.next()
+			sel, err := p.expect(token.Ident)
+			if err != nil {
+				return types.Field{}, err
+			}
+			field.Type = types.Name{Pkg: name, Name: sel}
+		case token.String, token.Semicolon, token.Rbrace:
+			// Anonymous field.
+			field.Type = types.Name{Name: name}
+		default:
+			// Identifier list followed by a type.
+			field.Names = []token.Token{name}
+			for p.accept(token.Comma) {
+				name, err := p.expect(token.Ident)
+				if err != nil {
+					return types.Field{}, err
+				}
+				field.Names = append(field.Names, name)
+			}
+			field.Type, err = p.parseType()
+			if err != nil {
+				return types.Field{}, err
+			}
+		}
+	}
+	if p.peek().Kind == token.String {
+		field.Tag = p.next()
+	}
+	return field, nil
+}
+
+// parseInterfaceType parses an interface type.
+//
+//    InterfaceType     = "interface" "{" { MethodSpec ";" } "}" .
+//    MethodSpec        = MethodName Signature | InterfaceTypeName .
+//    MethodName        = identifier .
+//    InterfaceTypeName = TypeName .
+//
+// ref: http://golang.org/ref/spec#Interface_types
+func (p *parser) parseInterfaceType() (types.Type, error) {
+	if _, err := p.expect(token.Interface); err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(token.Lbrace); err != nil {
+		return nil, err
+	}
+	var iface types.Interface
+	for kind := p.peek().Kind; kind != token.Rbrace && kind != token.None; kind = p.peek().Kind {
+		name, err := p.expect(token.Ident)
+		if err != nil {
+			return nil, err
+		}
+		var method types.Method
+		switch p.peek().Kind {
+		case token.Lparen:
+			// Method specifier.
+			sig, err := p.parseSignature()
+			if err != nil {
+				return nil, err
+			}
+			method = types.Method{Name: name, Sig: &sig}
+		case token.Dot:
+			// Embedded interface with a qualified type name.
+			p.next()
+			sel, err := p.expect(token.Ident)
+			if err != nil {
+				return nil, err
+			}
+			method = types.Method{Pkg: name, Name: sel}
+		default:
+			// Embedded interface.
+			method = types.Method{Name: name}
+		}
+		iface = append(iface, method)
+		if p.peek().Kind != token.Rbrace {
+			if _, err := p.expect(token.Semicolon); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if _, err := p.expect(token.Rbrace); err != nil {
+		return nil, err
+	}
+	return iface, nil
+}
+
+// parseMapType parses a map type.
+//
+//    MapType = "map" "[" KeyType "]" ElementType .
+//    KeyType = Type .
+//
+// ref: http://golang.org/ref/spec#Map_types
+func (p *parser) parseMapType() (types.Type, error) {
+	if _, err := p.expect(token.Map); err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(token.Lbrack); err != nil {
+		return nil, err
+	}
+	key, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(token.Rbrack); err != nil {
+		return nil, err
+	}
+	elem, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	return types.Map{Key: key, Elem: elem}, nil
+}
+
+// parseChanType parses a channel type.
+//
+//    ChannelType = ( "chan" | "chan" "<-" | "<-" "chan" ) ElementType .
+//
+// ref: http://golang.org/ref/spec#Channel_types
+func (p *parser) parseChanType() (types.Type, error) {
+	dir := types.BothDir
+	if p.accept(token.Arrow) {
+		// Receive-only channel.
+		dir = types.Recv
+		if _, err := p.expect(token.Chan); err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err := p.expect(token.Chan); err != nil {
+			return nil, err
+		}
+		if p.accept(token.Arrow) {
+			// Send-only channel.
+			dir = types.Send
+		}
+	}
+	elem, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	return types.Chan{Dir: dir, Elem: elem}, nil
+}
+
+// parseSignature parses a function signature. The func keyword, if any, has
+// already been consumed.
+//
+//    Signature      = Parameters [ Result ] .
+//    Result         = Parameters | Type .
+//
+// ref: http://golang.org/ref/spec#Function_types
+func (p *parser) parseSignature() (types.Func, error) {
+	var sig types.Func
+	var err error
+	var ellipsis token.Token
+	sig.Params, ellipsis, err = p.parseParameters()
+	if err != nil {
+		return types.Func{}, err
+	}
+	sig.IsVariadic = ellipsis.Kind != token.None
+	switch kind := p.peek().Kind; {
+	case kind == token.Lparen:
+		sig.Results, ellipsis, err = p.parseParameters()
+		if err != nil {
+			return types.Func{}, err
+		}
+		if ellipsis.Kind != token.None {
+			return types.Func{}, errorf(ellipsis, "cannot use ... in result parameter list")
+		}
+	case isTypeStart(kind):
+		typ, err := p.parseType()
+		if err != nil {
+			return types.Func{}, err
+		}
+		sig.Results = []types.Parameter{{Type: typ}}
+	}
+	return sig, nil
+}
+
+// parseParameters parses a parenthesized list of parameters or results. The
+// ellipsis type prefix of the final parameter is returned, or NONE if the list
+// is not variadic; only the final parameter may have an ellipsis type prefix.
+//
+//    Parameters     = "(" [ ParameterList [ "," ] ] ")" .
+//    ParameterList  = ParameterDecl { "," ParameterDecl } .
+//    ParameterDecl  = [ IdentifierList ] [ "..." ] Type .
+//
+// ref: http://golang.org/ref/spec#Function_types
+func (p *parser) parseParameters() ([]types.Parameter, token.Token, error) {
+	if _, err := p.expect(token.Lparen); err != nil {
+		return nil, token.Token{}, err
+	}
+
+	// Within a list of parameters, the names must either all be present or all
+	// be absent. A lone identifier is either a parameter name or a type name,
+	// which is resolved once the entire list has been parsed.
+	type entry struct {
+		// Parameter name, or NONE.
+		name token.Token
+		// Parameter type.
+		typ types.Type
+		// Specifies if the entry is a lone identifier.
+		lone bool
+	}
+	var entries []entry
+	// Ellipsis type prefix, and the index of its entry.
+	var ellipsis token.Token
+	ellipsisIndex := -1
+	for kind := p.peek().Kind; kind != token.Rparen && kind != token.None; kind = p.peek().Kind {
+		var e entry
+		if tok := p.peek(); tok.Kind == token.Ident {
+			p.next()
+			switch p.peek().Kind {
+			case token.Dot:
+				// Qualified type name.
+				p.next()
+				sel, err := p.expect(token.Ident)
+				if err != nil {
+					return nil, token.Token{}, err
+				}
+				e.typ = types.Name{Pkg: tok, Name: sel}
+			case token.Comma, token.Rparen:
+				// Parameter name or type name.
+				e.typ = types.Name{Name: tok}
+				e.lone = true
+			default:
+				// Parameter name followed by a type.
+				e.name = tok
+			}
+		}
+		if e.typ == nil {
+			if tok := p.peek(); tok.Kind == token.Ellipsis && ellipsisIndex == -1 {
+				ellipsis = p.next()
+				ellipsisIndex = len(entries)
+			} else if tok.Kind == token.Ellipsis {
+				return nil, token.Token{}, errorf(ellipsis, "can only use ... with final parameter")
+			}
+			typ, err := p.parseType()
+			if err != nil {
+				return nil, token.Token{}, err
+			}
+			e.typ = typ
+		}
+		entries = append(entries, e)
+		if !p.accept(token.Comma) {
+			break
+		}
+	}
+	rparen, err := p.expect(token.Rparen)
+	if err != nil {
+		return nil, token.Token{}, err
+	}
+	if ellipsisIndex != -1 && ellipsisIndex != len(entries)-1 {
+		return nil, token.Token{}, errorf(ellipsis, "can only use ... with final parameter")
+	}
+
+	named := false
+	for _, e := range entries {
+		if e.name.Kind != token.None {
+			named = true
+			break
+		}
+	}
+	var params []types.Parameter
+	if !named {
+		// Each entry denotes the type of an unnamed parameter.
+		for _, e := range entries {
+			params = append(params, types.Parameter{Type: e.typ})
+		}
+		return params, ellipsis, nil
+	}
+	// Lone identifiers denote the names of the following parameter declaration.
+	var names []token.Token
+	for _, e := range entries {
+		switch {
+		case e.lone:
+			names = append(names, e.typ.(types.Name).Name)
+		case e.name.Kind != token.None:
+			names = append(names, e.name)
+			params = append(params, types.Parameter{Names: names, Type: e.typ})
+			names = nil
+		default:
+			return nil, token.Token{}, errorf(rparen, "mixed named and unnamed parameters")
+		}
+	}
+	if len(names) > 0 {
+		return nil, token.Token{}, errorf(rparen, "mixed named and unnamed parameters")
+	}
+	// The ellipsis applies to a single parameter, not to a group of names.
+	if ellipsisIndex != -1 && len(params[len(params)-1].Names) > 1 {
+		return nil, token.Token{}, errorf(ellipsis, "can only use ... with final parameter")
+	}
+	return params, ellipsis, nil
+}
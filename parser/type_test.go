@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mewlang/go/lexer"
+	"github.com/mewlang/go/token"
+)
+
+func TestParseQualifiedIdent(t *testing.T) {
+	golden := []struct {
+		in        string
+		pos       int
+		pkg, name string
+		next      int
+		ok        bool
+	}{
+		{in: "fmt.Println", pos: 0, pkg: "fmt", name: "Println", next: 3, ok: true},
+		{in: "fmt.Println(x)", pos: 0, pkg: "fmt", name: "Println", next: 3, ok: true},
+		{in: "f(io.Reader)", pos: 2, pkg: "io", name: "Reader", next: 5, ok: true},
+		{in: "fmt. /* comment */ Println", pos: 0, pkg: "fmt", name: "Println", next: 4, ok: true},
+		// Not qualified.
+		{in: "x", pos: 0, next: 0, ok: false},
+		{in: "x.", pos: 0, next: 0, ok: false},
+		{in: "x.(T)", pos: 0, next: 0, ok: false},
+		{in: "1.5", pos: 0, next: 0, ok: false},
+		{in: "fmt.Println", pos: 1, next: 1, ok: false},
+		{in: "fmt.Println", pos: 4, next: 4, ok: false},
+		{in: "fmt.Println", pos: -1, next: -1, ok: false},
+	}
+
+	for i, g := range golden {
+		tokens, err := lexer.Parse(g.in)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		pkg, name, next, ok := ParseQualifiedIdent(tokens, g.pos)
+		if ok != g.ok || next != g.next {
+			t.Errorf("i=%d: result mismatch for %q; expected next=%d, ok=%t, got next=%d, ok=%t.", i, g.in, g.next, g.ok, next, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if pkg.Kind != token.Ident || pkg.Val != g.pkg {
+			t.Errorf("i=%d: package name mismatch; expected %q, got %v.", i, g.pkg, pkg)
+		}
+		if name.Kind != token.Ident || name.Val != g.name {
+			t.Errorf("i=%d: identifier mismatch; expected %q, got %v.", i, g.name, name)
+		}
+	}
+}
+
+func TestParseType(t *testing.T) {
+	golden := []struct {
+		in   string
+		want string
+		n    int
+		err  string
+	}{
+		{in: "[]map[string]*T", want: "[]map[string]*T", n: 8},
+		{in: "chan<- int", want: "chan<- int", n: 3},
+		{in: "struct{ X int }", want: "struct{X int}", n: 5},
+		{in: "func(int) error", want: "func(int) error", n: 5},
+		{in: "[4]io.Reader", want: "[4]io.Reader", n: 6},
+		{in: "[2*N]int", want: "[2 * N]int", n: 6},
+		{in: "<-chan /* comment */ T; x", want: "<-chan T", n: 4},
+		{in: "(*T) + 1", want: "*T", n: 4},
+		{in: "interface{}", want: "interface{}", n: 3},
+		{in: "+", err: `1:1: expected type, got '+'`},
+	}
+
+	for i, g := range golden {
+		tokens, err := lexer.Parse(g.in)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		typ, n, err := ParseType(tokens)
+		if err != nil {
+			if err.Error() != g.err {
+				t.Errorf("i=%d: error mismatch; expected %q, got %q.", i, g.err, err)
+			}
+			continue
+		}
+		if g.err != "" {
+			t.Errorf("i=%d: expected error %q, got nil.", i, g.err)
+			continue
+		}
+		if got := fmt.Sprint(typ); got != g.want {
+			t.Errorf("i=%d: type mismatch; expected %q, got %q.", i, g.want, got)
+		}
+		if n != g.n {
+			t.Errorf("i=%d: token count mismatch; expected %d, got %d.", i, g.n, n)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+// Package scanner implements an adapter which provides the interface of the
+// go/scanner package for the tokens of the lexer package, to ease migration of
+// tools built on the standard library.
+package scanner
+
+import (
+	"bytes"
+	gotoken "go/token"
+
+	"github.com/mewlang/go/lexer"
+	"github.com/mewlang/go/token"
+)
+
+// A Mode value is a set of flags (or 0). They control scanner behavior.
+type Mode uint
+
+// Scanner modes.
+const (
+	// ScanComments specifies that comments should be returned as COMMENT
+	// tokens, rather than skipped.
+	ScanComments Mode = 1 << iota
+)
+
+// A Scanner holds the scanner's internal state while processing a given text,
+// and mimics the Scanner type of the go/scanner package. It can be allocated as
+// part of another data structure but must be initialized via Init before use.
+type Scanner struct {
+	// Source file handle.
+	file *gotoken.File
+	// Source text.
+	src []byte
+	// Scanning mode.
+	mode Mode
+	// Tokens of the source text.
+	tokens []token.Token
+	// Index of the next token.
+	pos int
+
+	// ErrorCount is the number of errors encountered while lexing the source
+	// text.
+	ErrorCount int
+}
+
+// Init prepares the scanner s to tokenize the text src by setting the scanner
+// at the beginning of src. The file size must match the size of src, and the
+// line information of the file is set for the content of src.
+//
+// Unlike the Init method of the go/scanner package, Init lexes the entire
+// source text up front. Lexical errors are reported through ErrorCount, as the
+// errors of the lexer package carry no position information; invalid tokens are
+// returned as ILLEGAL.
+func (s *Scanner) Init(file *gotoken.File, src []byte, mode Mode) {
+	if file.Size() != len(src) {
+		panic("scanner.Scanner.Init: file size does not match src len")
+	}
+	file.SetLinesForContent(src)
+	*s = Scanner{file: file, src: src, mode: mode}
+	tokens, err := lexer.Parse(string(src))
+	if errs, ok := err.(lexer.ErrorList); ok {
+		s.ErrorCount = len(errs)
+	}
+	// The lexer inserts semicolons before trailing comments, while go/scanner
+	// inserts them after.
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i].Kind != token.Semicolon || s.isExplicit(tokens[i]) {
+			continue
+		}
+		j := i + 1
+		for j < len(tokens) && tokens[j].Kind&^token.Invalid == token.Comment && tokens[j].Line == tokens[i].Line {
+			j++
+		}
+		semi := tokens[i]
+		copy(tokens[i:], tokens[i+1:j])
+		tokens[j-1] = semi
+		i = j - 1
+	}
+	s.tokens = tokens
+}
+
+// isExplicit reports whether the provided semicolon token is present in the
+// source text, as opposed to inserted by the lexer.
+func (s *Scanner) isExplicit(semi token.Token) bool {
+	return semi.Offset < len(s.src) && s.src[semi.Offset] == ';'
+}
+
+// Scan scans the next token and returns the token position, the token, and its
+// literal string if applicable. The source end is indicated by EOF.
+//
+// If the returned token is a literal (IDENT, INT, FLOAT, IMAG, CHAR, STRING) or
+// COMMENT, the literal string has the corresponding value. If the returned
+// token is a keyword, the literal string is the keyword. If the returned token
+// is SEMICOLON, the corresponding literal string is ";" if the semicolon was
+// present in the source, and "\n" if the semicolon was inserted because of a
+// newline or at EOF. If the returned token is ILLEGAL, the literal string is the
+// offending text. In all other cases, Scan returns an empty literal string.
+func (s *Scanner) Scan() (pos gotoken.Pos, tok gotoken.Token, lit string) {
+	for s.pos < len(s.tokens) {
+		t := s.tokens[s.pos]
+		s.pos++
+		if t.Kind == token.Comment && s.mode&ScanComments == 0 {
+			continue
+		}
+		offset := t.Offset
+		pos = s.file.Pos(offset)
+		if !t.IsValid() {
+			return pos, gotoken.ILLEGAL, t.Val
+		}
+		tok = kinds[t.Kind]
+		switch {
+		case t.Kind == token.Semicolon:
+			if s.isExplicit(t) {
+				lit = ";"
+				break
+			}
+			// As with go/scanner, an inserted semicolon is positioned at the
+			// newline which ends the line, or at EOF.
+			lit = "\n"
+			if i := bytes.IndexByte(s.src[offset:], '\n'); i != -1 {
+				pos = s.file.Pos(offset + i)
+			} else {
+				pos = s.file.Pos(len(s.src))
+			}
+		case t.IsLiteral(), t.IsKeyword(), t.Kind == token.Comment:
+			lit = t.Val
+		}
+		return pos, tok, lit
+	}
+	return s.file.Pos(len(s.src)), gotoken.EOF, ""
+}
+
+// kinds maps from token kinds to the tokens of the go/token package.
+var kinds = [...]gotoken.Token{
+	// Special.
+	token.Comment: gotoken.COMMENT,
+
+	// Identifiers and literals.
+	token.Ident:  gotoken.IDENT,
+	token.Int:    gotoken.INT,
+	token.Float:  gotoken.FLOAT,
+	token.Imag:   gotoken.IMAG,
+	token.Rune:   gotoken.CHAR,
+	token.String: gotoken.STRING,
+
+	// Keywords.
+	token.Break:       gotoken.BREAK,
+	token.Case:        gotoken.CASE,
+	token.Chan:        gotoken.CHAN,
+	token.Const:       gotoken.CONST,
+	token.Continue:    gotoken.CONTINUE,
+	token.Default:     gotoken.DEFAULT,
+	token.Defer:       gotoken.DEFER,
+	token.Else:        gotoken.ELSE,
+	token.Fallthrough: gotoken.FALLTHROUGH,
+	token.For:         gotoken.FOR,
+	token.Func:        gotoken.FUNC,
+	token.Go:          gotoken.GO,
+	token.Goto:        gotoken.GOTO,
+	token.If:          gotoken.IF,
+	token.Import:      gotoken.IMPORT,
+	token.Interface:   gotoken.INTERFACE,
+	token.Map:         gotoken.MAP,
+	token.Package:     gotoken.PACKAGE,
+	token.Range:       gotoken.RANGE,
+	token.Return:      gotoken.RETURN,
+	token.Select:      gotoken.SELECT,
+	token.Struct:      gotoken.STRUCT,
+	token.Switch:      gotoken.SWITCH,
+	token.Type:        gotoken.TYPE,
+	token.Var:         gotoken.VAR,
+
+	// Operators and delimiters.
+	token.Not:         gotoken.NOT,
+	token.Arrow:       gotoken.ARROW,
+	token.Mul:         gotoken.MUL,
+	token.Div:         gotoken.QUO,
+	token.Mod:         gotoken.REM,
+	token.Shl:         gotoken.SHL,
+	token.Shr:         gotoken.SHR,
+	token.And:         gotoken.AND,
+	token.Clear:       gotoken.AND_NOT,
+	token.Add:         gotoken.ADD,
+	token.Sub:         gotoken.SUB,
+	token.Or:          gotoken.OR,
+	token.Xor:         gotoken.XOR,
+	token.Eq:          gotoken.EQL,
+	token.Neq:         gotoken.NEQ,
+	token.Lt:          gotoken.LSS,
+	token.Lte:         gotoken.LEQ,
+	token.Gt:          gotoken.GTR,
+	token.Gte:         gotoken.GEQ,
+	token.Land:        gotoken.LAND,
+	token.Lor:         gotoken.LOR,
+	token.Assign:      gotoken.ASSIGN,
+	token.DeclAssign:  gotoken.DEFINE,
+	token.MulAssign:   gotoken.MUL_ASSIGN,
+	token.DivAssign:   gotoken.QUO_ASSIGN,
+	token.ModAssign:   gotoken.REM_ASSIGN,
+	token.ShlAssign:   gotoken.SHL_ASSIGN,
+	token.ShrAssign:   gotoken.SHR_ASSIGN,
+	token.AndAssign:   gotoken.AND_ASSIGN,
+	token.ClearAssign: gotoken.AND_NOT_ASSIGN,
+	token.AddAssign:   gotoken.ADD_ASSIGN,
+	token.SubAssign:   gotoken.SUB_ASSIGN,
+	token.OrAssign:    gotoken.OR_ASSIGN,
+	token.XorAssign:   gotoken.XOR_ASSIGN,
+	token.Inc:         gotoken.INC,
+	token.Dec:         gotoken.DEC,
+	token.Lparen:      gotoken.LPAREN,
+	token.Lbrack:      gotoken.LBRACK,
+	token.Lbrace:      gotoken.LBRACE,
+	token.Rparen:      gotoken.RPAREN,
+	token.Rbrack:      gotoken.RBRACK,
+	token.Rbrace:      gotoken.RBRACE,
+	token.Dot:         gotoken.PERIOD,
+	token.Comma:       gotoken.COMMA,
+	token.Colon:       gotoken.COLON,
+	token.Semicolon:   gotoken.SEMICOLON,
+	token.Ellipsis:    gotoken.ELLIPSIS,
+}
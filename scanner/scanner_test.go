@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	goscanner "go/scanner"
+	gotoken "go/token"
+	"testing"
+)
+
+const src = `// Package p implements…
+package p
+
+import "fmt"
+
+/* T is a type. */
+type T struct {
+	X, Y int "tag"
+}
+
+func (t *T) Sum(xs ...float64) (sum float64) {
+	for _, x := range xs {
+		sum += x * 1.5e3 // Scale.
+	}
+	s := 'ä'; _ = s
+	ch := make(chan<- T)
+	fmt.Println("日本語", 0x1F, 0i, ch != nil && !false)
+	return
+}`
+
+func TestScanner(t *testing.T) {
+	for _, mode := range []Mode{0, ScanComments} {
+		// Scan the source text using go/scanner.
+		fset := gotoken.NewFileSet()
+		var want goscanner.Scanner
+		want.Init(fset.AddFile("p.go", -1, len(src)), []byte(src), nil, goscanner.Mode(mode))
+
+		// Scan the source text using the adapter.
+		var got Scanner
+		got.Init(fset.AddFile("p.go", -1, len(src)), []byte(src), mode)
+
+		for i := 0; ; i++ {
+			wantPos, wantTok, wantLit := want.Scan()
+			gotPos, gotTok, gotLit := got.Scan()
+			wp, gp := fset.Position(wantPos), fset.Position(gotPos)
+			if wp != gp || wantTok != gotTok || wantLit != gotLit {
+				t.Errorf("mode=%d, i=%d: token mismatch; expected %v %v %q, got %v %v %q.", mode, i, wp, wantTok, wantLit, gp, gotTok, gotLit)
+			}
+			if wantTok == gotoken.EOF || gotTok == gotoken.EOF {
+				break
+			}
+		}
+		if got.ErrorCount != 0 {
+			t.Errorf("mode=%d: error count mismatch; expected 0, got %d.", mode, got.ErrorCount)
+		}
+	}
+}
+
+func TestScannerIllegal(t *testing.T) {
+	const src = "x := 'ab'\n"
+	fset := gotoken.NewFileSet()
+	var s Scanner
+	s.Init(fset.AddFile("x.go", -1, len(src)), []byte(src), 0)
+	var toks []gotoken.Token
+	for {
+		_, tok, _ := s.Scan()
+		if tok == gotoken.EOF {
+			break
+		}
+		toks = append(toks, tok)
+	}
+	if s.ErrorCount == 0 {
+		t.Errorf("expected lexical errors, got none.")
+	}
+	found := false
+	for _, tok := range toks {
+		if tok == gotoken.ILLEGAL {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected ILLEGAL token in %v.", toks)
+	}
+}
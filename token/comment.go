@@ -0,0 +1,58 @@
+package token
+
+import "strings"
+
+// CommentText returns the text of the comment token, without comment markers,
+// or an empty string if tok is not a comment. The raw form of the comment is
+// still available from Val.
+//
+// The comment markers //, /* and */ are removed, together with one leading
+// space of the text. Leading asterisks are removed from the subsequent lines of
+// general comments; e.g.
+//
+//    /*
+//     * foo
+//     */
+//
+// Trailing white space of each line is removed, as are leading and trailing
+// empty lines. Lines are separated by newlines, and the text has no trailing
+// newline; similar to the Text method of go/ast.CommentGroup.
+//
+// ref: http://golang.org/ref/spec#Comments
+func (tok Token) CommentText() string {
+	if tok.Kind&^Invalid != Comment {
+		return ""
+	}
+	var lines []string
+	s := tok.Val
+	if strings.HasPrefix(s, "//") {
+		lines = append(lines, strings.TrimPrefix(s[2:], " "))
+	} else {
+		s = strings.TrimPrefix(s, "/*")
+		s = strings.TrimSuffix(s, "*/")
+		for i, line := range strings.Split(s, "\n") {
+			if i > 0 {
+				// Remove leading asterisks.
+				trimmed := strings.TrimLeft(line, " \t")
+				if strings.HasPrefix(trimmed, "*") {
+					line = strings.TrimPrefix(trimmed[1:], " ")
+				}
+			} else {
+				line = strings.TrimPrefix(line, " ")
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	// Remove trailing white space, and leading and trailing empty lines.
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,36 @@
+package token
+
+import "testing"
+
+func TestTokenCommentText(t *testing.T) {
+	golden := []struct {
+		tok  Token
+		want string
+	}{
+		// Line comments.
+		{tok: Token{Kind: Comment, Val: "// foo"}, want: "foo"},
+		{tok: Token{Kind: Comment, Val: "//foo"}, want: "foo"},
+		{tok: Token{Kind: Comment, Val: "//  indented  "}, want: " indented"},
+		{tok: Token{Kind: Comment, Val: "//"}, want: ""},
+		// Single-line general comments.
+		{tok: Token{Kind: Comment, Val: "/* foo */"}, want: "foo"},
+		{tok: Token{Kind: Comment, Val: "/**/"}, want: ""},
+		// Multi-line general comments.
+		{tok: Token{Kind: Comment, Val: "/*\nfoo\n\tbar\n*/"}, want: "foo\n\tbar"},
+		{tok: Token{Kind: Comment, Val: "/* foo\n   bar */"}, want: "foo\n   bar"},
+		{tok: Token{Kind: Comment, Val: "/*\n\n foo\n\n*/"}, want: " foo"},
+		// Star-prefixed general comments.
+		{tok: Token{Kind: Comment, Val: "/*\n * foo\n *\n * bar\n */"}, want: "foo\n\nbar"},
+		{tok: Token{Kind: Comment, Val: "/*\n\t*  foo\n\t*/"}, want: " foo"},
+		// Unterminated comments.
+		{tok: Token{Kind: Comment | Invalid, Val: "/* foo"}, want: "foo"},
+		// Non-comment tokens.
+		{tok: Token{Kind: String, Val: `"// foo"`}, want: ""},
+	}
+
+	for i, g := range golden {
+		if got := g.tok.CommentText(); got != g.want {
+			t.Errorf("i=%d: comment text mismatch for %q; expected %q, got %q.", i, g.tok.Val, g.want, got)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package token
+
+import "fmt"
+
+// A DiffOp is an edit operation of a token diff.
+type DiffOp uint8
+
+// Edit operations.
+const (
+	// DiffInsert inserts a token of the new token slice.
+	DiffInsert DiffOp = iota + 1
+	// DiffDelete deletes a token of the old token slice.
+	DiffDelete
+	// DiffChange replaces a token of the old token slice with a token of the
+	// new token slice.
+	DiffChange
+)
+
+// String returns the string representation of the edit operation.
+func (op DiffOp) String() string {
+	switch op {
+	case DiffInsert:
+		return "insert"
+	case DiffDelete:
+		return "delete"
+	case DiffChange:
+		return "change"
+	}
+	return fmt.Sprintf("DiffOp(%d)", uint8(op))
+}
+
+// A TokenDiff is a single edit of a token diff. The positions of an edit are
+// given by the positions of its tokens.
+type TokenDiff struct {
+	// Edit operation.
+	Op DiffOp
+	// Token of the old token slice; or the zero token for insertions.
+	Old Token
+	// Token of the new token slice; or the zero token for deletions.
+	New Token
+}
+
+// String returns the string representation of the edit; e.g.
+//
+//    3:1: change identifier "x" to identifier "y"
+func (d TokenDiff) String() string {
+	switch d.Op {
+	case DiffInsert:
+		return fmt.Sprintf("%v: insert %v %q", d.New.Pos(), d.New.Kind, d.New.Val)
+	case DiffDelete:
+		return fmt.Sprintf("%v: delete %v %q", d.Old.Pos(), d.Old.Kind, d.Old.Val)
+	}
+	return fmt.Sprintf("%v: %v %v %q to %v %q", d.Old.Pos(), d.Op, d.Old.Kind, d.Old.Val, d.New.Kind, d.New.Val)
+}
+
+// DiffTokens returns the edits which transform the old token slice a into the
+// new token slice b, in order of their position. Tokens are compared by kind
+// and value, ignoring their positions; the edits are derived from a longest
+// common subsequence of the two slices. A deletion directly followed by an
+// insertion is reported as a change, e.g. a renamed identifier.
+func DiffTokens(a, b []Token) []TokenDiff {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and
+	// b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case sameToken(a[i], b[j]):
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	// Walk the common subsequence, pairing the deletions and insertions between
+	// common tokens into changes.
+	var diffs []TokenDiff
+	var dels, inss []Token
+	flush := func() {
+		n := len(dels)
+		if len(inss) < n {
+			n = len(inss)
+		}
+		for k := 0; k < n; k++ {
+			diffs = append(diffs, TokenDiff{Op: DiffChange, Old: dels[k], New: inss[k]})
+		}
+		for _, tok := range dels[n:] {
+			diffs = append(diffs, TokenDiff{Op: DiffDelete, Old: tok})
+		}
+		for _, tok := range inss[n:] {
+			diffs = append(diffs, TokenDiff{Op: DiffInsert, New: tok})
+		}
+		dels, inss = dels[:0], inss[:0]
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && sameToken(a[i], b[j]):
+			flush()
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			dels = append(dels, a[i])
+			i++
+		default:
+			inss = append(inss, b[j])
+			j++
+		}
+	}
+	flush()
+	return diffs
+}
+
+// sameToken reports whether the two tokens have the same kind and value.
+func sameToken(a, b Token) bool {
+	return a.Kind == b.Kind && a.Val == b.Val
+}
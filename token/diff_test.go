@@ -0,0 +1,112 @@
+package token
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffTokens(t *testing.T) {
+	// x := 1
+	// y := 2
+	a := []Token{
+		{Kind: Ident, Val: "x", Line: 1, Col: 1},
+		{Kind: DeclAssign, Val: ":=", Line: 1, Col: 3},
+		{Kind: Int, Val: "1", Line: 1, Col: 6},
+		{Kind: Semicolon, Val: ";", Line: 1, Col: 7},
+		{Kind: Ident, Val: "y", Line: 2, Col: 1},
+		{Kind: DeclAssign, Val: ":=", Line: 2, Col: 3},
+		{Kind: Int, Val: "2", Line: 2, Col: 6},
+		{Kind: Semicolon, Val: ";", Line: 2, Col: 7},
+	}
+	// x := 1
+	// f()
+	// y := 2
+	inserted := []Token{
+		{Kind: Ident, Val: "x", Line: 1, Col: 1},
+		{Kind: DeclAssign, Val: ":=", Line: 1, Col: 3},
+		{Kind: Int, Val: "1", Line: 1, Col: 6},
+		{Kind: Semicolon, Val: ";", Line: 1, Col: 7},
+		{Kind: Ident, Val: "f", Line: 2, Col: 1},
+		{Kind: Lparen, Val: "(", Line: 2, Col: 2},
+		{Kind: Rparen, Val: ")", Line: 2, Col: 3},
+		{Kind: Semicolon, Val: ";", Line: 2, Col: 4},
+		{Kind: Ident, Val: "y", Line: 3, Col: 1},
+		{Kind: DeclAssign, Val: ":=", Line: 3, Col: 3},
+		{Kind: Int, Val: "2", Line: 3, Col: 6},
+		{Kind: Semicolon, Val: ";", Line: 3, Col: 7},
+	}
+	// x := 1
+	// z := 2
+	renamed := []Token{
+		{Kind: Ident, Val: "x", Line: 1, Col: 1},
+		{Kind: DeclAssign, Val: ":=", Line: 1, Col: 3},
+		{Kind: Int, Val: "1", Line: 1, Col: 6},
+		{Kind: Semicolon, Val: ";", Line: 1, Col: 7},
+		{Kind: Ident, Val: "z", Line: 2, Col: 1},
+		{Kind: DeclAssign, Val: ":=", Line: 2, Col: 3},
+		{Kind: Int, Val: "2", Line: 2, Col: 6},
+		{Kind: Semicolon, Val: ";", Line: 2, Col: 7},
+	}
+
+	golden := []struct {
+		a, b []Token
+		want []TokenDiff
+	}{
+		// Identical token slices.
+		{a: a, b: a, want: nil},
+		{a: nil, b: nil, want: nil},
+		// Inserted statement.
+		{
+			a: a, b: inserted,
+			want: []TokenDiff{
+				{Op: DiffInsert, New: inserted[4]},
+				{Op: DiffInsert, New: inserted[5]},
+				{Op: DiffInsert, New: inserted[6]},
+				{Op: DiffInsert, New: inserted[7]},
+			},
+		},
+		// Deleted statement.
+		{
+			a: inserted, b: a,
+			want: []TokenDiff{
+				{Op: DiffDelete, Old: inserted[4]},
+				{Op: DiffDelete, Old: inserted[5]},
+				{Op: DiffDelete, Old: inserted[6]},
+				{Op: DiffDelete, Old: inserted[7]},
+			},
+		},
+		// Renamed identifier.
+		{
+			a: a, b: renamed,
+			want: []TokenDiff{
+				{Op: DiffChange, Old: a[4], New: renamed[4]},
+			},
+		},
+		// Positions are ignored.
+		{a: a[:4], b: inserted[8:], want: []TokenDiff{{Op: DiffChange, Old: a[0], New: inserted[8]}, {Op: DiffChange, Old: a[2], New: inserted[10]}}},
+	}
+
+	for i, g := range golden {
+		got := DiffTokens(g.a, g.b)
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: diff mismatch; expected %v, got %v.", i, g.want, got)
+		}
+	}
+}
+
+func TestTokenDiffString(t *testing.T) {
+	golden := []struct {
+		d    TokenDiff
+		want string
+	}{
+		{d: TokenDiff{Op: DiffInsert, New: Token{Kind: Ident, Val: "f", Line: 2, Col: 1}}, want: `2:1: insert identifier "f"`},
+		{d: TokenDiff{Op: DiffDelete, Old: Token{Kind: Lparen, Val: "(", Line: 2, Col: 2}}, want: `2:2: delete ( "("`},
+		{d: TokenDiff{Op: DiffChange, Old: Token{Kind: Ident, Val: "x", Line: 3, Col: 1}, New: Token{Kind: Ident, Val: "y", Line: 3, Col: 1}}, want: `3:1: change identifier "x" to identifier "y"`},
+	}
+
+	for i, g := range golden {
+		if got := g.d.String(); got != g.want {
+			t.Errorf("i=%d: string mismatch; expected %q, got %q.", i, g.want, got)
+		}
+	}
+}
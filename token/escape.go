@@ -0,0 +1,149 @@
+package token
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// DecodeEscape decodes the backslash escape sequence at the start of s, and
+// returns the value it represents and its width in bytes. Both \' and \" are
+// accepted, even though single quotes are only valid escapes within rune
+// literals and double quotes only within string literals; it is up to the
+// caller to reject the other.
+//
+// Several backslash escapes allow arbitrary values to be encoded as ASCII text.
+// There are four ways to represent the integer value as a numeric constant: \x
+// followed by exactly two hexadecimal digits; \u followed by exactly four
+// hexadecimal digits; \U followed by exactly eight hexadecimal digits, and a
+// plain backslash \ followed by exactly three octal digits. In each case the
+// value of the literal is the value represented by the digits in the
+// corresponding base.
+//
+// Although these representations all result in an integer, they have different
+// valid ranges. Octal escapes must represent a value between 0 and 255
+// inclusive. Hexadecimal escapes satisfy this condition by construction. The
+// escapes \u and \U represent Unicode code points so within them some values
+// are illegal, in particular those above 0x10FFFF and surrogate halves.
+//
+// After a backslash, certain single-character escapes represent special values:
+//    \a   U+0007 alert or bell
+//    \b   U+0008 backspace
+//    \f   U+000C form feed
+//    \n   U+000A line feed or newline
+//    \r   U+000D carriage return
+//    \t   U+0009 horizontal tab
+//    \v   U+000b vertical tab
+//    \\   U+005c backslash
+//    \'   U+0027 single quote  (valid escape only within rune literals)
+//    \"   U+0022 double quote  (valid escape only within string literals)
+//
+// All other sequences starting with a backslash are illegal inside rune and
+// string literals. For an illegal sequence, width spans up to and including the
+// first offending character, or to the end of s.
+//
+// ref: http://golang.org/ref/spec#Rune_literals
+func DecodeEscape(s string) (r rune, width int, err error) {
+	return DecodeQuotedEscape(s, 0)
+}
+
+// DecodeQuotedEscape is like DecodeEscape, but decodes an escape sequence of a
+// rune or string literal delimited by the provided quote character (' or ").
+// A numeric escape sequence which is cut short by the quote character is
+// reported as having too few digits, as the literal ends before the escape
+// sequence; any other non-digit character, including the quote character of
+// the other kind of literal, is reported as such.
+func DecodeQuotedEscape(s string, quote rune) (r rune, width int, err error) {
+	if len(s) == 0 || s[0] != '\\' {
+		return 0, 0, errors.New("missing backslash in escape sequence")
+	}
+	if len(s) == 1 {
+		return 0, 1, errors.New("unexpected eof in escape sequence")
+	}
+	c, size := utf8.DecodeRuneInString(s[1:])
+	switch c {
+	case 'a':
+		return '\a', 2, nil
+	case 'b':
+		return '\b', 2, nil
+	case 'f':
+		return '\f', 2, nil
+	case 'n':
+		return '\n', 2, nil
+	case 'r':
+		return '\r', 2, nil
+	case 't':
+		return '\t', 2, nil
+	case 'v':
+		return '\v', 2, nil
+	case '\\', '\'', '"':
+		return c, 2, nil
+	case '0', '1', '2', '3':
+		// Octal escape.
+		width, err := escapeDigits(s, quote, 1, 3, "01234567", "octal", "octal")
+		if err != nil {
+			return 0, width, err
+		}
+		x, err := strconv.ParseUint(s[1:width], 8, 8)
+		if err != nil {
+			return 0, width, fmt.Errorf("invalid octal escape; %v", err)
+		}
+		return rune(x), width, nil
+	case 'x':
+		// Hexadecimal escape.
+		width, err := escapeDigits(s, quote, 2, 2, hexDigits, "hex", "hex")
+		if err != nil {
+			return 0, width, err
+		}
+		x, _ := strconv.ParseUint(s[2:width], 16, 8)
+		return rune(x), width, nil
+	case 'u', 'U':
+		// Unicode escape.
+		n := 4
+		if c == 'U' {
+			n = 8
+		}
+		width, err := escapeDigits(s, quote, 2, n, hexDigits, "hex", "Unicode")
+		if err != nil {
+			return 0, width, err
+		}
+		x, err := strconv.ParseUint(s[2:width], 16, 32)
+		if err != nil {
+			return 0, width, fmt.Errorf("invalid Unicode escape; %v", err)
+		}
+		r := rune(x)
+		if !utf8.ValidRune(r) {
+			return 0, width, fmt.Errorf("invalid Unicode code point %#U in escape sequence", r)
+		}
+		return r, width, nil
+	}
+	return 0, 1 + size, fmt.Errorf("unknown escape sequence %#U", c)
+}
+
+// hexDigits specifies the hexadecimal digit characters.
+const hexDigits = "0123456789ABCDEFabcdef"
+
+// escapeDigits checks that n digits from the provided set follow the first
+// start bytes of the escape sequence s, and returns the width of the escape
+// sequence. The escape sequence is part of a literal delimited by quote, or 0 if
+// none. The digits are described by desc, and the escape sequence by name, in
+// error messages.
+func escapeDigits(s string, quote rune, start, n int, digits, desc, name string) (width int, err error) {
+	for i := start; i < start+n; i++ {
+		if i >= len(s) {
+			return len(s), fmt.Errorf("unexpected eof in %s escape", name)
+		}
+		c, size := utf8.DecodeRuneInString(s[i:])
+		if c < utf8.RuneSelf && strings.IndexByte(digits, byte(c)) != -1 {
+			continue
+		}
+		if quote != 0 && c == quote {
+			// The literal ends before the escape sequence.
+			return i + size, fmt.Errorf("too few digits in %s escape; expected %d, got %d", name, n, i-start)
+		}
+		return i + size, fmt.Errorf("non-%s character %#U in %s escape", desc, c, name)
+	}
+	return start + n, nil
+}
@@ -0,0 +1,101 @@
+package token
+
+import "testing"
+
+func TestDecodeEscape(t *testing.T) {
+	golden := []struct {
+		in    string
+		want  rune
+		width int
+		err   string
+	}{
+		// Single-character escapes.
+		{in: `\n`, want: '\n', width: 2},
+		{in: `\a`, want: '\a', width: 2},
+		{in: `\\`, want: '\\', width: 2},
+		{in: `\'`, want: '\'', width: 2},
+		{in: `\"`, want: '"', width: 2},
+		{in: `\tabc`, want: '\t', width: 2},
+		// Numeric escapes.
+		{in: `\x41`, want: 'A', width: 4},
+		{in: `\xff`, want: 0xFF, width: 4},
+		{in: `\u00e9`, want: 'é', width: 6},
+		{in: `\U0001F600`, want: '😀', width: 10},
+		{in: `\377`, want: 0xFF, width: 4},
+		{in: `\0001`, want: 0, width: 4},
+		// Invalid escapes.
+		{in: ``, width: 0, err: "missing backslash in escape sequence"},
+		{in: `n`, width: 0, err: "missing backslash in escape sequence"},
+		{in: `\`, width: 1, err: "unexpected eof in escape sequence"},
+		{in: `\q`, width: 2, err: "unknown escape sequence U+0071 'q'"},
+		{in: `\ä`, width: 3, err: "unknown escape sequence U+00E4 'ä'"},
+		{in: `\8`, width: 2, err: "unknown escape sequence U+0038 '8'"},
+		{in: `\400`, width: 2, err: "unknown escape sequence U+0034 '4'"},
+		{in: `\07'`, width: 4, err: "non-octal character U+0027 ''' in octal escape"},
+		{in: `\08`, width: 3, err: "non-octal character U+0038 '8' in octal escape"},
+		{in: `\0`, width: 2, err: "unexpected eof in octal escape"},
+		{in: `\x4"`, width: 4, err: "non-hex character U+0022 '\"' in hex escape"},
+		{in: `\xg0`, width: 3, err: "non-hex character U+0067 'g' in hex escape"},
+		{in: `\x`, width: 2, err: "unexpected eof in hex escape"},
+		{in: `\u12'`, width: 5, err: "non-hex character U+0027 ''' in Unicode escape"},
+		{in: `\U0000000z`, width: 10, err: "non-hex character U+007A 'z' in Unicode escape"},
+		{in: `\ud800`, width: 6, err: "invalid Unicode code point U+D800 in escape sequence"},
+		{in: `\U00110000`, width: 10, err: "invalid Unicode code point U+110000 in escape sequence"},
+	}
+
+	for i, g := range golden {
+		got, width, err := DecodeEscape(g.in)
+		var errStr string
+		if err != nil {
+			errStr = err.Error()
+		}
+		if errStr != g.err {
+			t.Errorf("i=%d: error mismatch for %q; expected %q, got %q.", i, g.in, g.err, errStr)
+			continue
+		}
+		if got != g.want {
+			t.Errorf("i=%d: rune mismatch for %q; expected %#U, got %#U.", i, g.in, g.want, got)
+		}
+		if width != g.width {
+			t.Errorf("i=%d: width mismatch for %q; expected %d, got %d.", i, g.in, g.width, width)
+		}
+	}
+}
+
+func TestDecodeQuotedEscape(t *testing.T) {
+	golden := []struct {
+		in    string
+		quote rune
+		want  rune
+		width int
+		err   string
+	}{
+		{in: `\x41`, quote: '"', want: 'A', width: 4},
+		{in: `\'`, quote: '\'', want: '\'', width: 2},
+		// Numeric escapes cut short by the quote character of the literal.
+		{in: `\07'`, quote: '\'', width: 4, err: "too few digits in octal escape; expected 3, got 2"},
+		{in: `\x4"`, quote: '"', width: 4, err: "too few digits in hex escape; expected 2, got 1"},
+		{in: `\u12'`, quote: '\'', width: 5, err: "too few digits in Unicode escape; expected 4, got 2"},
+		// The quote character of the other kind of literal is not a digit.
+		{in: `\x4'`, quote: '"', width: 4, err: "non-hex character U+0027 ''' in hex escape"},
+		{in: `\07"`, quote: '\'', width: 4, err: "non-octal character U+0022 '\"' in octal escape"},
+	}
+
+	for i, g := range golden {
+		got, width, err := DecodeQuotedEscape(g.in, g.quote)
+		var errStr string
+		if err != nil {
+			errStr = err.Error()
+		}
+		if errStr != g.err {
+			t.Errorf("i=%d: error mismatch for %q; expected %q, got %q.", i, g.in, g.err, errStr)
+			continue
+		}
+		if got != g.want {
+			t.Errorf("i=%d: rune mismatch for %q; expected %#U, got %#U.", i, g.in, g.want, got)
+		}
+		if width != g.width {
+			t.Errorf("i=%d: width mismatch for %q; expected %d, got %d.", i, g.in, g.width, width)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package token
+
+import "fmt"
+
+// GoString returns a compact Go-syntax representation of the token, for use
+// with the %#v verb; e.g.
+//
+//    Token{Ident "foo" 1:1}
+//    Token{String|Invalid "\"abc" foo.go:3:7}
+func (tok Token) GoString() string {
+	pos := tok.Pos().String()
+	if tok.Filename != "" {
+		pos = tok.Filename + ":" + pos
+	}
+	return fmt.Sprintf("Token{%s %q %s}", kindName(tok.Kind), tok.Val, pos)
+}
+
+// kindName returns the name of the constant of the provided token type; e.g.
+// "Ident" or "Rune|Invalid".
+func kindName(kind Kind) string {
+	base := kind &^ Invalid
+	if base == None {
+		if kind == Invalid {
+			return "Invalid"
+		}
+		return "None"
+	}
+	if int(base) >= len(kindNames) || kindNames[base] == "" {
+		return fmt.Sprintf("Kind(%d)", uint8(kind))
+	}
+	if !kind.IsValid() {
+		return kindNames[base] + "|Invalid"
+	}
+	return kindNames[base]
+}
+
+// kindNames specifies the constant name of each token type.
+var kindNames = [...]string{
+	None:        "None",
+	Invalid:     "Invalid",
+	Comment:     "Comment",
+	Whitespace:  "Whitespace",
+	Newline:     "Newline",
+	Ident:       "Ident",
+	Int:         "Int",
+	Float:       "Float",
+	Imag:        "Imag",
+	Rune:        "Rune",
+	String:      "String",
+	Break:       "Break",
+	Case:        "Case",
+	Chan:        "Chan",
+	Const:       "Const",
+	Continue:    "Continue",
+	Default:     "Default",
+	Defer:       "Defer",
+	Else:        "Else",
+	Fallthrough: "Fallthrough",
+	For:         "For",
+	Func:        "Func",
+	Go:          "Go",
+	Goto:        "Goto",
+	If:          "If",
+	Import:      "Import",
+	Interface:   "Interface",
+	Map:         "Map",
+	Package:     "Package",
+	Range:       "Range",
+	Return:      "Return",
+	Select:      "Select",
+	Struct:      "Struct",
+	Switch:      "Switch",
+	Type:        "Type",
+	Var:         "Var",
+	Not:         "Not",
+	Arrow:       "Arrow",
+	Mul:         "Mul",
+	Div:         "Div",
+	Mod:         "Mod",
+	Shl:         "Shl",
+	Shr:         "Shr",
+	And:         "And",
+	Clear:       "Clear",
+	Add:         "Add",
+	Sub:         "Sub",
+	Or:          "Or",
+	Xor:         "Xor",
+	Eq:          "Eq",
+	Neq:         "Neq",
+	Lt:          "Lt",
+	Lte:         "Lte",
+	Gt:          "Gt",
+	Gte:         "Gte",
+	Land:        "Land",
+	Lor:         "Lor",
+	Assign:      "Assign",
+	DeclAssign:  "DeclAssign",
+	MulAssign:   "MulAssign",
+	DivAssign:   "DivAssign",
+	ModAssign:   "ModAssign",
+	ShlAssign:   "ShlAssign",
+	ShrAssign:   "ShrAssign",
+	AndAssign:   "AndAssign",
+	ClearAssign: "ClearAssign",
+	AddAssign:   "AddAssign",
+	SubAssign:   "SubAssign",
+	OrAssign:    "OrAssign",
+	XorAssign:   "XorAssign",
+	Inc:         "Inc",
+	Dec:         "Dec",
+	Lparen:      "Lparen",
+	Lbrack:      "Lbrack",
+	Lbrace:      "Lbrace",
+	Rparen:      "Rparen",
+	Rbrack:      "Rbrack",
+	Rbrace:      "Rbrace",
+	Dot:         "Dot",
+	Comma:       "Comma",
+	Colon:       "Colon",
+	Semicolon:   "Semicolon",
+	Ellipsis:    "Ellipsis",
+}
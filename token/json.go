@@ -0,0 +1,66 @@
+package token
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalJSON returns the JSON encoding of the token kind, which is the JSON
+// string of its name; e.g. "identifier" or "<invalid> rune literal".
+func (kind Kind) MarshalJSON() ([]byte, error) {
+	if kind == None {
+		return json.Marshal("NONE")
+	}
+	return json.Marshal(kind.String())
+}
+
+// UnmarshalJSON decodes the JSON encoding of a token kind, as produced by
+// MarshalJSON, and stores the result in kind.
+func (kind *Kind) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	if name == "NONE" {
+		*kind = None
+		return nil
+	}
+	k, ok := KindFromName(name)
+	if !ok {
+		return fmt.Errorf("token.Kind.UnmarshalJSON: unknown token kind %q", name)
+	}
+	*kind = k
+	return nil
+}
+
+// jsonToken is the JSON representation of a token.
+type jsonToken struct {
+	Kind     Kind   `json:"kind"`
+	Val      string `json:"val"`
+	Line     int    `json:"line"`
+	Col      int    `json:"col"`
+	Offset   int    `json:"offset"`
+	Filename string `json:"filename,omitempty"`
+}
+
+// MarshalJSON returns the JSON encoding of the token, which is an object with
+// the members kind, val, line, col, offset and the optional filename; e.g.
+//
+//    {"kind":"identifier","val":"main","line":3,"col":6,"offset":15}
+//
+// Token requires a MarshalJSON method of its own, as the MarshalJSON method of
+// the embedded Kind would otherwise be promoted.
+func (tok Token) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonToken{Kind: tok.Kind, Val: tok.Val, Line: tok.Line, Col: tok.Col, Offset: tok.Offset, Filename: tok.Filename})
+}
+
+// UnmarshalJSON decodes the JSON encoding of a token, as produced by
+// MarshalJSON, and stores the result in tok.
+func (tok *Token) UnmarshalJSON(data []byte) error {
+	var v jsonToken
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*tok = Token{Kind: v.Kind, Val: v.Val, Line: v.Line, Col: v.Col, Offset: v.Offset, Filename: v.Filename}
+	return nil
+}
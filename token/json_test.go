@@ -0,0 +1,47 @@
+package token
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTokenJSON(t *testing.T) {
+	golden := []struct {
+		tok  Token
+		want string
+	}{
+		{tok: Token{Kind: Ident, Val: "main", Line: 3, Col: 6, Offset: 15}, want: `{"kind":"identifier","val":"main","line":3,"col":6,"offset":15}`},
+		{tok: Token{Kind: Int, Val: "42", Line: 1, Col: 1}, want: `{"kind":"int literal","val":"42","line":1,"col":1,"offset":0}`},
+		{tok: Token{Kind: Add, Val: "+", Line: 2, Col: 4, Offset: 12}, want: `{"kind":"+","val":"+","line":2,"col":4,"offset":12}`},
+		{tok: Token{Kind: Func, Val: "func", Line: 5, Col: 1, Offset: 40}, want: `{"kind":"func","val":"func","line":5,"col":1,"offset":40}`},
+		{tok: Token{Kind: Rune | Invalid, Val: "'a", Line: 1, Col: 9, Offset: 8}, want: `{"kind":"\u003cinvalid\u003e rune literal","val":"'a","line":1,"col":9,"offset":8}`},
+		{tok: Token{Kind: Ident, Val: "x", Line: 7, Col: 2, Offset: 61, Filename: "foo.go"}, want: `{"kind":"identifier","val":"x","line":7,"col":2,"offset":61,"filename":"foo.go"}`},
+		{tok: Token{}, want: `{"kind":"NONE","val":"","line":0,"col":0,"offset":0}`},
+	}
+
+	for i, g := range golden {
+		buf, err := json.Marshal(g.tok)
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		if got := string(buf); got != g.want {
+			t.Errorf("i=%d: JSON mismatch; expected %s, got %s.", i, g.want, got)
+		}
+		var tok Token
+		if err := json.Unmarshal(buf, &tok); err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		if tok != g.tok {
+			t.Errorf("i=%d: token mismatch; expected %#v, got %#v.", i, g.tok, tok)
+		}
+	}
+}
+
+func TestKindUnmarshalJSONError(t *testing.T) {
+	var kind Kind
+	if err := json.Unmarshal([]byte(`"foo"`), &kind); err == nil {
+		t.Errorf("expected error for unknown token kind, got nil")
+	}
+}
@@ -0,0 +1,187 @@
+package token
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Unquote returns the value of a string or rune literal token, with escape
+// sequences interpreted; e.g. the raw string literal `\n` has the value "\\n",
+// and the rune literal '\x41' has the value "A".
+//
+// ref: http://golang.org/ref/spec#String_literals
+// ref: http://golang.org/ref/spec#Rune_literals
+func (tok Token) Unquote() (string, error) {
+	if tok.Kind != String && tok.Kind != Rune {
+		return "", fmt.Errorf("token.Token.Unquote: invalid token kind %v; expected string or rune literal", tok.Kind)
+	}
+	if tok.Kind == Rune {
+		// The escapes \x and \ooo of rune literals denote Unicode code points
+		// rather than bytes.
+		r, err := unquoteRune(tok.Val)
+		if err != nil {
+			return "", fmt.Errorf("token.Token.Unquote: invalid rune literal %s; %v", tok.Val, err)
+		}
+		return string(r), nil
+	}
+	s, err := unquoteString(tok.Val)
+	if err != nil {
+		return "", fmt.Errorf("token.Token.Unquote: invalid string literal %s; %v", tok.Val, err)
+	}
+	return s, nil
+}
+
+// unquoteString returns the value of the provided string literal.
+func unquoteString(lit string) (string, error) {
+	n := len(lit)
+	if n < 2 || lit[0] != lit[n-1] {
+		return "", strconv.ErrSyntax
+	}
+	s := lit[1 : n-1]
+	switch lit[0] {
+	case '`':
+		// Carriage returns are discarded from the value of raw string literals.
+		if strings.IndexByte(s, '`') != -1 {
+			return "", strconv.ErrSyntax
+		}
+		return strings.Replace(s, "\r", "", -1), nil
+	case '"':
+	default:
+		return "", strconv.ErrSyntax
+	}
+	buf := new(bytes.Buffer)
+	for len(s) > 0 {
+		switch c := s[0]; c {
+		case '"', '\n':
+			return "", strconv.ErrSyntax
+		case '\\':
+			if len(s) > 1 && s[1] == '\'' {
+				return "", fmt.Errorf("unknown escape sequence %#U", '\'')
+			}
+			r, width, err := DecodeQuotedEscape(s, '"')
+			if err != nil {
+				return "", err
+			}
+			if s[1] == 'x' || '0' <= s[1] && s[1] <= '7' {
+				// Hexadecimal and octal escapes denote bytes within string
+				// literals.
+				buf.WriteByte(byte(r))
+			} else {
+				buf.WriteRune(r)
+			}
+			s = s[width:]
+		default:
+			buf.WriteByte(c)
+			s = s[1:]
+		}
+	}
+	return buf.String(), nil
+}
+
+// unquoteRune returns the value of the provided rune literal.
+func unquoteRune(lit string) (rune, error) {
+	n := len(lit)
+	if n < 3 || lit[0] != '\'' || lit[n-1] != '\'' {
+		return 0, strconv.ErrSyntax
+	}
+	s := lit[1 : n-1]
+	var r rune
+	var width int
+	if s[0] == '\\' {
+		if len(s) > 1 && s[1] == '"' {
+			return 0, fmt.Errorf("unknown escape sequence %#U", '"')
+		}
+		var err error
+		r, width, err = DecodeQuotedEscape(s, '\'')
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		r, width = utf8.DecodeRuneInString(s)
+		if r == '\'' || r == '\n' {
+			return 0, strconv.ErrSyntax
+		}
+	}
+	if width != len(s) {
+		return 0, strconv.ErrSyntax
+	}
+	return r, nil
+}
+
+// Int returns the value of an integer literal token. The prefixes 0x, 0b and
+// 0o, octal literals with a leading 0, and underscore digit separators are
+// interpreted. An error is returned if the value overflows an int64.
+//
+// ref: http://golang.org/ref/spec#Integer_literals
+func (tok Token) Int() (int64, error) {
+	if tok.Kind != Int {
+		return 0, fmt.Errorf("token.Token.Int: invalid token kind %v; expected int literal", tok.Kind)
+	}
+	return strconv.ParseInt(tok.Val, 0, 64)
+}
+
+// Uint returns the value of an integer literal token, as interpreted by Int. An
+// error is returned if the value overflows an uint64.
+func (tok Token) Uint() (uint64, error) {
+	if tok.Kind != Int {
+		return 0, fmt.Errorf("token.Token.Uint: invalid token kind %v; expected int literal", tok.Kind)
+	}
+	return strconv.ParseUint(tok.Val, 0, 64)
+}
+
+// Float returns the value of a floating-point or integer literal token, rounded
+// to the nearest float64. An error is returned if the value overflows a float64.
+//
+// ref: http://golang.org/ref/spec#Floating-point_literals
+func (tok Token) Float() (float64, error) {
+	switch tok.Kind {
+	case Int:
+		return parseFloat(tok.Val, true)
+	case Float:
+		return parseFloat(tok.Val, false)
+	}
+	return 0, fmt.Errorf("token.Token.Float: invalid token kind %v; expected float or int literal", tok.Kind)
+}
+
+// Complex returns the value of an imaginary literal token. An error is returned
+// if the imaginary part overflows a float64.
+//
+// ref: http://golang.org/ref/spec#Imaginary_literals
+func (tok Token) Complex() (complex128, error) {
+	if tok.Kind != Imag || !strings.HasSuffix(tok.Val, "i") {
+		return 0, fmt.Errorf("token.Token.Complex: invalid token kind %v; expected imaginary literal", tok.Kind)
+	}
+	s := tok.Val[:len(tok.Val)-1]
+	// For backward compatibility, the integer part of an imaginary literal
+	// consisting entirely of decimal digits (and possibly underscores) is
+	// considered a decimal integer, even if it starts with a leading 0.
+	isInt := len(s) > 2 && s[0] == '0' && strings.ContainsRune("xXbBoO", rune(s[1])) && !strings.ContainsAny(s, ".pP")
+	f, err := parseFloat(s, isInt)
+	if err != nil {
+		return 0, err
+	}
+	return complex(0, f), nil
+}
+
+// parseFloat returns the value of the provided floating-point or integer
+// literal, rounded to the nearest float64.
+func parseFloat(s string, isInt bool) (float64, error) {
+	if !isInt {
+		return strconv.ParseFloat(s, 64)
+	}
+	// Integer literals may exceed the range of uint64.
+	x, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return 0, &strconv.NumError{Func: "ParseFloat", Num: s, Err: strconv.ErrSyntax}
+	}
+	f, _ := new(big.Float).SetInt(x).Float64()
+	if math.IsInf(f, 0) {
+		return f, &strconv.NumError{Func: "ParseFloat", Num: s, Err: strconv.ErrRange}
+	}
+	return f, nil
+}
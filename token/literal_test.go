@@ -0,0 +1,237 @@
+package token
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokenUnquote(t *testing.T) {
+	golden := []struct {
+		tok  Token
+		want string
+		err  bool
+	}{
+		// Interpreted string literals.
+		{tok: Token{Kind: String, Val: `"abc"`}, want: "abc"},
+		{tok: Token{Kind: String, Val: `""`}, want: ""},
+		{tok: Token{Kind: String, Val: `"a\tb\n"`}, want: "a\tb\n"},
+		{tok: Token{Kind: String, Val: `"\"quoted\""`}, want: `"quoted"`},
+		{tok: Token{Kind: String, Val: `"\x41\101ä\U0001F600"`}, want: "AAä😀"},
+		{tok: Token{Kind: String, Val: `"\xff"`}, want: "\xff"},
+		{tok: Token{Kind: String, Val: `"\377\u00e9'"`}, want: "\xffé'"},
+		{tok: Token{Kind: String, Val: `"日本語"`}, want: "日本語"},
+		// Raw string literals.
+		{tok: Token{Kind: String, Val: "`abc`"}, want: "abc"},
+		{tok: Token{Kind: String, Val: "`\\n`"}, want: `\n`},
+		{tok: Token{Kind: String, Val: "`a\nb`"}, want: "a\nb"},
+		{tok: Token{Kind: String, Val: "`a\r\nb`"}, want: "a\nb"},
+		// Rune literals.
+		{tok: Token{Kind: Rune, Val: `'a'`}, want: "a"},
+		{tok: Token{Kind: Rune, Val: `'ä'`}, want: "ä"},
+		{tok: Token{Kind: Rune, Val: `'\n'`}, want: "\n"},
+		{tok: Token{Kind: Rune, Val: `'\''`}, want: "'"},
+		{tok: Token{Kind: Rune, Val: `'\x41'`}, want: "A"},
+		{tok: Token{Kind: Rune, Val: `'\377'`}, want: "ÿ"},
+		{tok: Token{Kind: Rune, Val: `'ዤ'`}, want: "ዤ"},
+		// Errors.
+		{tok: Token{Kind: Int, Val: "42"}, err: true},
+		{tok: Token{Kind: Ident, Val: "x"}, err: true},
+		{tok: Token{Kind: String | Invalid, Val: `"abc`}, err: true},
+		{tok: Token{Kind: String, Val: `"\'"`}, err: true},
+		{tok: Token{Kind: Rune, Val: `'\"'`}, err: true},
+		{tok: Token{Kind: Rune, Val: `''`}, err: true},
+		{tok: Token{Kind: Rune, Val: `'ab'`}, err: true},
+		{tok: Token{Kind: Rune, Val: `'\400'`}, err: true},
+		{tok: Token{Kind: String, Val: `"\xg0"`}, err: true},
+		{tok: Token{Kind: String, Val: `"a"b"`}, err: true},
+		{tok: Token{Kind: String, Val: "`a`b`"}, err: true},
+	}
+
+	for i, g := range golden {
+		got, err := g.tok.Unquote()
+		if g.err {
+			if err == nil {
+				t.Errorf("i=%d: expected error for %v, got nil.", i, g.tok.Val)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		if got != g.want {
+			t.Errorf("i=%d: value mismatch; expected %q, got %q.", i, g.want, got)
+		}
+	}
+}
+
+func TestTokenInt(t *testing.T) {
+	golden := []struct {
+		val  string
+		want int64
+		err  bool
+	}{
+		{val: "0", want: 0},
+		{val: "42", want: 42},
+		{val: "1_000_000", want: 1000000},
+		{val: "0x2A", want: 42},
+		{val: "0XdeadBEEF", want: 0xdeadbeef},
+		{val: "0x_FF", want: 255},
+		{val: "0b101010", want: 42},
+		{val: "0B1_0", want: 2},
+		{val: "0o52", want: 42},
+		{val: "0O7_7", want: 63},
+		{val: "052", want: 42},
+		{val: "9223372036854775807", want: 1<<63 - 1},
+		{val: "9223372036854775808", err: true},
+		{val: "0xFFFFFFFFFFFFFFFF", err: true},
+		{val: "09", err: true},
+	}
+
+	for i, g := range golden {
+		got, err := Token{Kind: Int, Val: g.val}.Int()
+		if g.err {
+			if err == nil {
+				t.Errorf("i=%d: expected error for %v, got nil.", i, g.val)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		if got != g.want {
+			t.Errorf("i=%d: value mismatch for %v; expected %d, got %d.", i, g.val, g.want, got)
+		}
+	}
+
+	if _, err := (Token{Kind: Float, Val: "1.0"}).Int(); err == nil {
+		t.Errorf("expected error for float literal, got nil.")
+	}
+}
+
+func TestTokenUint(t *testing.T) {
+	golden := []struct {
+		val  string
+		want uint64
+		err  bool
+	}{
+		{val: "42", want: 42},
+		{val: "0xFFFFFFFFFFFFFFFF", want: 1<<64 - 1},
+		{val: "0o1777777777777777777777", want: 1<<64 - 1},
+		{val: "0b1_1", want: 3},
+		{val: "0777", want: 511},
+		{val: "18446744073709551616", err: true},
+	}
+
+	for i, g := range golden {
+		got, err := Token{Kind: Int, Val: g.val}.Uint()
+		if g.err {
+			if err == nil {
+				t.Errorf("i=%d: expected error for %v, got nil.", i, g.val)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		if got != g.want {
+			t.Errorf("i=%d: value mismatch for %v; expected %d, got %d.", i, g.val, g.want, got)
+		}
+	}
+}
+
+func TestTokenFloat(t *testing.T) {
+	golden := []struct {
+		tok  Token
+		want float64
+		err  bool
+	}{
+		{tok: Token{Kind: Float, Val: "0."}, want: 0},
+		{tok: Token{Kind: Float, Val: "72.40"}, want: 72.4},
+		{tok: Token{Kind: Float, Val: "072.40"}, want: 72.4},
+		{tok: Token{Kind: Float, Val: "2.71828"}, want: 2.71828},
+		{tok: Token{Kind: Float, Val: "1.e+0"}, want: 1},
+		{tok: Token{Kind: Float, Val: "6.67428e-11"}, want: 6.67428e-11},
+		{tok: Token{Kind: Float, Val: "1E6"}, want: 1e6},
+		{tok: Token{Kind: Float, Val: ".25"}, want: 0.25},
+		{tok: Token{Kind: Float, Val: ".12345E+5"}, want: 12345},
+		{tok: Token{Kind: Float, Val: "1_5."}, want: 15},
+		{tok: Token{Kind: Float, Val: "0x1p-2"}, want: 0.25},
+		{tok: Token{Kind: Float, Val: "0x1.Fp+0"}, want: 1.9375},
+		{tok: Token{Kind: Float, Val: "1e309"}, err: true},
+		// Integer literals.
+		{tok: Token{Kind: Int, Val: "42"}, want: 42},
+		{tok: Token{Kind: Int, Val: "0777"}, want: 511},
+		{tok: Token{Kind: Int, Val: "0x10"}, want: 16},
+		{tok: Token{Kind: Int, Val: "0b1_0"}, want: 2},
+		{tok: Token{Kind: Int, Val: "1267650600228229401496703205376"}, want: 1 << 100},
+		{tok: Token{Kind: Int, Val: "1" + strings.Repeat("0", 309)}, err: true},
+		// Other tokens.
+		{tok: Token{Kind: Imag, Val: "1i"}, err: true},
+		{tok: Token{Kind: String, Val: `"1"`}, err: true},
+	}
+
+	for i, g := range golden {
+		got, err := g.tok.Float()
+		if g.err {
+			if err == nil {
+				t.Errorf("i=%d: expected error for %v, got nil.", i, g.tok.Val)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		if got != g.want {
+			t.Errorf("i=%d: value mismatch for %v; expected %v, got %v.", i, g.tok.Val, g.want, got)
+		}
+	}
+}
+
+func TestTokenComplex(t *testing.T) {
+	golden := []struct {
+		val  string
+		want complex128
+		err  bool
+	}{
+		{val: "0i", want: 0i},
+		{val: "0123i", want: 123i},
+		{val: "0o123i", want: 83i},
+		{val: "0xabci", want: 2748i},
+		{val: "0b11i", want: 3i},
+		{val: "0.i", want: 0i},
+		{val: "2.71828i", want: 2.71828i},
+		{val: "1.e+0i", want: 1i},
+		{val: "6.67428e-11i", want: 6.67428e-11i},
+		{val: "1E6i", want: 1e6i},
+		{val: ".25i", want: 0.25i},
+		{val: ".12345E+5i", want: 12345i},
+		{val: "0x1p-2i", want: 0.25i},
+		{val: "1_0i", want: 10i},
+		{val: "1e309i", err: true},
+	}
+
+	for i, g := range golden {
+		got, err := Token{Kind: Imag, Val: g.val}.Complex()
+		if g.err {
+			if err == nil {
+				t.Errorf("i=%d: expected error for %v, got nil.", i, g.val)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		if got != g.want {
+			t.Errorf("i=%d: value mismatch for %v; expected %v, got %v.", i, g.val, g.want, got)
+		}
+	}
+
+	if _, err := (Token{Kind: Float, Val: "1.0"}).Complex(); err == nil {
+		t.Errorf("expected error for float literal, got nil.")
+	}
+}
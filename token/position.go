@@ -0,0 +1,97 @@
+package token
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// A Position describes a source position, including the byte offset, the line
+// number and the column number.
+type Position struct {
+	// Byte offset, starting at 0.
+	Offset int
+	// Line number, starting at 1.
+	Line int
+	// Column number, starting at 1 (character count).
+	Col int
+}
+
+// String returns a string representation of the position, in the form
+// "line:col".
+func (pos Position) String() string {
+	return fmt.Sprintf("%d:%d", pos.Line, pos.Col)
+}
+
+// Pos returns the position of the token.
+func (tok Token) Pos() Position {
+	return Position{Offset: tok.Offset, Line: tok.Line, Col: tok.Col}
+}
+
+// A LineTable records the byte offset of the start of each line of an input
+// string, and resolves byte offsets into line and column numbers on demand.
+type LineTable struct {
+	// The input string.
+	input string
+	// Byte offset of the start of each line, in ascending order.
+	lines []int
+}
+
+// NewLineTable returns a new line table for the provided input string.
+func NewLineTable(input string) *LineTable {
+	lt := &LineTable{
+		input: input,
+		lines: []int{0},
+	}
+	for i := 0; i < len(input); i++ {
+		if input[i] == '\n' {
+			lt.lines = append(lt.lines, i+1)
+		}
+	}
+	return lt
+}
+
+// Position returns the position of the provided byte offset. The line and
+// column numbers match those of the tokens of the lexer, which ignores a byte
+// order mark at the start of the input. Offsets outside of the input are
+// clamped to the input.
+func (lt *LineTable) Position(offset int) Position {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(lt.input) {
+		offset = len(lt.input)
+	}
+	// Index of the last line starting at or before offset.
+	i := sort.Search(len(lt.lines), func(i int) bool { return lt.lines[i] > offset }) - 1
+	lineStart := lt.lines[i]
+	col := utf8.RuneCountInString(lt.input[lineStart:offset]) + 1
+	if i == 0 && offset > 0 && strings.HasPrefix(lt.input, "\ufeff") {
+		// The byte order mark at the start of the input is ignored.
+		col--
+	}
+	return Position{Offset: offset, Line: i + 1, Col: col}
+}
+
+// TokenAt returns the token whose span contains the provided byte offset, and a
+// boolean indicating success. The span of a token is [Offset, Offset+Len), and
+// the tokens must be sorted by offset, as produced by the lexer. No token is
+// returned for offsets of white space between tokens.
+//
+// The spans of automatically inserted semicolons and of tokens with carriage
+// returns stripped from their values do not match their source text, as
+// described by Token.Len. A token present in the source, such as a trailing
+// comment, takes precedence over an inserted semicolon at the same offset.
+func TokenAt(tokens []Token, offset int) (Token, bool) {
+	// Index of the last token starting at or before offset.
+	i := sort.Search(len(tokens), func(i int) bool { return tokens[i].Offset > offset }) - 1
+	if i < 0 || offset < 0 {
+		return Token{}, false
+	}
+	tok := tokens[i]
+	if offset >= tok.Offset+tok.Len() {
+		return Token{}, false
+	}
+	return tok, true
+}
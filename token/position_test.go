@@ -0,0 +1,158 @@
+package token_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mewlang/go/lexer"
+	"github.com/mewlang/go/token"
+)
+
+func TestLineTable(t *testing.T) {
+	inputs := []string{
+		"package p\n\nvar (\n\tä, b = 1, \"日本\"\n)\n\nfunc f() { return }\n",
+		"\ufeffpackage p; var x = 'λ'",
+		"",
+	}
+	for i, input := range inputs {
+		tokens, err := lexer.Parse(input)
+		if err != nil {
+			t.Fatalf("i=%d: unexpected error; %v", i, err)
+		}
+		lt := token.NewLineTable(input)
+		// Locate the byte offset of each token present in the input, and
+		// compare the resolved position against the eagerly computed position.
+		offset := 0
+		for j, tok := range tokens {
+			if tok.Kind == token.Semicolon {
+				// Semicolons may be automatically inserted.
+				continue
+			}
+			k := strings.Index(input[offset:], tok.Val)
+			if k == -1 {
+				t.Fatalf("i=%d, j=%d: unable to locate %q in input.", i, j, tok.Val)
+			}
+			offset += k
+			pos := lt.Position(offset)
+			if pos.Line != tok.Line || pos.Col != tok.Col {
+				t.Errorf("i=%d, j=%d: position mismatch for %q; expected %d:%d, got %v.", i, j, tok.Val, tok.Line, tok.Col, pos)
+			}
+			if pos.Offset != offset {
+				t.Errorf("i=%d, j=%d: offset mismatch; expected %d, got %d.", i, j, offset, pos.Offset)
+			}
+			if tok.Pos() != pos {
+				t.Errorf("i=%d, j=%d: token position mismatch for %q; expected %#v, got %#v.", i, j, tok.Val, pos, tok.Pos())
+			}
+			offset += len(tok.Val)
+		}
+	}
+}
+
+func TestLineTableClamp(t *testing.T) {
+	lt := token.NewLineTable("a\nb")
+	golden := []struct {
+		offset int
+		want   token.Position
+	}{
+		{offset: -1, want: token.Position{Offset: 0, Line: 1, Col: 1}},
+		{offset: 0, want: token.Position{Offset: 0, Line: 1, Col: 1}},
+		{offset: 1, want: token.Position{Offset: 1, Line: 1, Col: 2}},
+		{offset: 2, want: token.Position{Offset: 2, Line: 2, Col: 1}},
+		{offset: 3, want: token.Position{Offset: 3, Line: 2, Col: 2}},
+		{offset: 4, want: token.Position{Offset: 3, Line: 2, Col: 2}},
+	}
+	for i, g := range golden {
+		if got := lt.Position(g.offset); got != g.want {
+			t.Errorf("i=%d: position mismatch; expected %#v, got %#v.", i, g.want, got)
+		}
+	}
+}
+
+func TestTokenAt(t *testing.T) {
+	const input = "\ufeffx := `a\nb`\t// 日本\n\tfoo()//c\n"
+	tokens, err := lexer.Parse(input)
+	if err != nil {
+		t.Fatalf("unexpected error; %v", err)
+	}
+	golden := []struct {
+		offset int
+		want   string
+		ok     bool
+	}{
+		// Byte order mark.
+		{offset: 0, ok: false},
+		// x
+		{offset: 3, want: "x", ok: true},
+		{offset: 4, ok: false},
+		// Start and end of :=
+		{offset: 5, want: ":=", ok: true},
+		{offset: 6, want: ":=", ok: true},
+		{offset: 7, ok: false},
+		// Start, middle and end of a raw string literal spanning two lines.
+		{offset: 8, want: "`a\nb`", ok: true},
+		{offset: 10, want: "`a\nb`", ok: true},
+		{offset: 12, want: "`a\nb`", ok: true},
+		// White space, which is overlapped by an automatically inserted
+		// semicolon.
+		{offset: 13, want: ";", ok: true},
+		// Comment.
+		{offset: 14, want: "// 日本", ok: true},
+		{offset: 20, want: "// 日本", ok: true},
+		{offset: 22, want: "// 日本", ok: true},
+		// Newline and indentation.
+		{offset: 23, ok: false},
+		{offset: 24, ok: false},
+		// foo()
+		{offset: 25, want: "foo", ok: true},
+		{offset: 27, want: "foo", ok: true},
+		{offset: 28, want: "(", ok: true},
+		{offset: 29, want: ")", ok: true},
+		// Comment at the offset of an automatically inserted semicolon.
+		{offset: 30, want: "//c", ok: true},
+		{offset: 32, want: "//c", ok: true},
+		{offset: 33, ok: false},
+		// Out of range.
+		{offset: -1, ok: false},
+		{offset: len(input), ok: false},
+	}
+
+	for i, g := range golden {
+		got, ok := token.TokenAt(tokens, g.offset)
+		if ok != g.ok || got.Val != g.want {
+			t.Errorf("i=%d: token mismatch at offset %d; expected %q (%t), got %q (%t).", i, g.offset, g.want, g.ok, got.Val, ok)
+		}
+	}
+}
+
+func TestTokenLen(t *testing.T) {
+	const input = "package p\n\nvar x = f(\"日本\", 'a') // x\n\nconst c = x+1;\n"
+	tokens, err := lexer.Parse(input)
+	if err != nil {
+		t.Fatalf("lexer.Parse failed; %v", err)
+	}
+	// The spans of the tokens tile the input, separated only by white space.
+	prev := 0
+	for i, tok := range tokens {
+		start := tok.Offset
+		end := start + tok.Len()
+		if gap := input[prev:start]; strings.TrimSpace(gap) != "" {
+			t.Errorf("i=%d: non-white space gap %q before token %q.", i, gap, tok.Val)
+		}
+		if tok.Kind == token.Semicolon && input[start] != ';' {
+			// Automatically inserted semicolon, positioned directly after the
+			// preceding token.
+			if start != prev {
+				t.Errorf("i=%d: inserted semicolon offset mismatch; expected %d, got %d.", i, prev, start)
+			}
+			prev = start
+			continue
+		}
+		if src := input[start:end]; src != tok.Val {
+			t.Errorf("i=%d: source mismatch; expected %q, got %q.", i, tok.Val, src)
+		}
+		prev = end
+	}
+	if rest := input[prev:]; strings.TrimSpace(rest) != "" {
+		t.Errorf("non-white space %q after last token.", rest)
+	}
+}
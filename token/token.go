@@ -2,7 +2,12 @@
 // programming language.
 package token
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
 
 // A Token represents a lexical token of the Go programming language.
 type Token struct {
@@ -14,6 +19,10 @@ type Token struct {
 	Line int
 	// Column number, starting at 1 (character count).
 	Col int
+	// Byte offset, starting at 0.
+	Offset int
+	// File name, or an empty string if unknown.
+	Filename string
 }
 
 func (tok Token) String() string {
@@ -26,6 +35,86 @@ func (tok Token) String() string {
 	return tok.Val
 }
 
+// Len returns the width of the token in bytes, which is the length of its value.
+// The token occupies the bytes [Offset, Offset+Len) of the source.
+//
+// Two kinds of tokens do not match their source text. Automatically inserted
+// semicolons have a length of 1, as they are indistinguishable from explicit
+// semicolons; they are positioned directly after the preceding token, and thus
+// overlap the source text which follows it. Carriage returns are stripped from
+// the values of comments and raw string literals, and are thus not included in
+// their length.
+func (tok Token) Len() int {
+	return len(tok.Val)
+}
+
+// IsBlank returns true if tok is the blank identifier (_), and false otherwise.
+//
+// ref: http://golang.org/ref/spec#Blank_identifier
+func (tok Token) IsBlank() bool {
+	return tok.Kind == Ident && tok.Val == "_"
+}
+
+// IsExported returns true if tok is an exported identifier, and false
+// otherwise. An identifier is exported if its first character is a Unicode
+// upper case letter (e.g. Foo or Жук).
+//
+// ref: http://golang.org/ref/spec#Exported_identifiers
+func (tok Token) IsExported() bool {
+	if tok.Kind != Ident {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(tok.Val)
+	return unicode.IsUpper(r)
+}
+
+// IsError returns true if tok is lexically invalid, and false otherwise. This is
+// the case for two kinds of tokens:
+//
+//    - a token of type Invalid, which is an unexpected character (e.g. #) that
+//      does not start any token.
+//    - a token of a specific type with the Invalid bit set, which is a
+//      malformed token of that type (e.g. an unterminated rune literal 'a).
+//
+// Use Kind&^Invalid to obtain the type of a malformed token.
+func (tok Token) IsError() bool {
+	return !tok.Kind.IsValid()
+}
+
+// IsBlockComment returns true if tok is a general comment (/* */), and false if
+// it is a line comment (//) or not a comment.
+//
+// ref: http://golang.org/ref/spec#Comments
+func (tok Token) IsBlockComment() bool {
+	return tok.Kind&^Invalid == Comment && strings.HasPrefix(tok.Val, "/*")
+}
+
+// CloneTokens returns a copy of the provided token slice, which shares no
+// memory with the original slice.
+func CloneTokens(ts []Token) []Token {
+	if ts == nil {
+		return nil
+	}
+	clone := make([]Token, len(ts))
+	copy(clone, ts)
+	return clone
+}
+
+// TokensEqual reports whether a and b contain the same tokens in the same order.
+// Tokens are equal if all of their fields, including positions, are equal. A nil
+// slice is equal to an empty slice.
+func TokensEqual(a, b []Token) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // Kind is the set of lexical token types of the Go programming language. It
 // contains four classes of tokens:
 //    * identifiers
@@ -43,6 +132,10 @@ const (
 	Invalid Kind = 1         // invalid token; e.g. an unterminated rune literal.
 	Comment Kind = iota << 1 // line comment or block comment.
 
+	// Trivia; only emitted by the lexer on request.
+	Whitespace // spaces, horizontal tabs and carriage returns.
+	Newline    // line break.
+
 	// Identifiers and literals.
 	// Identifier.
 	Ident // main
@@ -154,6 +247,10 @@ var names = [...]string{
 	Invalid: "<invalid>",
 	Comment: "comment",
 
+	// Trivia.
+	Whitespace: "whitespace",
+	Newline:    "newline",
+
 	// Identifiers and literals.
 	Ident:  "identifier",
 	Int:    "int literal",
@@ -239,12 +336,82 @@ var names = [...]string{
 	Ellipsis:    "...",
 }
 
+// String returns the name of the token type, or "Kind(N)" for unknown token
+// types.
 func (kind Kind) String() string {
+	base := kind &^ Invalid
+	if int(base) >= len(names) || (base != None && names[base] == "") {
+		return fmt.Sprintf("Kind(%d)", uint8(kind))
+	}
 	if !kind.IsValid() {
-		kind &^= Invalid
-		return "<invalid> " + names[kind]
+		return "<invalid> " + names[base]
 	}
-	return names[kind]
+	return names[base]
+}
+
+// Describe returns a phrase describing the token type, for use in error
+// messages such as "expected X, found Y"; e.g.
+//
+//    an identifier
+//    a string literal
+//    keyword 'for'
+//    '+'
+//
+// Lexically invalid token types are described as such, e.g. "an invalid rune
+// literal", and None as "EOF".
+func (kind Kind) Describe() string {
+	var s string
+	base := kind &^ Invalid
+	switch {
+	case kind == None:
+		return "EOF"
+	case kind == Invalid:
+		return "an invalid token"
+	case int(base) >= len(names) || names[base] == "":
+		// Unknown token type.
+		return kind.String()
+	case base.IsKeyword():
+		s = fmt.Sprintf("keyword '%s'", names[base])
+	case base.IsOperator():
+		s = fmt.Sprintf("'%s'", names[base])
+	case base == Whitespace:
+		s = names[base]
+	default:
+		// Comments, newlines, identifiers and literals.
+		s = names[base]
+		if kind.IsValid() {
+			return withArticle(s)
+		}
+	}
+	if !kind.IsValid() {
+		return withArticle("invalid " + s)
+	}
+	return s
+}
+
+// withArticle prefixes the provided phrase with an indefinite article.
+func withArticle(s string) string {
+	if strings.IndexByte("aeiou", s[0]) != -1 {
+		return "an " + s
+	}
+	return "a " + s
+}
+
+// KindFromName returns the token kind with the provided name, as returned by
+// Kind.String, and a boolean indicating success; e.g. "identifier", "func" or
+// "+=". It is the inverse of Kind.String; the name of a lexically invalid token
+// kind is also recognized, e.g. "<invalid> rune literal" maps to Rune|Invalid.
+func KindFromName(name string) (Kind, bool) {
+	var invalid Kind
+	if s := strings.TrimPrefix(name, "<invalid> "); s != name {
+		invalid, name = Invalid, s
+	}
+	for kind, s := range names {
+		if Kind(kind) != Invalid && s != "" && s == name {
+			return Kind(kind) | invalid, true
+		}
+	}
+	return None, false
 }
 
 // IsValid returns true if the token is lexically valid, and false otherwise.
@@ -263,8 +430,109 @@ func (kind Kind) IsOperator() bool {
 	return Not <= kind && kind <= Ellipsis
 }
 
+// IsDelimiter returns true if kind is one of the delimiters (, ), [, ], {, },
+// ., ,, :, ; and ..., and false otherwise. Delimiters are also reported as
+// operators by IsOperator; use kind.IsOperator() && !kind.IsDelimiter() to
+// match the remaining operators.
+func (kind Kind) IsDelimiter() bool {
+	return Lparen <= kind && kind <= Ellipsis
+}
+
+// IsOpenDelim returns true if kind is one of the opening delimiters (, [ and {,
+// and false otherwise.
+func (kind Kind) IsOpenDelim() bool {
+	switch kind {
+	case Lparen, Lbrack, Lbrace:
+		return true
+	}
+	return false
+}
+
+// IsCloseDelim returns true if kind is one of the closing delimiters ), ] and },
+// and false otherwise.
+func (kind Kind) IsCloseDelim() bool {
+	switch kind {
+	case Rparen, Rbrack, Rbrace:
+		return true
+	}
+	return false
+}
+
+// MatchingDelim returns the closing delimiter of the opening delimiter kind, or
+// the opening delimiter of the closing delimiter kind, and a boolean indicating
+// success; e.g. the matching delimiter of Lparen is Rparen.
+func (kind Kind) MatchingDelim() (Kind, bool) {
+	switch kind {
+	case Lparen:
+		return Rparen, true
+	case Lbrack:
+		return Rbrack, true
+	case Lbrace:
+		return Rbrace, true
+	case Rparen:
+		return Lparen, true
+	case Rbrack:
+		return Lbrack, true
+	case Rbrace:
+		return Lbrace, true
+	}
+	return None, false
+}
+
 // IsLiteral returns true if kind is an identifier or a basic literal, and false
 // otherwise.
 func (kind Kind) IsLiteral() bool {
 	return Ident <= kind && kind <= String
 }
+
+// IsComparison returns true if kind is a comparison operator, and false
+// otherwise.
+//
+//    rel_op = "==" | "!=" | "<" | "<=" | ">" | ">=" .
+//
+// ref: http://golang.org/ref/spec#Comparison_operators
+func (kind Kind) IsComparison() bool {
+	return Eq <= kind && kind <= Gte
+}
+
+// IsArithmetic returns true if kind is one of the arithmetic operators +, -, *,
+// / and %, and false otherwise.
+//
+// ref: http://golang.org/ref/spec#Arithmetic_operators
+func (kind Kind) IsArithmetic() bool {
+	switch kind {
+	case Add, Sub, Mul, Div, Mod:
+		return true
+	}
+	return false
+}
+
+// Precedence returns the operator precedence of the binary operator kind, which
+// ranges from 1 (||) to 5 (*, /, %, <<, >>, & and &^). If kind is not a binary
+// operator, or is lexically invalid, the result is 0.
+//
+//    Precedence    Operator
+//        5             *  /  %  <<  >>  &  &^
+//        4             +  -  |  ^
+//        3             ==  !=  <  <=  >  >=
+//        2             &&
+//        1             ||
+//
+// ref: http://golang.org/ref/spec#Operator_precedence
+func (kind Kind) Precedence() int {
+	switch {
+	case !kind.IsValid():
+		return 0
+	case Mul <= kind && kind <= Clear:
+		return 5
+	case Add <= kind && kind <= Xor:
+		return 4
+	case Eq <= kind && kind <= Gte:
+		return 3
+	case kind == Land:
+		return 2
+	case kind == Lor:
+		return 1
+	}
+	return 0
+}
@@ -1,6 +1,9 @@
 package token
 
-import "testing"
+import (
+	"fmt"
+	"testing"
+)
 
 type test struct {
 	kind Kind
@@ -291,3 +294,690 @@ func TestKindIsLiteral(t *testing.T) {
 		}
 	}
 }
+
+func TestKindPrecedence(t *testing.T) {
+	golden := []struct {
+		kind Kind
+		want int
+	}{
+		// Operators with precedence 5.
+		{kind: Mul, want: 5},
+		{kind: Div, want: 5},
+		{kind: Mod, want: 5},
+		{kind: Shl, want: 5},
+		{kind: Shr, want: 5},
+		{kind: And, want: 5},
+		{kind: Clear, want: 5},
+
+		// Operators with precedence 4.
+		{kind: Add, want: 4},
+		{kind: Sub, want: 4},
+		{kind: Or, want: 4},
+		{kind: Xor, want: 4},
+
+		// Operators with precedence 3.
+		{kind: Eq, want: 3},
+		{kind: Neq, want: 3},
+		{kind: Lt, want: 3},
+		{kind: Lte, want: 3},
+		{kind: Gt, want: 3},
+		{kind: Gte, want: 3},
+
+		// Operators with precedence 2.
+		{kind: Land, want: 2},
+
+		// Operators with precedence 1.
+		{kind: Lor, want: 1},
+
+		// Other tokens.
+		{kind: Not, want: 0},
+		{kind: Arrow, want: 0},
+		{kind: Assign, want: 0},
+		{kind: AddAssign, want: 0},
+		{kind: Inc, want: 0},
+		{kind: Lparen, want: 0},
+		{kind: Ellipsis, want: 0},
+		{kind: Ident, want: 0},
+		{kind: Int, want: 0},
+		{kind: Break, want: 0},
+		{kind: Comment, want: 0},
+		{kind: Invalid, want: 0},
+		{kind: None, want: 0},
+
+		// Lexically invalid operators.
+		{kind: Mul | Invalid, want: 0},
+		{kind: Add | Invalid, want: 0},
+		{kind: Eq | Invalid, want: 0},
+		{kind: Land | Invalid, want: 0},
+		{kind: Lor | Invalid, want: 0},
+	}
+
+	for i, g := range golden {
+		got := g.kind.Precedence()
+		if got != g.want {
+			t.Errorf("i=%d: Precedence mismatch for token type %v; expected %d, got %d.", i, g.kind, g.want, got)
+		}
+	}
+}
+
+func TestKindFromName(t *testing.T) {
+	golden := []struct {
+		name string
+		want Kind
+		ok   bool
+	}{
+		{name: "comment", want: Comment, ok: true},
+		{name: "identifier", want: Ident, ok: true},
+		{name: "string literal", want: String, ok: true},
+		{name: "func", want: Func, ok: true},
+		{name: "+=", want: AddAssign, ok: true},
+		{name: "...", want: Ellipsis, ok: true},
+		{name: "<invalid> rune literal", want: Rune | Invalid, ok: true},
+		{name: "<invalid> +=", want: AddAssign | Invalid, ok: true},
+		{name: "<invalid>", ok: false},
+		{name: "<invalid> ", ok: false},
+		{name: "<invalid> foo", ok: false},
+		{name: "NONE", ok: false},
+		{name: "", ok: false},
+		{name: "foo", ok: false},
+	}
+
+	for i, g := range golden {
+		got, ok := KindFromName(g.name)
+		if ok != g.ok || got != g.want {
+			t.Errorf("i=%d: KindFromName(%q) mismatch; expected %v (%t), got %v (%t).", i, g.name, g.want, g.ok, got, ok)
+		}
+	}
+
+	// Every named token kind round-trips through Kind.String, both as a valid
+	// and as an invalid token kind.
+	for kind := Comment; kind <= Ellipsis; kind += 2 {
+		for _, want := range []Kind{kind, kind | Invalid} {
+			got, ok := KindFromName(want.String())
+			if !ok || got != want {
+				t.Errorf("KindFromName(%q) mismatch; expected %v (true), got %v (%t).", want.String(), want, got, ok)
+			}
+		}
+	}
+}
+
+func TestKindIsComparison(t *testing.T) {
+	golden := []test{
+		// Comparison operators.
+		{kind: Eq, want: true},
+		{kind: Gt, want: true},
+		{kind: Gte, want: true},
+		{kind: Lt, want: true},
+		{kind: Lte, want: true},
+		{kind: Neq, want: true},
+
+		// Other tokens.
+		{kind: Add, want: false},
+		{kind: AddAssign, want: false},
+		{kind: And, want: false},
+		{kind: AndAssign, want: false},
+		{kind: Arrow, want: false},
+		{kind: Assign, want: false},
+		{kind: Clear, want: false},
+		{kind: ClearAssign, want: false},
+		{kind: Colon, want: false},
+		{kind: Comma, want: false},
+		{kind: Dec, want: false},
+		{kind: DeclAssign, want: false},
+		{kind: Div, want: false},
+		{kind: DivAssign, want: false},
+		{kind: Dot, want: false},
+		{kind: Ellipsis, want: false},
+		{kind: Inc, want: false},
+		{kind: Land, want: false},
+		{kind: Lbrace, want: false},
+		{kind: Lbrack, want: false},
+		{kind: Lor, want: false},
+		{kind: Lparen, want: false},
+		{kind: Mod, want: false},
+		{kind: ModAssign, want: false},
+		{kind: Mul, want: false},
+		{kind: MulAssign, want: false},
+		{kind: Not, want: false},
+		{kind: Or, want: false},
+		{kind: OrAssign, want: false},
+		{kind: Rbrace, want: false},
+		{kind: Rbrack, want: false},
+		{kind: Rparen, want: false},
+		{kind: Semicolon, want: false},
+		{kind: Shl, want: false},
+		{kind: ShlAssign, want: false},
+		{kind: Shr, want: false},
+		{kind: ShrAssign, want: false},
+		{kind: Sub, want: false},
+		{kind: SubAssign, want: false},
+		{kind: Xor, want: false},
+		{kind: XorAssign, want: false},
+		{kind: Break, want: false},
+		{kind: Case, want: false},
+		{kind: Chan, want: false},
+		{kind: Comment, want: false},
+		{kind: Const, want: false},
+		{kind: Continue, want: false},
+		{kind: Default, want: false},
+		{kind: Defer, want: false},
+		{kind: Else, want: false},
+		{kind: Fallthrough, want: false},
+		{kind: Float, want: false},
+		{kind: For, want: false},
+		{kind: Func, want: false},
+		{kind: Go, want: false},
+		{kind: Goto, want: false},
+		{kind: Ident, want: false},
+		{kind: If, want: false},
+		{kind: Imag, want: false},
+		{kind: Import, want: false},
+		{kind: Int, want: false},
+		{kind: Interface, want: false},
+		{kind: Invalid, want: false},
+		{kind: Map, want: false},
+		{kind: Package, want: false},
+		{kind: Range, want: false},
+		{kind: Return, want: false},
+		{kind: Rune, want: false},
+		{kind: Select, want: false},
+		{kind: String, want: false},
+		{kind: Struct, want: false},
+		{kind: Switch, want: false},
+		{kind: Type, want: false},
+		{kind: Var, want: false},
+	}
+
+	for i, g := range golden {
+		got := g.kind.IsComparison()
+		if got != g.want {
+			t.Errorf("i=%d: IsComparison mismatch for token type %v; expected %t, got %t.", i, g.kind, g.want, got)
+		}
+	}
+}
+
+func TestKindIsArithmetic(t *testing.T) {
+	golden := []test{
+		// Arithmetic operators.
+		{kind: Add, want: true},
+		{kind: Div, want: true},
+		{kind: Mod, want: true},
+		{kind: Mul, want: true},
+		{kind: Sub, want: true},
+
+		// Other tokens.
+		{kind: AddAssign, want: false},
+		{kind: And, want: false},
+		{kind: AndAssign, want: false},
+		{kind: Arrow, want: false},
+		{kind: Assign, want: false},
+		{kind: Clear, want: false},
+		{kind: ClearAssign, want: false},
+		{kind: Colon, want: false},
+		{kind: Comma, want: false},
+		{kind: Dec, want: false},
+		{kind: DeclAssign, want: false},
+		{kind: DivAssign, want: false},
+		{kind: Dot, want: false},
+		{kind: Ellipsis, want: false},
+		{kind: Eq, want: false},
+		{kind: Gt, want: false},
+		{kind: Gte, want: false},
+		{kind: Inc, want: false},
+		{kind: Land, want: false},
+		{kind: Lbrace, want: false},
+		{kind: Lbrack, want: false},
+		{kind: Lor, want: false},
+		{kind: Lparen, want: false},
+		{kind: Lt, want: false},
+		{kind: Lte, want: false},
+		{kind: ModAssign, want: false},
+		{kind: MulAssign, want: false},
+		{kind: Neq, want: false},
+		{kind: Not, want: false},
+		{kind: Or, want: false},
+		{kind: OrAssign, want: false},
+		{kind: Rbrace, want: false},
+		{kind: Rbrack, want: false},
+		{kind: Rparen, want: false},
+		{kind: Semicolon, want: false},
+		{kind: Shl, want: false},
+		{kind: ShlAssign, want: false},
+		{kind: Shr, want: false},
+		{kind: ShrAssign, want: false},
+		{kind: SubAssign, want: false},
+		{kind: Xor, want: false},
+		{kind: XorAssign, want: false},
+		{kind: Break, want: false},
+		{kind: Case, want: false},
+		{kind: Chan, want: false},
+		{kind: Comment, want: false},
+		{kind: Const, want: false},
+		{kind: Continue, want: false},
+		{kind: Default, want: false},
+		{kind: Defer, want: false},
+		{kind: Else, want: false},
+		{kind: Fallthrough, want: false},
+		{kind: Float, want: false},
+		{kind: For, want: false},
+		{kind: Func, want: false},
+		{kind: Go, want: false},
+		{kind: Goto, want: false},
+		{kind: Ident, want: false},
+		{kind: If, want: false},
+		{kind: Imag, want: false},
+		{kind: Import, want: false},
+		{kind: Int, want: false},
+		{kind: Interface, want: false},
+		{kind: Invalid, want: false},
+		{kind: Map, want: false},
+		{kind: Package, want: false},
+		{kind: Range, want: false},
+		{kind: Return, want: false},
+		{kind: Rune, want: false},
+		{kind: Select, want: false},
+		{kind: String, want: false},
+		{kind: Struct, want: false},
+		{kind: Switch, want: false},
+		{kind: Type, want: false},
+		{kind: Var, want: false},
+	}
+
+	for i, g := range golden {
+		got := g.kind.IsArithmetic()
+		if got != g.want {
+			t.Errorf("i=%d: IsArithmetic mismatch for token type %v; expected %t, got %t.", i, g.kind, g.want, got)
+		}
+	}
+}
+
+func TestKindIsDelimiter(t *testing.T) {
+	golden := []test{
+		// Delimiters.
+		{kind: Colon, want: true},
+		{kind: Comma, want: true},
+		{kind: Dot, want: true},
+		{kind: Ellipsis, want: true},
+		{kind: Lbrace, want: true},
+		{kind: Lbrack, want: true},
+		{kind: Lparen, want: true},
+		{kind: Rbrace, want: true},
+		{kind: Rbrack, want: true},
+		{kind: Rparen, want: true},
+		{kind: Semicolon, want: true},
+
+		// Other tokens.
+		{kind: Add, want: false},
+		{kind: Div, want: false},
+		{kind: Mod, want: false},
+		{kind: Mul, want: false},
+		{kind: Sub, want: false},
+		{kind: AddAssign, want: false},
+		{kind: And, want: false},
+		{kind: AndAssign, want: false},
+		{kind: Arrow, want: false},
+		{kind: Assign, want: false},
+		{kind: Clear, want: false},
+		{kind: ClearAssign, want: false},
+		{kind: Dec, want: false},
+		{kind: DeclAssign, want: false},
+		{kind: DivAssign, want: false},
+		{kind: Eq, want: false},
+		{kind: Gt, want: false},
+		{kind: Gte, want: false},
+		{kind: Inc, want: false},
+		{kind: Land, want: false},
+		{kind: Lor, want: false},
+		{kind: Lt, want: false},
+		{kind: Lte, want: false},
+		{kind: ModAssign, want: false},
+		{kind: MulAssign, want: false},
+		{kind: Neq, want: false},
+		{kind: Not, want: false},
+		{kind: Or, want: false},
+		{kind: OrAssign, want: false},
+		{kind: Shl, want: false},
+		{kind: ShlAssign, want: false},
+		{kind: Shr, want: false},
+		{kind: ShrAssign, want: false},
+		{kind: SubAssign, want: false},
+		{kind: Xor, want: false},
+		{kind: XorAssign, want: false},
+		{kind: Break, want: false},
+		{kind: Case, want: false},
+		{kind: Chan, want: false},
+		{kind: Comment, want: false},
+		{kind: Const, want: false},
+		{kind: Continue, want: false},
+		{kind: Default, want: false},
+		{kind: Defer, want: false},
+		{kind: Else, want: false},
+		{kind: Fallthrough, want: false},
+		{kind: Float, want: false},
+		{kind: For, want: false},
+		{kind: Func, want: false},
+		{kind: Go, want: false},
+		{kind: Goto, want: false},
+		{kind: Ident, want: false},
+		{kind: If, want: false},
+		{kind: Imag, want: false},
+		{kind: Import, want: false},
+		{kind: Int, want: false},
+		{kind: Interface, want: false},
+		{kind: Invalid, want: false},
+		{kind: Map, want: false},
+		{kind: Package, want: false},
+		{kind: Range, want: false},
+		{kind: Return, want: false},
+		{kind: Rune, want: false},
+		{kind: Select, want: false},
+		{kind: String, want: false},
+		{kind: Struct, want: false},
+		{kind: Switch, want: false},
+		{kind: Type, want: false},
+		{kind: Var, want: false},
+	}
+
+	for i, g := range golden {
+		got := g.kind.IsDelimiter()
+		if got != g.want {
+			t.Errorf("i=%d: IsDelimiter mismatch for token type %v; expected %t, got %t.", i, g.kind, g.want, got)
+		}
+	}
+}
+
+func TestKindIsOpenDelim(t *testing.T) {
+	golden := []test{
+		{kind: Lparen, want: true},
+		{kind: Lbrack, want: true},
+		{kind: Lbrace, want: true},
+		{kind: Rparen, want: false},
+		{kind: Rbrack, want: false},
+		{kind: Rbrace, want: false},
+		{kind: Comma, want: false},
+		{kind: Lt, want: false},
+		{kind: Ident, want: false},
+		{kind: Lparen | Invalid, want: false},
+	}
+
+	for i, g := range golden {
+		got := g.kind.IsOpenDelim()
+		if got != g.want {
+			t.Errorf("i=%d: IsOpenDelim mismatch for token type %v; expected %t, got %t.", i, g.kind, g.want, got)
+		}
+	}
+}
+
+func TestKindIsCloseDelim(t *testing.T) {
+	golden := []test{
+		{kind: Rparen, want: true},
+		{kind: Rbrack, want: true},
+		{kind: Rbrace, want: true},
+		{kind: Lparen, want: false},
+		{kind: Lbrack, want: false},
+		{kind: Lbrace, want: false},
+		{kind: Semicolon, want: false},
+		{kind: Gt, want: false},
+		{kind: Ident, want: false},
+		{kind: Rparen | Invalid, want: false},
+	}
+
+	for i, g := range golden {
+		got := g.kind.IsCloseDelim()
+		if got != g.want {
+			t.Errorf("i=%d: IsCloseDelim mismatch for token type %v; expected %t, got %t.", i, g.kind, g.want, got)
+		}
+	}
+}
+
+func TestKindMatchingDelim(t *testing.T) {
+	golden := []struct {
+		kind Kind
+		want Kind
+		ok   bool
+	}{
+		{kind: Lparen, want: Rparen, ok: true},
+		{kind: Rparen, want: Lparen, ok: true},
+		{kind: Lbrack, want: Rbrack, ok: true},
+		{kind: Rbrack, want: Lbrack, ok: true},
+		{kind: Lbrace, want: Rbrace, ok: true},
+		{kind: Rbrace, want: Lbrace, ok: true},
+		{kind: Lt, want: None, ok: false},
+		{kind: Gt, want: None, ok: false},
+		{kind: Comma, want: None, ok: false},
+		{kind: Ident, want: None, ok: false},
+		{kind: None, want: None, ok: false},
+	}
+
+	for i, g := range golden {
+		got, ok := g.kind.MatchingDelim()
+		if got != g.want || ok != g.ok {
+			t.Errorf("i=%d: MatchingDelim mismatch for token type %v; expected %v, %t, got %v, %t.", i, g.kind, g.want, g.ok, got, ok)
+		}
+	}
+}
+
+func TestKindDescribe(t *testing.T) {
+	golden := []struct {
+		kind Kind
+		want string
+	}{
+		// Special tokens.
+		{kind: None, want: "EOF"},
+		{kind: Invalid, want: "an invalid token"},
+		{kind: Comment, want: "a comment"},
+		{kind: Whitespace, want: "whitespace"},
+		{kind: Newline, want: "a newline"},
+		// Identifiers and literals.
+		{kind: Ident, want: "an identifier"},
+		{kind: Int, want: "an int literal"},
+		{kind: Float, want: "a float literal"},
+		{kind: Imag, want: "an imaginary literal"},
+		{kind: Rune, want: "a rune literal"},
+		{kind: String, want: "a string literal"},
+		{kind: Rune | Invalid, want: "an invalid rune literal"},
+		{kind: Comment | Invalid, want: "an invalid comment"},
+		// Keywords.
+		{kind: For, want: "keyword 'for'"},
+		{kind: Func, want: "keyword 'func'"},
+		{kind: Interface, want: "keyword 'interface'"},
+		// Operators and delimiters.
+		{kind: Add, want: "'+'"},
+		{kind: Arrow, want: "'<-'"},
+		{kind: ClearAssign, want: "'&^='"},
+		{kind: Lbrace, want: "'{'"},
+		{kind: Ellipsis, want: "'...'"},
+	}
+
+	for i, g := range golden {
+		got := g.kind.Describe()
+		if got != g.want {
+			t.Errorf("i=%d: description mismatch for token type %v; expected %q, got %q.", i, g.kind, g.want, got)
+		}
+	}
+}
+
+func TestKindString(t *testing.T) {
+	golden := []struct {
+		kind Kind
+		want string
+	}{
+		{kind: Ident, want: "identifier"},
+		{kind: For, want: "for"},
+		{kind: Ellipsis, want: "..."},
+		{kind: String | Invalid, want: "<invalid> string literal"},
+		// Unknown token types.
+		{kind: Ellipsis + 2, want: fmt.Sprintf("Kind(%d)", Ellipsis+2)},
+		{kind: (Ellipsis + 2) | Invalid, want: fmt.Sprintf("Kind(%d)", Ellipsis+3)},
+		{kind: 255, want: "Kind(255)"},
+	}
+
+	for i, g := range golden {
+		got := g.kind.String()
+		if got != g.want {
+			t.Errorf("i=%d: string mismatch; expected %q, got %q.", i, g.want, got)
+		}
+		// Describe must not panic on unknown token types either.
+		g.kind.Describe()
+	}
+}
+
+func TestTokenGoString(t *testing.T) {
+	golden := []struct {
+		tok  Token
+		want string
+	}{
+		{tok: Token{Kind: Ident, Val: "foo", Line: 1, Col: 1}, want: `Token{Ident "foo" 1:1}`},
+		{tok: Token{Kind: Func, Val: "func", Line: 12, Col: 3}, want: `Token{Func "func" 12:3}`},
+		{tok: Token{Kind: String | Invalid, Val: `"abc`, Line: 3, Col: 7, Filename: "foo.go"}, want: `Token{String|Invalid "\"abc" foo.go:3:7}`},
+		{tok: Token{Kind: Invalid, Val: "#", Line: 1, Col: 2}, want: `Token{Invalid "#" 1:2}`},
+		{tok: Token{}, want: `Token{None "" 0:0}`},
+		{tok: Token{Kind: Ellipsis + 2}, want: fmt.Sprintf(`Token{Kind(%d) "" 0:0}`, Ellipsis+2)},
+	}
+
+	for i, g := range golden {
+		got := fmt.Sprintf("%#v", g.tok)
+		if got != g.want {
+			t.Errorf("i=%d: Go-syntax representation mismatch; expected %s, got %s.", i, g.want, got)
+		}
+	}
+}
+
+func TestCloneTokens(t *testing.T) {
+	ts := []Token{
+		{Kind: Package, Val: "package", Line: 1, Col: 1},
+		{Kind: Ident, Val: "p", Line: 1, Col: 9},
+	}
+	clone := CloneTokens(ts)
+	if !TokensEqual(clone, ts) {
+		t.Fatalf("clone mismatch; expected %v, got %v.", ts, clone)
+	}
+	// Modifications of the clone must not affect the original slice.
+	clone[1].Val = "q"
+	if ts[1].Val != "p" {
+		t.Errorf("original token modified by clone; expected %q, got %q.", "p", ts[1].Val)
+	}
+	if CloneTokens(nil) != nil {
+		t.Errorf("expected nil clone of nil slice.")
+	}
+}
+
+func TestTokensEqual(t *testing.T) {
+	tok := Token{Kind: Ident, Val: "x", Line: 1, Col: 1}
+	golden := []struct {
+		a, b []Token
+		want bool
+	}{
+		{a: nil, b: nil, want: true},
+		{a: nil, b: []Token{}, want: true},
+		{a: []Token{tok}, b: []Token{tok}, want: true},
+		{a: []Token{tok}, b: nil, want: false},
+		{a: []Token{tok}, b: []Token{tok, tok}, want: false},
+		{a: []Token{tok}, b: []Token{{Kind: Int, Val: "x", Line: 1, Col: 1}}, want: false},
+		{a: []Token{tok}, b: []Token{{Kind: Ident | Invalid, Val: "x", Line: 1, Col: 1}}, want: false},
+		{a: []Token{tok}, b: []Token{{Kind: Ident, Val: "y", Line: 1, Col: 1}}, want: false},
+		{a: []Token{tok}, b: []Token{{Kind: Ident, Val: "x", Line: 2, Col: 1}}, want: false},
+		{a: []Token{tok}, b: []Token{{Kind: Ident, Val: "x", Line: 1, Col: 2}}, want: false},
+	}
+
+	for i, g := range golden {
+		if got := TokensEqual(g.a, g.b); got != g.want {
+			t.Errorf("i=%d: TokensEqual mismatch; expected %t, got %t.", i, g.want, got)
+		}
+		if got := TokensEqual(g.b, g.a); got != g.want {
+			t.Errorf("i=%d: TokensEqual mismatch (swapped); expected %t, got %t.", i, g.want, got)
+		}
+	}
+}
+
+func TestTokenIsBlockComment(t *testing.T) {
+	golden := []struct {
+		tok  Token
+		want bool
+	}{
+		{tok: Token{Kind: Comment, Val: "/* a comment */"}, want: true},
+		{tok: Token{Kind: Comment, Val: "/*\n*/"}, want: true},
+		{tok: Token{Kind: Comment | Invalid, Val: "/* unterminated"}, want: true},
+		{tok: Token{Kind: Comment, Val: "// a comment"}, want: false},
+		{tok: Token{Kind: Comment, Val: "///* a comment */"}, want: false},
+		{tok: Token{Kind: String, Val: `"/* not a comment */"`}, want: false},
+		{tok: Token{Kind: Div, Val: "/"}, want: false},
+	}
+
+	for i, g := range golden {
+		if got := g.tok.IsBlockComment(); got != g.want {
+			t.Errorf("i=%d: IsBlockComment mismatch for %q; expected %t, got %t.", i, g.tok.Val, g.want, got)
+		}
+	}
+}
+
+func TestTokenIsBlank(t *testing.T) {
+	golden := []struct {
+		tok  Token
+		want bool
+	}{
+		{tok: Token{Kind: Ident, Val: "_"}, want: true},
+		{tok: Token{Kind: Ident, Val: "_x"}, want: false},
+		{tok: Token{Kind: Ident, Val: "x_"}, want: false},
+		{tok: Token{Kind: Ident, Val: "__"}, want: false},
+		{tok: Token{Kind: Ident | Invalid, Val: "_"}, want: false},
+		{tok: Token{Kind: String, Val: "_"}, want: false},
+	}
+
+	for i, g := range golden {
+		if got := g.tok.IsBlank(); got != g.want {
+			t.Errorf("i=%d: IsBlank mismatch for %q; expected %t, got %t.", i, g.tok.Val, g.want, got)
+		}
+	}
+}
+
+func TestTokenIsExported(t *testing.T) {
+	golden := []struct {
+		tok  Token
+		want bool
+	}{
+		{tok: Token{Kind: Ident, Val: "Foo"}, want: true},
+		{tok: Token{Kind: Ident, Val: "X"}, want: true},
+		{tok: Token{Kind: Ident, Val: "Жук"}, want: true},
+		{tok: Token{Kind: Ident, Val: "Ǆ"}, want: true},
+		{tok: Token{Kind: Ident, Val: "foo"}, want: false},
+		{tok: Token{Kind: Ident, Val: "жук"}, want: false},
+		{tok: Token{Kind: Ident, Val: "_Foo"}, want: false},
+		{tok: Token{Kind: Ident, Val: "_"}, want: false},
+		{tok: Token{Kind: Ident, Val: "日本"}, want: false},
+		{tok: Token{Kind: Ident | Invalid, Val: "Foo"}, want: false},
+		{tok: Token{Kind: String, Val: `"Foo"`}, want: false},
+		{tok: Token{Kind: Ident, Val: ""}, want: false},
+	}
+
+	for i, g := range golden {
+		if got := g.tok.IsExported(); got != g.want {
+			t.Errorf("i=%d: IsExported mismatch for %q; expected %t, got %t.", i, g.tok.Val, g.want, got)
+		}
+	}
+}
+
+func TestTokenIsError(t *testing.T) {
+	golden := []struct {
+		tok  Token
+		want bool
+	}{
+		// Unexpected characters.
+		{tok: Token{Kind: Invalid, Val: "#"}, want: true},
+		// Malformed tokens.
+		{tok: Token{Kind: Rune | Invalid, Val: "'a"}, want: true},
+		{tok: Token{Kind: Comment | Invalid, Val: "/*"}, want: true},
+		{tok: Token{Kind: Int | Invalid, Val: "0x"}, want: true},
+		// Valid tokens.
+		{tok: Token{Kind: Rune, Val: "'a'"}, want: false},
+		{tok: Token{Kind: Ident, Val: "x"}, want: false},
+		{tok: Token{Kind: Semicolon, Val: ";"}, want: false},
+		{tok: Token{}, want: false},
+	}
+
+	for i, g := range golden {
+		if got := g.tok.IsError(); got != g.want {
+			t.Errorf("i=%d: IsError mismatch for %v; expected %t, got %t.", i, g.tok, g.want, got)
+		}
+	}
+}
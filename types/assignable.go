@@ -0,0 +1,109 @@
+package types
+
+// AssignableTo reports whether a value of type src is assignable to a variable
+// of type dst. The following assignability rules are in scope:
+//
+//    - src and dst are identical.
+//    - src and dst have identical underlying types and at least one of src or
+//      dst is not a named type.
+//    - dst is an interface type and src implements dst.
+//    - src is a bidirectional channel type, dst is a channel type, src and dst
+//      have identical element types, and at least one of src or dst is not a
+//      named type.
+//    - src is the type of the predeclared identifier nil and dst is a pointer,
+//      function, slice, map, channel, or interface type.
+//
+// The assignability of untyped constants is not in scope.
+//
+// ref: http://golang.org/ref/spec#Assignability
+func AssignableTo(src, dst Type) bool {
+	if Identical(src, dst) {
+		return true
+	}
+	su, du := Underlying(src), Underlying(dst)
+	if su == nil || du == nil {
+		// Unresolved type names.
+		return false
+	}
+	if src == UntypedNil {
+		switch du.(type) {
+		case Pointer, Func, Slice, Map, Chan, Interface:
+			return true
+		}
+		return false
+	}
+	if (!isNamed(src) || !isNamed(dst)) && Identical(su, du) {
+		return true
+	}
+	if iface, ok := du.(Interface); ok {
+		return Implements(src, iface)
+	}
+	if sc, ok := su.(Chan); ok && sc.Dir == BothDir {
+		if dc, ok := du.(Chan); ok && (!isNamed(src) || !isNamed(dst)) {
+			return Identical(sc.Elem, dc.Elem)
+		}
+	}
+	return false
+}
+
+// Implements reports whether the method set of t contains each method of the
+// interface type iface. The method set of an interface type is its interface;
+// the method set of a named type T, or a pointer to T, consists of the methods
+// declared with receiver base type T. Promoted methods of embedded fields are
+// not in the method set.
+//
+// An embedded interface type name of iface is implemented only by interface
+// types which embed the same interface type name.
+//
+// ref: http://golang.org/ref/spec#Method_sets
+func Implements(t Type, iface Interface) bool {
+	methods := methodSet(t)
+	for _, m := range iface {
+		if !hasMethod(methods, m) {
+			return false
+		}
+	}
+	return true
+}
+
+// methodSet returns the method set of t.
+func methodSet(t Type) []Method {
+	if iface, ok := Underlying(t).(Interface); ok {
+		return iface
+	}
+	if ptr, ok := t.(Pointer); ok {
+		t = ptr.Base
+	}
+	if name, ok := t.(Name); ok {
+		return name.Methods
+	}
+	return nil
+}
+
+// hasMethod reports whether the provided method set contains a method with the
+// same key and an identical signature as m.
+func hasMethod(methods []Method, m Method) bool {
+	key := methodKey(m)
+	for _, n := range methods {
+		if methodKey(n) != key {
+			continue
+		}
+		if m.Sig == nil || n.Sig == nil {
+			return m.Sig == n.Sig
+		}
+		return identicalSigs(*m.Sig, *n.Sig)
+	}
+	return false
+}
+
+// isNamed reports whether t is a named type; i.e. a type name or a predeclared
+// type.
+func isNamed(t Type) bool {
+	switch t := t.(type) {
+	case Name:
+		return true
+	case Basic:
+		return t != UntypedNil
+	}
+	return false
+}
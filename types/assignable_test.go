@@ -0,0 +1,92 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/mewlang/go/token"
+)
+
+func TestAssignableTo(t *testing.T) {
+	ident := func(val string) token.Token {
+		return token.Token{Kind: token.Ident, Val: val}
+	}
+	// type Stringer interface { String() string }
+	stringer := Name{
+		Name: ident("Stringer"),
+		Type: Interface{
+			{Name: ident("String"), Sig: &Func{Results: []Parameter{{Type: String}}}},
+		},
+	}
+	// type T int
+	// func (T) String() string
+	tt := Name{
+		Name: ident("T"),
+		Type: Int,
+		Methods: []Method{
+			{Name: ident("String"), Sig: &Func{Results: []Parameter{{Type: String}}}},
+		},
+	}
+	// type U int
+	// func (U) String() int
+	u := Name{
+		Name: ident("U"),
+		Type: Int,
+		Methods: []Method{
+			{Name: ident("String"), Sig: &Func{Results: []Parameter{{Type: Int}}}},
+		},
+	}
+	// type S []int
+	s := Name{Name: ident("S"), Type: Slice{Elem: Int}}
+	// type C chan int
+	c := Name{Name: ident("C"), Type: Chan{Dir: Send | Recv, Elem: Int}}
+	// type D chan<- int
+	d := Name{Name: ident("D"), Type: Chan{Dir: Send, Elem: Int}}
+	golden := []struct {
+		src, dst Type
+		want     bool
+	}{
+		// Identical types.
+		{src: Int, dst: Int, want: true},
+		{src: Int, dst: Int64, want: false},
+		{src: tt, dst: tt, want: true},
+		{src: Byte, dst: Uint8, want: true},
+		{src: Int32, dst: Rune, want: true},
+		{src: Slice{Elem: Uint8}, dst: Slice{Elem: Byte}, want: true},
+		// Identical underlying types.
+		{src: Slice{Elem: Int}, dst: s, want: true},
+		{src: s, dst: Slice{Elem: Int}, want: true},
+		{src: tt, dst: Int, want: false},
+		{src: Int, dst: tt, want: false},
+		{src: tt, dst: u, want: false},
+		// Interface satisfaction.
+		{src: tt, dst: stringer, want: true},
+		{src: Pointer{Base: tt}, dst: stringer, want: true},
+		{src: u, dst: stringer, want: false},
+		{src: Int, dst: stringer, want: false},
+		{src: stringer, dst: Interface{}, want: true},
+		{src: Interface{}, dst: stringer, want: false},
+		{src: Int, dst: Interface{}, want: true},
+		// Channels.
+		{src: Chan{Dir: Send | Recv, Elem: Int}, dst: Chan{Dir: Send, Elem: Int}, want: true},
+		{src: Chan{Dir: Send | Recv, Elem: Int}, dst: Chan{Dir: Recv, Elem: Int}, want: true},
+		{src: Chan{Dir: Send, Elem: Int}, dst: Chan{Dir: Send | Recv, Elem: Int}, want: false},
+		{src: Chan{Dir: Send | Recv, Elem: Int}, dst: Chan{Dir: Send, Elem: Int64}, want: false},
+		{src: c, dst: Chan{Dir: Send, Elem: Int}, want: true},
+		{src: c, dst: d, want: false},
+		// Untyped nil.
+		{src: UntypedNil, dst: Pointer{Base: Int}, want: true},
+		{src: UntypedNil, dst: Func{}, want: true},
+		{src: UntypedNil, dst: s, want: true},
+		{src: UntypedNil, dst: Map{Key: String, Elem: Int}, want: true},
+		{src: UntypedNil, dst: c, want: true},
+		{src: UntypedNil, dst: stringer, want: true},
+		{src: UntypedNil, dst: Int, want: false},
+		{src: UntypedNil, dst: Struct{}, want: false},
+	}
+
+	for i, g := range golden {
+		if got := AssignableTo(g.src, g.dst); got != g.want {
+			t.Errorf("i=%d: AssignableTo(%v, %v) mismatch; expected %v, got %v.", i, g.src, g.dst, g.want, got)
+		}
+	}
+}
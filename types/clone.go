@@ -0,0 +1,83 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/mewlang/go/token"
+)
+
+// CloneType returns a deep copy of the type t, which shares no mutable memory
+// with t; i.e. the slices of fields, parameters, methods and identifiers, and
+// the signatures of methods are copied. Basic types are returned as-is, and
+// the length expressions of array types are shared, as expressions are never
+// mutated in place. CloneType returns nil if t is nil.
+func CloneType(t Type) Type {
+	switch t := t.(type) {
+	case nil:
+		return nil
+	case Basic:
+		return t
+	case Name:
+		return Name{Pkg: t.Pkg, Name: t.Name, Type: CloneType(t.Type), Methods: cloneMethods(t.Methods)}
+	case Array:
+		return Array{Len: t.Len, Elem: CloneType(t.Elem)}
+	case Struct:
+		if t == nil {
+			return t
+		}
+		fields := make(Struct, len(t))
+		for i, field := range t {
+			fields[i] = Field{Names: token.CloneTokens(field.Names), Type: CloneType(field.Type), Tag: field.Tag}
+		}
+		return fields
+	case Pointer:
+		return Pointer{Base: CloneType(t.Base)}
+	case Func:
+		return cloneFunc(t)
+	case Interface:
+		if t == nil {
+			return t
+		}
+		return Interface(cloneMethods(t))
+	case Slice:
+		return Slice{Elem: CloneType(t.Elem)}
+	case Map:
+		return Map{Key: CloneType(t.Key), Elem: CloneType(t.Elem)}
+	case Chan:
+		return Chan{Dir: t.Dir, Elem: CloneType(t.Elem)}
+	}
+	panic(fmt.Sprintf("types.CloneType: unexpected type %T", t))
+}
+
+// cloneFunc returns a deep copy of the provided function signature.
+func cloneFunc(sig Func) Func {
+	return Func{Params: cloneParams(sig.Params), Results: cloneParams(sig.Results), IsVariadic: sig.IsVariadic}
+}
+
+// cloneParams returns a deep copy of the provided parameters.
+func cloneParams(params []Parameter) []Parameter {
+	if params == nil {
+		return nil
+	}
+	clone := make([]Parameter, len(params))
+	for i, param := range params {
+		clone[i] = Parameter{Names: token.CloneTokens(param.Names), Type: CloneType(param.Type)}
+	}
+	return clone
+}
+
+// cloneMethods returns a deep copy of the provided methods.
+func cloneMethods(methods []Method) []Method {
+	if methods == nil {
+		return nil
+	}
+	clone := make([]Method, len(methods))
+	for i, method := range methods {
+		clone[i] = Method{Pkg: method.Pkg, Name: method.Name}
+		if method.Sig != nil {
+			sig := cloneFunc(*method.Sig)
+			clone[i].Sig = &sig
+		}
+	}
+	return clone
+}
@@ -0,0 +1,62 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mewlang/go/token"
+)
+
+func TestCloneType(t *testing.T) {
+	ident := func(val string) token.Token {
+		return token.Token{Kind: token.Ident, Val: val}
+	}
+	// newType returns a new type tree exercising every kind of type.
+	newType := func() Type {
+		return Map{
+			Key: String,
+			Elem: Slice{Elem: Pointer{Base: Name{
+				Name: ident("T"),
+				Type: Struct{
+					{Names: []token.Token{ident("X"), ident("Y")}, Type: Array{Len: token.Token{Kind: token.Ellipsis, Val: "..."}, Elem: Int}},
+					{Type: Name{Pkg: ident("io"), Name: ident("Reader")}, Tag: token.Token{Kind: token.String, Val: `"r"`}},
+					{Names: []token.Token{ident("C")}, Type: Chan{Dir: Recv, Elem: Interface{
+						{Name: ident("M"), Sig: &Func{Params: []Parameter{{Names: []token.Token{ident("a")}, Type: Int}}, Results: []Parameter{{Type: Bool}}}},
+						{Pkg: ident("fmt"), Name: ident("Stringer")},
+					}}},
+				},
+				Methods: []Method{{Name: ident("N"), Sig: &Func{IsVariadic: true, Params: []Parameter{{Type: Slice{Elem: Int}}}}}},
+			}}},
+		}
+	}
+	orig := newType()
+	clone := CloneType(orig)
+	if !reflect.DeepEqual(clone, orig) {
+		t.Fatalf("clone mismatch; expected %v, got %v.", orig, clone)
+	}
+
+	// Mutate the clone through every shared component of the original.
+	name := clone.(Map).Elem.(Slice).Elem.(Pointer).Base.(Name)
+	st := name.Type.(Struct)
+	st[0].Names[0] = ident("Z")
+	st[0].Type = Uint8
+	st[1].Tag.Val = `"w"`
+	iface := st[2].Type.(Chan).Elem.(Interface)
+	iface[0].Sig.Params[0].Names[0] = ident("b")
+	iface[0].Sig.Results[0].Type = String
+	iface[1].Name = ident("GoStringer")
+	name.Methods[0].Sig.IsVariadic = false
+	name.Methods[0].Sig.Params[0].Type = Int
+
+	if want := newType(); !reflect.DeepEqual(orig, want) {
+		t.Errorf("original modified through clone; expected %v, got %v.", want, orig)
+	}
+
+	// Basic types and nil.
+	if got := CloneType(Int); got != Int {
+		t.Errorf("basic type mismatch; expected %v, got %v.", Int, got)
+	}
+	if got := CloneType(nil); got != nil {
+		t.Errorf("nil type mismatch; expected nil, got %v.", got)
+	}
+}
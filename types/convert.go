@@ -0,0 +1,44 @@
+package types
+
+// ConvertibleTo reports whether a non-constant value of type src may be
+// converted to type dst. This is the case if:
+//
+//    - src is assignable to dst.
+//    - src and dst have identical underlying types.
+//    - src and dst are unnamed pointer types and their base types have
+//      identical underlying types.
+//    - src and dst are both integer or floating-point types.
+//    - src and dst are both complex types.
+//    - src is an integer or a slice of bytes or runes and dst is a string type.
+//    - src is a string and dst is a slice of bytes or runes.
+//
+// ref: http://golang.org/ref/spec#Conversions
+func ConvertibleTo(src, dst Type) bool {
+	if AssignableTo(src, dst) {
+		return true
+	}
+	su, du := Underlying(src), Underlying(dst)
+	if su == nil || du == nil {
+		// Unresolved type names.
+		return false
+	}
+	if Identical(su, du) {
+		return true
+	}
+	if sp, ok := src.(Pointer); ok {
+		if dp, ok := dst.(Pointer); ok {
+			return Identical(Underlying(sp.Base), Underlying(dp.Base))
+		}
+	}
+	switch {
+	case (isInteger(src) || isFloat(src)) && (isInteger(dst) || isFloat(dst)):
+		return true
+	case isComplex(src) && isComplex(dst):
+		return true
+	case isString(dst):
+		return isInteger(src) || isByteSlice(src) || isRuneSlice(src)
+	case isString(src):
+		return isByteSlice(dst) || isRuneSlice(dst)
+	}
+	return false
+}
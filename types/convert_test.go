@@ -0,0 +1,72 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/mewlang/go/token"
+)
+
+func TestConvertibleTo(t *testing.T) {
+	ident := func(val string) token.Token {
+		return token.Token{Kind: token.Ident, Val: val}
+	}
+	// type Celsius float64
+	celsius := Name{Name: ident("Celsius"), Type: Float64}
+	// type Fahrenheit float64
+	fahrenheit := Name{Name: ident("Fahrenheit"), Type: Float64}
+	// type Str string
+	str := Name{Name: ident("Str"), Type: String}
+	// type Bytes []byte
+	bytes := Name{Name: ident("Bytes"), Type: Slice{Elem: Byte}}
+	// type P struct { X int }
+	p := Name{Name: ident("P"), Type: Struct{{Names: []token.Token{ident("X")}, Type: Int}}}
+	// type Q struct { X int }
+	q := Name{Name: ident("Q"), Type: Struct{{Names: []token.Token{ident("X")}, Type: Int}}}
+	golden := []struct {
+		src, dst Type
+		want     bool
+	}{
+		// Assignability.
+		{src: Int, dst: Int, want: true},
+		{src: Int, dst: Interface{}, want: true},
+		// Identical underlying types.
+		{src: celsius, dst: fahrenheit, want: true},
+		{src: p, dst: q, want: true},
+		{src: Float64, dst: celsius, want: true},
+		// Pointers with identical underlying base types.
+		{src: Pointer{Base: p}, dst: Pointer{Base: q}, want: true},
+		{src: Pointer{Base: p}, dst: Pointer{Base: Int}, want: false},
+		// Numeric types.
+		{src: Int, dst: Float64, want: true},
+		{src: Float64, dst: Int, want: true},
+		{src: Uint8, dst: Int64, want: true},
+		{src: celsius, dst: Int, want: true},
+		{src: Complex64, dst: Complex128, want: true},
+		{src: Int, dst: Complex128, want: false},
+		{src: Complex128, dst: Float64, want: false},
+		// Strings.
+		{src: String, dst: Slice{Elem: Byte}, want: true},
+		{src: Slice{Elem: Byte}, dst: String, want: true},
+		{src: String, dst: Slice{Elem: Uint8}, want: true},
+		{src: String, dst: Slice{Elem: Rune}, want: true},
+		{src: Slice{Elem: Rune}, dst: String, want: true},
+		{src: str, dst: bytes, want: true},
+		{src: bytes, dst: str, want: true},
+		{src: Int, dst: String, want: true},
+		{src: Rune, dst: str, want: true},
+		{src: String, dst: Int, want: false},
+		{src: Float64, dst: String, want: false},
+		{src: String, dst: Slice{Elem: Int}, want: false},
+		// Illegal conversions.
+		{src: p, dst: Int, want: false},
+		{src: Int, dst: p, want: false},
+		{src: Bool, dst: Int, want: false},
+		{src: Slice{Elem: Int}, dst: Array{Len: token.Token{Kind: token.Int, Val: "1"}, Elem: Int}, want: false},
+	}
+
+	for i, g := range golden {
+		if got := ConvertibleTo(g.src, g.dst); got != g.want {
+			t.Errorf("i=%d: ConvertibleTo(%v, %v) mismatch; expected %v, got %v.", i, g.src, g.dst, g.want, got)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package types
+
+import "github.com/mewlang/go/token"
+
+// A FlatField is a single field of a struct type.
+type FlatField struct {
+	// Field name; the unqualified type name of embedded fields.
+	Name token.Token
+	// Field type.
+	Type Type
+	// Field tag, or NONE.
+	Tag token.Token
+	// Specifies if the field is an embedded field.
+	Embedded bool
+}
+
+// Fields returns the fields of the struct type, one for each field name; e.g.
+// the field declaration "X, Y int" declares the two fields X and Y. Embedded
+// fields are named by their unqualified type name; e.g. the embedded field
+// *io.Reader has the name Reader.
+func (s Struct) Fields() []FlatField {
+	var fields []FlatField
+	for _, f := range s {
+		if len(f.Names) == 0 {
+			name, _ := EmbeddedFieldName(f)
+			fields = append(fields, FlatField{Name: name, Type: f.Type, Tag: f.Tag, Embedded: true})
+			continue
+		}
+		for _, name := range f.Names {
+			fields = append(fields, FlatField{Name: name, Type: f.Type, Tag: f.Tag})
+		}
+	}
+	return fields
+}
+
+// EmbeddedFieldName returns the implicit name of the provided embedded field,
+// and a boolean indicating success. The name of an embedded field is its
+// unqualified type name; e.g. T for the embedded fields T, *T and pkg.T. The
+// boolean is false if the field has explicit names.
+//
+//    AnonymousField = [ "*" ] TypeName .
+//
+// ref: http://golang.org/ref/spec#Struct_types
+func EmbeddedFieldName(f Field) (token.Token, bool) {
+	if len(f.Names) > 0 {
+		return token.Token{}, false
+	}
+	t := f.Type
+	if ptr, ok := t.(Pointer); ok {
+		t = ptr.Base
+	}
+	if name, ok := t.(Name); ok {
+		return name.Name, true
+	}
+	if basic, ok := t.(Basic); ok && basic <= Uintptr {
+		// Predeclared type names, e.g. int or error.
+		return token.Token{Kind: token.Ident, Val: basic.String()}, true
+	}
+	return token.Token{}, false
+}
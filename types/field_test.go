@@ -0,0 +1,80 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mewlang/go/token"
+)
+
+func TestStructFields(t *testing.T) {
+	ident := func(val string) token.Token {
+		return token.Token{Kind: token.Ident, Val: val}
+	}
+	tag := token.Token{Kind: token.String, Val: "`json:\"x\"`"}
+	reader := Name{Pkg: ident("io"), Name: ident("Reader")}
+	// struct {
+	//    X, Y int `json:"x"`
+	//    Z    string
+	//    T
+	//    *io.Reader
+	//    error
+	// }
+	s := Struct{
+		{Names: []token.Token{ident("X"), ident("Y")}, Type: Int, Tag: tag},
+		{Names: []token.Token{ident("Z")}, Type: String},
+		{Type: Name{Name: ident("T")}},
+		{Type: Pointer{Base: reader}},
+		{Type: Error},
+	}
+	want := []FlatField{
+		{Name: ident("X"), Type: Int, Tag: tag},
+		{Name: ident("Y"), Type: Int, Tag: tag},
+		{Name: ident("Z"), Type: String},
+		{Name: ident("T"), Type: Name{Name: ident("T")}, Embedded: true},
+		{Name: ident("Reader"), Type: Pointer{Base: reader}, Embedded: true},
+		{Name: ident("error"), Type: Error, Embedded: true},
+	}
+	got := s.Fields()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("fields mismatch; expected %v, got %v.", want, got)
+	}
+	if fields := (Struct{}).Fields(); fields != nil {
+		t.Errorf("fields mismatch; expected nil, got %v.", fields)
+	}
+}
+
+func TestEmbeddedFieldName(t *testing.T) {
+	ident := func(val string) token.Token {
+		return token.Token{Kind: token.Ident, Val: val}
+	}
+	golden := []struct {
+		f    Field
+		want token.Token
+		ok   bool
+	}{
+		// T
+		{f: Field{Type: Name{Name: ident("T")}}, want: ident("T"), ok: true},
+		// *T
+		{f: Field{Type: Pointer{Base: Name{Name: ident("T")}}}, want: ident("T"), ok: true},
+		// pkg.T
+		{f: Field{Type: Name{Pkg: ident("pkg"), Name: ident("T")}}, want: ident("T"), ok: true},
+		// *pkg.T
+		{f: Field{Type: Pointer{Base: Name{Pkg: ident("pkg"), Name: ident("T")}}}, want: ident("T"), ok: true},
+		// int
+		{f: Field{Type: Int}, want: ident("int"), ok: true},
+		// X T
+		{f: Field{Names: []token.Token{ident("X")}, Type: Name{Name: ident("T")}}},
+		// **T
+		{f: Field{Type: Pointer{Base: Pointer{Base: Name{Name: ident("T")}}}}},
+		// []T
+		{f: Field{Type: Slice{Elem: Name{Name: ident("T")}}}},
+	}
+
+	for i, g := range golden {
+		got, ok := EmbeddedFieldName(g.f)
+		if got != g.want || ok != g.ok {
+			t.Errorf("i=%d: name mismatch; expected (%v, %v), got (%v, %v).", i, g.want, g.ok, got, ok)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package types
+
+import "bytes"
+
+// Arity returns the number of parameters and results of the function
+// signature, and whether the final parameter is variadic. Grouped names are
+// counted individually; e.g. the parameters (a, b int) count as two.
+func (f Func) Arity() (params, results int, variadic bool) {
+	return len(flattenParams(f.Params)), len(flattenParams(f.Results)), f.IsVariadic
+}
+
+// Signature returns the parameter and result types of the function signature,
+// without parameter names; e.g.
+//
+//    (int, int) (string, error)
+//
+// for the function type func(a, b int) (s string, err error).
+func (f Func) Signature() string {
+	sig := Func{
+		Params:     unnamedParams(f.Params),
+		Results:    unnamedParams(f.Results),
+		IsVariadic: f.IsVariadic,
+	}
+	buf := new(bytes.Buffer)
+	writeSignature(&typeWriter{w: buf}, sig)
+	return buf.String()
+}
+
+// unnamedParams returns an unnamed parameter for each parameter of the provided
+// list; e.g. the list (a, b int) has the unnamed parameters (int, int).
+func unnamedParams(params []Parameter) []Parameter {
+	var unnamed []Parameter
+	for _, typ := range flattenParams(params) {
+		unnamed = append(unnamed, Parameter{Type: typ})
+	}
+	return unnamed
+}
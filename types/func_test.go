@@ -0,0 +1,86 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/mewlang/go/token"
+)
+
+func TestFuncArity(t *testing.T) {
+	ident := func(val string) token.Token {
+		return token.Token{Kind: token.Ident, Val: val}
+	}
+	names := func(vals ...string) []token.Token {
+		var names []token.Token
+		for _, val := range vals {
+			names = append(names, ident(val))
+		}
+		return names
+	}
+	golden := []struct {
+		sig      Func
+		params   int
+		results  int
+		variadic bool
+		want     string
+	}{
+		// func()
+		{sig: Func{}, want: "()"},
+		// func(a, b int) (string, error)
+		{
+			sig: Func{
+				Params:  []Parameter{{Names: names("a", "b"), Type: Int}},
+				Results: []Parameter{{Type: String}, {Type: Error}},
+			},
+			params: 2, results: 2, want: "(int, int) (string, error)",
+		},
+		// func(int, string) error
+		{
+			sig: Func{
+				Params:  []Parameter{{Type: Int}, {Type: String}},
+				Results: []Parameter{{Type: Error}},
+			},
+			params: 2, results: 1, want: "(int, string) error",
+		},
+		// func(format string, args ...interface{}) (n int, err error)
+		{
+			sig: Func{
+				Params:     []Parameter{{Names: names("format"), Type: String}, {Names: names("args"), Type: Interface{}}},
+				Results:    []Parameter{{Names: names("n"), Type: Int}, {Names: names("err"), Type: Error}},
+				IsVariadic: true,
+			},
+			params: 2, results: 2, variadic: true, want: "(string, ...interface{}) (int, error)",
+		},
+		// func(...int)
+		{
+			sig:    Func{Params: []Parameter{{Type: Int}}, IsVariadic: true},
+			params: 1, variadic: true, want: "(...int)",
+		},
+		// func(a, b, c float64, s []string) (x, y int)
+		{
+			sig: Func{
+				Params:  []Parameter{{Names: names("a", "b", "c"), Type: Float64}, {Names: names("s"), Type: Slice{Elem: String}}},
+				Results: []Parameter{{Names: names("x", "y"), Type: Int}},
+			},
+			params: 4, results: 2, want: "(float64, float64, float64, []string) (int, int)",
+		},
+		// func(x int) (n int)
+		{
+			sig: Func{
+				Params:  []Parameter{{Names: names("x"), Type: Int}},
+				Results: []Parameter{{Names: names("n"), Type: Int}},
+			},
+			params: 1, results: 1, want: "(int) int",
+		},
+	}
+
+	for i, g := range golden {
+		params, results, variadic := g.sig.Arity()
+		if params != g.params || results != g.results || variadic != g.variadic {
+			t.Errorf("i=%d: arity mismatch; expected (%d, %d, %v), got (%d, %d, %v).", i, g.params, g.results, g.variadic, params, results, variadic)
+		}
+		if got := g.sig.Signature(); got != g.want {
+			t.Errorf("i=%d: signature mismatch; expected %q, got %q.", i, g.want, got)
+		}
+	}
+}
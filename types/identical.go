@@ -0,0 +1,188 @@
+package types
+
+import "github.com/mewlang/go/token"
+
+// Identical reports whether x and y are identical types. The predeclared types
+// byte and rune are aliases for uint8 and int32 respectively, and are identical
+// to them. Two named types are identical if their type names originate in the
+// same type declaration, which is approximated by comparing the package and
+// type names. An unnamed type is never identical to a named type. Two unnamed
+// types are identical if their type literals have the same structure and their
+// corresponding components are identical:
+//
+//    - Two array types are identical if they have identical element types and
+//      the same array length. Array lengths are compared by their source text,
+//      as they are not evaluated; e.g. [4]int and [0x4]int are not considered
+//      identical.
+//    - Two slice types are identical if they have identical element types.
+//    - Two struct types are identical if they have the same sequence of fields,
+//      and if corresponding fields have the same names, identical types, and
+//      identical tags. Two anonymous fields are considered to have the same
+//      name.
+//    - Two pointer types are identical if they have identical base types.
+//    - Two function types are identical if they have the same number of
+//      parameters and result values, corresponding parameter and result types
+//      are identical, and either both functions are variadic or neither is.
+//      Parameter and result names are not required to match.
+//    - Two interface types are identical if they have the same set of methods
+//      with the same names and identical function types. The order of the
+//      methods is irrelevant.
+//    - Two map types are identical if they have identical key and value types.
+//    - Two channel types are identical if they have identical value types and
+//      the same direction.
+//
+// ref: http://golang.org/ref/spec#Type_identity
+func Identical(x, y Type) bool {
+	switch x := x.(type) {
+	case Basic:
+		y, ok := y.(Basic)
+		return ok && unalias(x) == unalias(y)
+	case Name:
+		y, ok := y.(Name)
+		return ok && x.Pkg.Val == y.Pkg.Val && x.Name.Val == y.Name.Val
+	case Array:
+		y, ok := y.(Array)
+		return ok && lenString(x.Len) == lenString(y.Len) && Identical(x.Elem, y.Elem)
+	case Struct:
+		y, ok := y.(Struct)
+		if !ok {
+			return false
+		}
+		xs, ys := flattenFields(x), flattenFields(y)
+		if len(xs) != len(ys) {
+			return false
+		}
+		for i := range xs {
+			xf, yf := xs[i], ys[i]
+			if xf.name.Val != yf.name.Val || xf.tag.Val != yf.tag.Val || !Identical(xf.typ, yf.typ) {
+				return false
+			}
+		}
+		return true
+	case Pointer:
+		y, ok := y.(Pointer)
+		return ok && Identical(x.Base, y.Base)
+	case Func:
+		y, ok := y.(Func)
+		return ok && identicalSigs(x, y)
+	case Interface:
+		y, ok := y.(Interface)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		ms := make(map[string]Method, len(y))
+		for _, m := range y {
+			ms[methodKey(m)] = m
+		}
+		for _, xm := range x {
+			ym, ok := ms[methodKey(xm)]
+			if !ok {
+				return false
+			}
+			if (xm.Sig == nil) != (ym.Sig == nil) {
+				return false
+			}
+			if xm.Sig != nil && !identicalSigs(*xm.Sig, *ym.Sig) {
+				return false
+			}
+		}
+		return true
+	case Slice:
+		y, ok := y.(Slice)
+		return ok && Identical(x.Elem, y.Elem)
+	case Map:
+		y, ok := y.(Map)
+		return ok && Identical(x.Key, y.Key) && Identical(x.Elem, y.Elem)
+	case Chan:
+		y, ok := y.(Chan)
+		return ok && x.Dir == y.Dir && Identical(x.Elem, y.Elem)
+	}
+	return false
+}
+
+// unalias returns the basic type denoted by the alias b; i.e. uint8 for byte
+// and int32 for rune. Every other basic type is returned unchanged.
+func unalias(b Basic) Basic {
+	switch b {
+	case Byte:
+		return Uint8
+	case Rune:
+		return Int32
+	}
+	return b
+}
+
+// identicalSigs reports whether the function signatures x and y are identical.
+func identicalSigs(x, y Func) bool {
+	if x.IsVariadic != y.IsVariadic {
+		return false
+	}
+	return identicalParams(x.Params, y.Params) && identicalParams(x.Results, y.Results)
+}
+
+// identicalParams reports whether the parameter lists x and y have the same
+// number of parameters with identical types. Parameter names are ignored.
+func identicalParams(x, y []Parameter) bool {
+	xs, ys := flattenParams(x), flattenParams(y)
+	if len(xs) != len(ys) {
+		return false
+	}
+	for i := range xs {
+		if !Identical(xs[i], ys[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// flattenParams returns the type of each parameter of the provided list; e.g.
+// the list (a, b int, c string) has the types [int, int, string].
+func flattenParams(params []Parameter) []Type {
+	var types []Type
+	for _, param := range params {
+		n := len(param.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			types = append(types, param.Type)
+		}
+	}
+	return types
+}
+
+// A field is a single named or anonymous field of a struct type.
+type field struct {
+	// Field name, or NONE for anonymous fields.
+	name token.Token
+	// Field type.
+	typ Type
+	// Field tag, or NONE.
+	tag token.Token
+}
+
+// flattenFields returns the sequence of fields of the provided struct type; e.g.
+// the field declaration "X, Y int" declares the two fields X and Y.
+func flattenFields(t Struct) []field {
+	var fields []field
+	for _, f := range t {
+		if len(f.Names) == 0 {
+			fields = append(fields, field{typ: f.Type, tag: f.Tag})
+			continue
+		}
+		for _, name := range f.Names {
+			fields = append(fields, field{name: name, typ: f.Type, tag: f.Tag})
+		}
+	}
+	return fields
+}
+
+// methodKey returns a key which uniquely identifies the provided method or
+// embedded interface of an interface type.
+func methodKey(m Method) string {
+	if m.Sig == nil {
+		// Embedded interface.
+		return m.Pkg.Val + "." + m.Name.Val
+	}
+	return m.Name.Val
+}
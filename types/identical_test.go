@@ -0,0 +1,104 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/mewlang/go/token"
+)
+
+func TestIdentical(t *testing.T) {
+	ident := func(val string) token.Token {
+		return token.Token{Kind: token.Ident, Val: val}
+	}
+	names := func(vals ...string) []token.Token {
+		var toks []token.Token
+		for _, val := range vals {
+			toks = append(toks, ident(val))
+		}
+		return toks
+	}
+	tag := func(val string) token.Token {
+		return token.Token{Kind: token.String, Val: val}
+	}
+	golden := []struct {
+		x, y Type
+		want bool
+	}{
+		// Basic and named types.
+		{x: Int, y: Int, want: true},
+		{x: Int, y: Int32, want: false},
+		{x: Byte, y: Uint8, want: true},
+		{x: Uint8, y: Byte, want: true},
+		{x: Rune, y: Int32, want: true},
+		{x: Int32, y: Rune, want: true},
+		{x: Byte, y: Int8, want: false},
+		{x: Rune, y: Uint32, want: false},
+		{x: Slice{Elem: Byte}, y: Slice{Elem: Uint8}, want: true},
+		{x: Name{Name: ident("T")}, y: Name{Name: token.Token{Kind: token.Ident, Val: "T", Line: 3, Col: 7}}, want: true},
+		{x: Name{Name: ident("T")}, y: Name{Name: ident("U")}, want: false},
+		{x: Name{Pkg: ident("io"), Name: ident("Reader")}, y: Name{Name: ident("Reader")}, want: false},
+		{x: Name{Name: ident("T"), Type: Slice{Elem: Int}}, y: Slice{Elem: Int}, want: false},
+		// Arrays and slices.
+		{x: Array{Len: token.Token{Kind: token.Int, Val: "4"}, Elem: Int}, y: Array{Len: token.Token{Kind: token.Int, Val: "4"}, Elem: Int}, want: true},
+		{x: Array{Len: token.Token{Kind: token.Int, Val: "4"}, Elem: Int}, y: Array{Len: token.Token{Kind: token.Int, Val: "5"}, Elem: Int}, want: false},
+		{x: Array{Len: token.Token{Kind: token.Int, Val: "4"}, Elem: Int}, y: Slice{Elem: Int}, want: false},
+		// Array lengths are compared by source text.
+		{x: Array{Len: token.Token{Kind: token.Int, Val: "4"}, Elem: Int}, y: Array{Len: token.Token{Kind: token.Int, Val: "0x4"}, Elem: Int}, want: false},
+		{x: Slice{Elem: Slice{Elem: String}}, y: Slice{Elem: Slice{Elem: String}}, want: true},
+		// Structs.
+		{
+			x:    Struct{{Names: names("X", "Y"), Type: Int, Tag: tag(`"a"`)}, {Type: Pointer{Base: Name{Name: ident("T")}}}},
+			y:    Struct{{Names: names("X"), Type: Int, Tag: tag(`"a"`)}, {Names: names("Y"), Type: Int, Tag: tag(`"a"`)}, {Type: Pointer{Base: Name{Name: ident("T")}}}},
+			want: true,
+		},
+		{x: Struct{{Names: names("X", "Y"), Type: Int}}, y: Struct{{Names: names("Y", "X"), Type: Int}}, want: false},
+		{x: Struct{{Names: names("X"), Type: Int}}, y: Struct{{Names: names("X"), Type: Int64}}, want: false},
+		{x: Struct{{Names: names("X"), Type: Int, Tag: tag(`"a"`)}}, y: Struct{{Names: names("X"), Type: Int}}, want: false},
+		{x: Struct{{Names: names("T"), Type: Name{Name: ident("T")}}}, y: Struct{{Type: Name{Name: ident("T")}}}, want: false},
+		{x: Struct{{Names: names("X"), Type: Int}}, y: Struct{{Names: names("X"), Type: Int}, {Names: names("Y"), Type: Int}}, want: false},
+		// Functions.
+		{
+			x:    Func{Params: []Parameter{{Names: names("a", "b"), Type: Int}}, Results: []Parameter{{Type: Error}}},
+			y:    Func{Params: []Parameter{{Type: Int}, {Type: Int}}, Results: []Parameter{{Names: names("err"), Type: Error}}},
+			want: true,
+		},
+		{
+			x:    Func{Params: []Parameter{{Type: Int}, {Type: String}}, IsVariadic: true},
+			y:    Func{Params: []Parameter{{Type: Int}, {Type: String}}},
+			want: false,
+		},
+		{x: Func{Params: []Parameter{{Type: Int}}}, y: Func{Results: []Parameter{{Type: Int}}}, want: false},
+		// Interfaces.
+		{
+			x: Interface{
+				{Name: ident("M"), Sig: &Func{}},
+				{Name: ident("N"), Sig: &Func{Results: []Parameter{{Type: Int}}}},
+			},
+			y: Interface{
+				{Name: ident("N"), Sig: &Func{Results: []Parameter{{Type: Int}}}},
+				{Name: ident("M"), Sig: &Func{}},
+			},
+			want: true,
+		},
+		{x: Interface{{Name: ident("M"), Sig: &Func{}}}, y: Interface{{Name: ident("M"), Sig: &Func{Params: []Parameter{{Type: Int}}}}}, want: false},
+		{x: Interface{{Name: ident("M"), Sig: &Func{}}}, y: Interface{{Name: ident("N"), Sig: &Func{}}}, want: false},
+		{x: Interface{{Pkg: ident("io"), Name: ident("Reader")}}, y: Interface{{Pkg: ident("io"), Name: ident("Reader")}}, want: true},
+		{x: Interface{}, y: Interface{}, want: true},
+		// Maps, pointers and channels.
+		{x: Map{Key: String, Elem: Int}, y: Map{Key: String, Elem: Int}, want: true},
+		{x: Map{Key: String, Elem: Int}, y: Map{Key: Int, Elem: Int}, want: false},
+		{x: Pointer{Base: Int}, y: Pointer{Base: Int}, want: true},
+		{x: Chan{Dir: Send | Recv, Elem: Int}, y: Chan{Dir: Send | Recv, Elem: Int}, want: true},
+		{x: Chan{Dir: Send, Elem: Int}, y: Chan{Dir: Send | Recv, Elem: Int}, want: false},
+		{x: Chan{Dir: Recv, Elem: Int}, y: Chan{Dir: Send, Elem: Int}, want: false},
+	}
+
+	for i, g := range golden {
+		if got := Identical(g.x, g.y); got != g.want {
+			t.Errorf("i=%d: Identical(%v, %v) mismatch; expected %v, got %v.", i, g.x, g.y, g.want, got)
+		}
+		if got := Identical(g.y, g.x); got != g.want {
+			t.Errorf("i=%d: Identical(%v, %v) mismatch; expected %v, got %v.", i, g.y, g.x, g.want, got)
+		}
+	}
+}
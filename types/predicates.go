@@ -0,0 +1,89 @@
+package types
+
+// isInteger reports whether the underlying type of t is an integer type.
+func isInteger(t Type) bool {
+	switch Underlying(t) {
+	case Byte, Int, Int8, Int16, Int32, Int64, Rune, Uint, Uint8, Uint16, Uint32, Uint64, Uintptr:
+		return true
+	}
+	return false
+}
+
+// isFloat reports whether the underlying type of t is a floating-point type.
+func isFloat(t Type) bool {
+	switch Underlying(t) {
+	case Float32, Float64:
+		return true
+	}
+	return false
+}
+
+// isComplex reports whether the underlying type of t is a complex type.
+func isComplex(t Type) bool {
+	switch Underlying(t) {
+	case Complex64, Complex128:
+		return true
+	}
+	return false
+}
+
+// isString reports whether the underlying type of t is a string type.
+func isString(t Type) bool {
+	return Underlying(t) == String
+}
+
+// isByteSlice reports whether the underlying type of t is a slice of bytes.
+func isByteSlice(t Type) bool {
+	if s, ok := Underlying(t).(Slice); ok {
+		switch Underlying(s.Elem) {
+		case Byte, Uint8:
+			return true
+		}
+	}
+	return false
+}
+
+// isRuneSlice reports whether the underlying type of t is a slice of runes.
+func isRuneSlice(t Type) bool {
+	if s, ok := Underlying(t).(Slice); ok {
+		switch Underlying(s.Elem) {
+		case Rune, Int32:
+			return true
+		}
+	}
+	return false
+}
+
+// Comparable reports whether values of type t may be compared using the == and
+// != operators. Booleans, numbers, strings, pointers, channels and interfaces
+// are comparable. Structs are comparable if all their fields are comparable and
+// arrays are comparable if their element type is comparable. Slices, maps and
+// functions are not comparable.
+//
+// ref: http://golang.org/ref/spec#Comparison_operators
+func Comparable(t Type) bool {
+	switch u := Underlying(t).(type) {
+	case Basic:
+		return u != UntypedNil
+	case Pointer, Chan, Interface:
+		return true
+	case Struct:
+		for _, field := range u {
+			if !Comparable(field.Type) {
+				return false
+			}
+		}
+		return true
+	case Array:
+		return Comparable(u.Elem)
+	}
+	return false
+}
+
+// Ordered reports whether values of type t may be compared using the <, <=, >
+// and >= operators. Integers, floating-point numbers and strings are ordered.
+//
+// ref: http://golang.org/ref/spec#Comparison_operators
+func Ordered(t Type) bool {
+	return isInteger(t) || isFloat(t) || isString(t)
+}
@@ -0,0 +1,74 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/mewlang/go/token"
+)
+
+func TestComparable(t *testing.T) {
+	ident := func(val string) token.Token {
+		return token.Token{Kind: token.Ident, Val: val}
+	}
+	field := func(name string, typ Type) Field {
+		return Field{Names: []token.Token{ident(name)}, Type: typ}
+	}
+	length := token.Token{Kind: token.Int, Val: "4"}
+	golden := []struct {
+		typ  Type
+		want bool
+	}{
+		{typ: Bool, want: true},
+		{typ: Int, want: true},
+		{typ: Complex128, want: true},
+		{typ: String, want: true},
+		{typ: Error, want: true},
+		{typ: Pointer{Base: Slice{Elem: Int}}, want: true},
+		{typ: Chan{Dir: Send | Recv, Elem: Int}, want: true},
+		{typ: Interface{}, want: true},
+		{typ: Name{Name: ident("T"), Type: Int}, want: true},
+		{typ: Struct{}, want: true},
+		{typ: Struct{field("X", Int), field("Y", String)}, want: true},
+		{typ: Struct{field("X", Int), field("Y", Slice{Elem: Int})}, want: false},
+		{typ: Struct{field("S", Struct{field("M", Map{Key: String, Elem: Int})})}, want: false},
+		{typ: Array{Len: length, Elem: Int}, want: true},
+		{typ: Array{Len: length, Elem: Func{}}, want: false},
+		{typ: Slice{Elem: Int}, want: false},
+		{typ: Map{Key: String, Elem: Int}, want: false},
+		{typ: Func{}, want: false},
+		{typ: Name{Name: ident("S"), Type: Slice{Elem: Byte}}, want: false},
+		// Unresolved type name.
+		{typ: Name{Name: ident("U")}, want: false},
+	}
+
+	for i, g := range golden {
+		if got := Comparable(g.typ); got != g.want {
+			t.Errorf("i=%d: Comparable(%v) mismatch; expected %v, got %v.", i, g.typ, g.want, got)
+		}
+	}
+}
+
+func TestOrdered(t *testing.T) {
+	golden := []struct {
+		typ  Type
+		want bool
+	}{
+		{typ: Int, want: true},
+		{typ: Uint8, want: true},
+		{typ: Rune, want: true},
+		{typ: Float32, want: true},
+		{typ: String, want: true},
+		{typ: Name{Name: token.Token{Kind: token.Ident, Val: "Celsius"}, Type: Float64}, want: true},
+		{typ: Bool, want: false},
+		{typ: Complex64, want: false},
+		{typ: Pointer{Base: Int}, want: false},
+		{typ: Interface{}, want: false},
+		{typ: Struct{}, want: false},
+	}
+
+	for i, g := range golden {
+		if got := Ordered(g.typ); got != g.want {
+			t.Errorf("i=%d: Ordered(%v) mismatch; expected %v, got %v.", i, g.typ, g.want, got)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package types
+
+import (
+	"math"
+	"strconv"
+)
+
+// An Arch specifies the word size of a target architecture, which determines
+// the size and alignment of types.
+type Arch uint8
+
+// Target architectures.
+const (
+	// Arch32 specifies architectures with 32-bit words; e.g. 386 and arm.
+	Arch32 Arch = iota + 1
+	// Arch64 specifies architectures with 64-bit words; e.g. amd64 and arm64.
+	Arch64
+)
+
+// wordSize returns the word size in bytes of the architecture.
+func (arch Arch) wordSize() int64 {
+	if arch == Arch32 {
+		return 4
+	}
+	return 8
+}
+
+// Sizeof returns the size and alignment in bytes of values of type t on the
+// provided architecture, as laid out by the gc compiler. The alignment of a
+// basic type is its size, capped at the word size; the alignment of a complex
+// type is that of its components. Strings, slices and interfaces consist of two
+// or three words, and pointers, functions, maps and channels of a single word.
+//
+// The fields of a struct are laid out in order, each at the first offset which
+// is a multiple of its alignment; the alignment of a struct is the largest
+// alignment of its fields and its size is rounded up to a multiple thereof. The
+// size of an array is its length times the size of its element type.
+//
+// ok is false if t is incomplete; i.e. if it contains an unresolved type name,
+// the type of nil, or an array whose length is not an integer literal.
+//
+// ref: http://golang.org/ref/spec#Size_and_alignment_guarantees
+func Sizeof(t Type, arch Arch) (size, align int64, ok bool) {
+	word := arch.wordSize()
+	switch t := t.(type) {
+	case Basic:
+		switch t {
+		case Bool, Byte, Int8, Uint8:
+			return 1, 1, true
+		case Int16, Uint16:
+			return 2, 2, true
+		case Float32, Int32, Rune, Uint32:
+			return 4, 4, true
+		case Complex64:
+			return 8, 4, true
+		case Float64, Int64, Uint64:
+			return 8, min64(8, word), true
+		case Complex128:
+			return 16, min64(8, word), true
+		case Int, Uint, Uintptr:
+			return word, word, true
+		case String, Error:
+			return 2 * word, word, true
+		}
+		// UntypedNil.
+		return 0, 0, false
+	case Name:
+		if t.Type == nil {
+			return 0, 0, false
+		}
+		return Sizeof(t.Type, arch)
+	case Array:
+		n, err := strconv.ParseInt(lenString(t.Len), 0, 64)
+		if err != nil || n < 0 {
+			return 0, 0, false
+		}
+		size, align, ok := Sizeof(t.Elem, arch)
+		if !ok || (size > 0 && n > math.MaxInt64/size) {
+			return 0, 0, false
+		}
+		return n * size, align, true
+	case Struct:
+		size, align := int64(0), int64(1)
+		for _, field := range t {
+			fsize, falign, ok := Sizeof(field.Type, arch)
+			if !ok {
+				return 0, 0, false
+			}
+			n := len(field.Names)
+			if n == 0 {
+				// Anonymous field.
+				n = 1
+			}
+			for i := 0; i < n; i++ {
+				size = alignUp(size, falign) + fsize
+			}
+			if falign > align {
+				align = falign
+			}
+		}
+		return alignUp(size, align), align, true
+	case Interface:
+		return 2 * word, word, true
+	case Slice:
+		return 3 * word, word, true
+	case Pointer, Func, Map, Chan:
+		return word, word, true
+	}
+	return 0, 0, false
+}
+
+// alignUp rounds x up to a multiple of align.
+func alignUp(x, align int64) int64 {
+	return (x + align - 1) / align * align
+}
+
+// min64 returns the smaller of x and y.
+func min64(x, y int64) int64 {
+	if x < y {
+		return x
+	}
+	return y
+}
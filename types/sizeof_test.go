@@ -0,0 +1,99 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/mewlang/go/token"
+)
+
+func TestSizeof(t *testing.T) {
+	ident := func(val string) token.Token {
+		return token.Token{Kind: token.Ident, Val: val}
+	}
+	names := func(vals ...string) []token.Token {
+		var toks []token.Token
+		for _, val := range vals {
+			toks = append(toks, ident(val))
+		}
+		return toks
+	}
+	length := func(val string) token.Token {
+		return token.Token{Kind: token.Int, Val: val}
+	}
+	// Layout of the following struct, which on amd64 has the unsafe.Sizeof 48
+	// and the unsafe.Alignof 8; the unsafe.Offsetof of its fields is 0, 8, 16,
+	// 20, 22, 24 and 40 respectively. On 386, its unsafe.Sizeof is 32.
+	//
+	//    struct {
+	//       a    bool
+	//       b    int64
+	//       c    [3]byte
+	//       d, e int16
+	//       f    string
+	//       g    *int
+	//    }
+	layout := Struct{
+		{Names: names("a"), Type: Bool},
+		{Names: names("b"), Type: Int64},
+		{Names: names("c"), Type: Array{Len: length("3"), Elem: Byte}},
+		{Names: names("d", "e"), Type: Int16},
+		{Names: names("f"), Type: String},
+		{Names: names("g"), Type: Pointer{Base: Int}},
+	}
+	golden := []struct {
+		t               Type
+		size, align     int64
+		size32, align32 int64
+	}{
+		// Basic types.
+		{t: Bool, size: 1, align: 1, size32: 1, align32: 1},
+		{t: Int16, size: 2, align: 2, size32: 2, align32: 2},
+		{t: Rune, size: 4, align: 4, size32: 4, align32: 4},
+		{t: Int, size: 8, align: 8, size32: 4, align32: 4},
+		{t: Uint64, size: 8, align: 8, size32: 8, align32: 4},
+		{t: Float64, size: 8, align: 8, size32: 8, align32: 4},
+		{t: Complex64, size: 8, align: 4, size32: 8, align32: 4},
+		{t: Complex128, size: 16, align: 8, size32: 16, align32: 4},
+		{t: String, size: 16, align: 8, size32: 8, align32: 4},
+		{t: Error, size: 16, align: 8, size32: 8, align32: 4},
+		// Named types.
+		{t: Name{Name: ident("T"), Type: Uint16}, size: 2, align: 2, size32: 2, align32: 2},
+		// Composite types.
+		{t: Pointer{Base: layout}, size: 8, align: 8, size32: 4, align32: 4},
+		{t: Func{}, size: 8, align: 8, size32: 4, align32: 4},
+		{t: Map{Key: String, Elem: Int}, size: 8, align: 8, size32: 4, align32: 4},
+		{t: Chan{Dir: BothDir, Elem: Int}, size: 8, align: 8, size32: 4, align32: 4},
+		{t: Slice{Elem: Int}, size: 24, align: 8, size32: 12, align32: 4},
+		{t: Interface{}, size: 16, align: 8, size32: 8, align32: 4},
+		{t: Array{Len: length("5"), Elem: Int32}, size: 20, align: 4, size32: 20, align32: 4},
+		{t: Array{Len: length("0x10"), Elem: Int}, size: 128, align: 8, size32: 64, align32: 4},
+		{t: Array{Len: length("0"), Elem: Int64}, size: 0, align: 8, size32: 0, align32: 4},
+		// Structs.
+		{t: layout, size: 48, align: 8, size32: 32, align32: 4},
+		{t: Struct{}, size: 0, align: 1, size32: 0, align32: 1},
+		{t: Struct{{Names: names("a"), Type: Int64}, {Names: names("b"), Type: Bool}}, size: 16, align: 8, size32: 12, align32: 4},
+		{t: Struct{{Names: names("a", "b"), Type: Byte}, {Names: names("c"), Type: Int32}}, size: 8, align: 4, size32: 8, align32: 4},
+		{t: Struct{{Type: Name{Name: ident("T"), Type: Complex64}}, {Names: names("b"), Type: Byte}}, size: 12, align: 4, size32: 12, align32: 4},
+		// Incomplete types.
+		{t: UntypedNil},
+		{t: Name{Name: ident("T")}},
+		{t: Array{Len: token.Token{Kind: token.Ellipsis, Val: "..."}, Elem: Int}},
+		{t: Array{Len: ident("n"), Elem: Int}},
+		{t: Array{Len: length("0x7fffffffffffffff"), Elem: Int16}},
+		{t: Struct{{Names: names("a"), Type: Int}, {Names: names("b"), Type: Name{Name: ident("T")}}}},
+		{t: nil},
+	}
+
+	for i, g := range golden {
+		// Incomplete types have a zero alignment in the table.
+		want := g.align != 0
+		size, align, ok := Sizeof(g.t, Arch64)
+		if size != g.size || align != g.align || ok != want {
+			t.Errorf("i=%d: 64-bit size mismatch; expected (%d, %d, %v), got (%d, %d, %v).", i, g.size, g.align, want, size, align, ok)
+		}
+		size, align, ok = Sizeof(g.t, Arch32)
+		if size != g.size32 || align != g.align32 || ok != want {
+			t.Errorf("i=%d: 32-bit size mismatch; expected (%d, %d, %v), got (%d, %d, %v).", i, g.size32, g.align32, want, size, align, ok)
+		}
+	}
+}
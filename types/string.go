@@ -0,0 +1,266 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/mewlang/go/token"
+)
+
+// basicNames specifies the predeclared name of each basic type.
+var basicNames = [...]string{
+	Bool:       "bool",
+	Byte:       "byte",
+	Complex64:  "complex64",
+	Complex128: "complex128",
+	Error:      "error",
+	Float32:    "float32",
+	Float64:    "float64",
+	Int:        "int",
+	Int8:       "int8",
+	Int16:      "int16",
+	Int32:      "int32",
+	Int64:      "int64",
+	Rune:       "rune",
+	String:     "string",
+	Uint:       "uint",
+	Uint8:      "uint8",
+	Uint16:     "uint16",
+	Uint32:     "uint32",
+	Uint64:     "uint64",
+	Uintptr:    "uintptr",
+	UntypedNil: "untyped nil",
+}
+
+// String returns the predeclared name of the basic type.
+func (t Basic) String() string {
+	if int(t) < len(basicNames) {
+		return basicNames[t]
+	}
+	return fmt.Sprintf("Basic(%d)", uint8(t))
+}
+
+// basics maps from the predeclared name of each basic type to the basic type.
+// The aliases byte and rune have distinct entries from uint8 and int32.
+var basics = make(map[string]Basic, len(basicNames))
+
+func init() {
+	for t := Bool; t <= Uintptr; t++ {
+		basics[basicNames[t]] = t
+	}
+}
+
+// LookupBasic returns the basic type of the provided predeclared type name, and
+// a boolean indicating success.
+func LookupBasic(name string) (Basic, bool) {
+	t, ok := basics[name]
+	return t, ok
+}
+
+// String returns the Go syntax representation of the type; e.g.
+// map[string][]int.
+func (t Name) String() string      { return typeString(t) }
+func (t Array) String() string     { return typeString(t) }
+func (t Struct) String() string    { return typeString(t) }
+func (t Pointer) String() string   { return typeString(t) }
+func (t Func) String() string      { return typeString(t) }
+func (t Interface) String() string { return typeString(t) }
+func (t Slice) String() string     { return typeString(t) }
+func (t Map) String() string       { return typeString(t) }
+func (t Chan) String() string      { return typeString(t) }
+
+// typeString returns the Go syntax representation of the provided type.
+func typeString(t Type) string {
+	buf := new(bytes.Buffer)
+	WriteType(buf, t)
+	return buf.String()
+}
+
+// WriteType writes the Go syntax representation of the provided type to w. The
+// tags of struct fields are written as raw string literals when possible.
+func WriteType(w io.Writer, t Type) error {
+	tw := &typeWriter{w: w}
+	writeType(tw, t)
+	return tw.err
+}
+
+// A typeWriter writes strings to an underlying writer, and records the first
+// error encountered; once an error has occurred, subsequent writes are no-ops.
+type typeWriter struct {
+	// Underlying writer.
+	w io.Writer
+	// First write error, or nil.
+	err error
+}
+
+// WriteString writes s to the underlying writer, unless a previous write has
+// failed.
+func (w *typeWriter) WriteString(s string) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = io.WriteString(w.w, s)
+}
+
+// writeType writes the Go syntax representation of the provided type to w.
+func writeType(w *typeWriter, t Type) {
+	switch t := t.(type) {
+	case nil:
+		w.WriteString("<nil>")
+	case Basic:
+		w.WriteString(t.String())
+	case Name:
+		if t.Pkg.Kind != token.None {
+			w.WriteString(t.Pkg.Val)
+			w.WriteString(".")
+		}
+		w.WriteString(t.Name.Val)
+	case Array:
+		w.WriteString("[")
+		w.WriteString(lenString(t.Len))
+		w.WriteString("]")
+		writeType(w, t.Elem)
+	case Struct:
+		w.WriteString("struct{")
+		for i, field := range t {
+			if i > 0 {
+				w.WriteString("; ")
+			}
+			if len(field.Names) > 0 {
+				writeNames(w, field.Names)
+				w.WriteString(" ")
+			}
+			writeType(w, field.Type)
+			if field.Tag.Kind != token.None {
+				w.WriteString(" ")
+				w.WriteString(tagString(field.Tag))
+			}
+		}
+		w.WriteString("}")
+	case Pointer:
+		w.WriteString("*")
+		writeType(w, t.Base)
+	case Func:
+		w.WriteString("func")
+		writeSignature(w, t)
+	case Interface:
+		w.WriteString("interface{")
+		for i, method := range t {
+			if i > 0 {
+				w.WriteString("; ")
+			}
+			if method.Sig == nil {
+				// Embedded interface.
+				writeType(w, Name{Pkg: method.Pkg, Name: method.Name})
+				continue
+			}
+			w.WriteString(method.Name.Val)
+			writeSignature(w, *method.Sig)
+		}
+		w.WriteString("}")
+	case Slice:
+		w.WriteString("[]")
+		writeType(w, t.Elem)
+	case Map:
+		w.WriteString("map[")
+		writeType(w, t.Key)
+		w.WriteString("]")
+		writeType(w, t.Elem)
+	case Chan:
+		parens := false
+		switch t.Dir {
+		case Send, Recv:
+			w.WriteString(t.Dir.String())
+			w.WriteString(" ")
+		default:
+			w.WriteString("chan ")
+			// The element type of a bidirectional channel requires parentheses if
+			// it is a receive-only channel; i.e. chan (<-chan int).
+			if elem, ok := t.Elem.(Chan); ok && elem.Dir == Recv {
+				parens = true
+			}
+		}
+		if parens {
+			w.WriteString("(")
+		}
+		writeType(w, t.Elem)
+		if parens {
+			w.WriteString(")")
+		}
+	default:
+		panic(fmt.Sprintf("types.writeType: unexpected type %T", t))
+	}
+}
+
+// tagString returns the provided struct field tag as a raw string literal, if
+// the value of the tag can be represented as such.
+func tagString(tag token.Token) string {
+	if tag.Kind == token.String {
+		if s, err := tag.Unquote(); err == nil && strconv.CanBackquote(s) {
+			return "`" + s + "`"
+		}
+	}
+	return tag.Val
+}
+
+// lenString returns the source text of the provided array length, which is
+// either a constant expression or an ellipsis.
+func lenString(n interface{}) string {
+	switch n := n.(type) {
+	case token.Token:
+		return n.Val
+	case fmt.Stringer:
+		return n.String()
+	}
+	return fmt.Sprint(n)
+}
+
+// writeSignature writes the parameters and results of the provided function
+// signature to w.
+func writeSignature(w *typeWriter, sig Func) {
+	writeParams(w, sig.Params, sig.IsVariadic)
+	switch {
+	case len(sig.Results) == 0:
+		// No results.
+	case len(sig.Results) == 1 && len(sig.Results[0].Names) == 0:
+		// Single unnamed result.
+		w.WriteString(" ")
+		writeType(w, sig.Results[0].Type)
+	default:
+		w.WriteString(" ")
+		writeParams(w, sig.Results, false)
+	}
+}
+
+// writeParams writes the parenthesized list of parameters or results to w.
+// The type of the final parameter is prefixed with an ellipsis if isVariadic is
+// set.
+func writeParams(w *typeWriter, params []Parameter, isVariadic bool) {
+	w.WriteString("(")
+	for i, param := range params {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		if len(param.Names) > 0 {
+			writeNames(w, param.Names)
+			w.WriteString(" ")
+		}
+		if isVariadic && i == len(params)-1 {
+			w.WriteString("...")
+		}
+		writeType(w, param.Type)
+	}
+	w.WriteString(")")
+}
+
+// writeNames writes the comma-separated list of names to w.
+func writeNames(w *typeWriter, names []token.Token) {
+	for i, name := range names {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		w.WriteString(name.Val)
+	}
+}
@@ -0,0 +1,180 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mewlang/go/token"
+)
+
+func TestTypeString(t *testing.T) {
+	ident := func(val string) token.Token {
+		return token.Token{Kind: token.Ident, Val: val}
+	}
+	name := func(val string) Name {
+		return Name{Name: ident(val)}
+	}
+	golden := []struct {
+		typ  Type
+		want string
+	}{
+		{typ: Int, want: "int"},
+		{typ: Uintptr, want: "uintptr"},
+		{typ: name("T"), want: "T"},
+		{typ: Name{Pkg: ident("io"), Name: ident("Reader")}, want: "io.Reader"},
+		{typ: Array{Len: token.Token{Kind: token.Int, Val: "16"}, Elem: Byte}, want: "[16]byte"},
+		{typ: Array{Len: token.Token{Kind: token.Ellipsis, Val: "..."}, Elem: String}, want: "[...]string"},
+		{typ: Pointer{Base: name("T")}, want: "*T"},
+		{typ: Slice{Elem: Slice{Elem: Int}}, want: "[][]int"},
+		{typ: Map{Key: String, Elem: Slice{Elem: Int}}, want: "map[string][]int"},
+		{typ: Chan{Dir: Send | Recv, Elem: Int}, want: "chan int"},
+		{typ: Chan{Dir: Send, Elem: Bool}, want: "chan<- bool"},
+		{typ: Chan{Dir: Recv, Elem: Int}, want: "<-chan int"},
+		{typ: Chan{Dir: Send | Recv, Elem: Chan{Dir: Recv, Elem: Int}}, want: "chan (<-chan int)"},
+		{typ: Chan{Dir: Send, Elem: Chan{Dir: Recv, Elem: Int}}, want: "chan<- <-chan int"},
+		{typ: Func{}, want: "func()"},
+		{
+			typ: Func{
+				Params:     []Parameter{{Type: Int}, {Type: String}},
+				Results:    []Parameter{{Type: Error}},
+				IsVariadic: true,
+			},
+			want: "func(int, ...string) error",
+		},
+		{
+			typ: Func{
+				Params:  []Parameter{{Names: []token.Token{ident("a"), ident("b")}, Type: Int}},
+				Results: []Parameter{{Names: []token.Token{ident("n")}, Type: Int}, {Names: []token.Token{ident("err")}, Type: Error}},
+			},
+			want: "func(a, b int) (n int, err error)",
+		},
+		{typ: Struct{}, want: "struct{}"},
+		{
+			typ: Struct{
+				{Names: []token.Token{ident("X"), ident("Y")}, Type: Int, Tag: token.Token{Kind: token.String, Val: `"tag"`}},
+				{Type: Pointer{Base: name("Z")}},
+			},
+			want: "struct{X, Y int `tag`; *Z}",
+		},
+		{typ: Interface{}, want: "interface{}"},
+		{
+			typ: Interface{
+				{Name: ident("M"), Sig: &Func{Params: []Parameter{{Names: []token.Token{ident("a")}, Type: Int}}, Results: []Parameter{{Type: Error}}}},
+				{Pkg: ident("io"), Name: ident("Writer")},
+			},
+			want: "interface{M(a int) error; io.Writer}",
+		},
+	}
+
+	for i, g := range golden {
+		got := g.typ.(fmt.Stringer).String()
+		if got != g.want {
+			t.Errorf("i=%d: string mismatch; expected %q, got %q.", i, g.want, got)
+		}
+	}
+}
+
+func TestLookupBasic(t *testing.T) {
+	golden := []struct {
+		name string
+		want Basic
+		ok   bool
+	}{
+		{name: "bool", want: Bool, ok: true},
+		{name: "byte", want: Byte, ok: true},
+		{name: "uint8", want: Uint8, ok: true},
+		{name: "rune", want: Rune, ok: true},
+		{name: "int32", want: Int32, ok: true},
+		{name: "complex128", want: Complex128, ok: true},
+		{name: "error", want: Error, ok: true},
+		{name: "uintptr", want: Uintptr, ok: true},
+		{name: "int128", ok: false},
+		{name: "Int", ok: false},
+		{name: "untyped nil", ok: false},
+		{name: "", ok: false},
+	}
+
+	for i, g := range golden {
+		got, ok := LookupBasic(g.name)
+		if ok != g.ok {
+			t.Errorf("i=%d: ok mismatch for %q; expected %v, got %v.", i, g.name, g.ok, ok)
+			continue
+		}
+		if got != g.want {
+			t.Errorf("i=%d: basic type mismatch for %q; expected %v, got %v.", i, g.name, g.want, got)
+		}
+		if ok && got.String() != g.name {
+			t.Errorf("i=%d: name mismatch; expected %q, got %q.", i, g.name, got.String())
+		}
+	}
+}
+
+func TestWriteType(t *testing.T) {
+	ident := func(val string) token.Token {
+		return token.Token{Kind: token.Ident, Val: val}
+	}
+	tag := func(val string) token.Token {
+		return token.Token{Kind: token.String, Val: val}
+	}
+	golden := []struct {
+		typ  Type
+		want string
+	}{
+		{typ: Map{Key: String, Elem: Slice{Elem: Pointer{Base: Name{Pkg: ident("big"), Name: ident("Int")}}}}, want: "map[string][]*big.Int"},
+		{
+			typ: Func{
+				Params:     []Parameter{{Names: []token.Token{ident("format")}, Type: String}, {Names: []token.Token{ident("args")}, Type: Interface{}}},
+				Results:    []Parameter{{Type: Int}, {Type: Error}},
+				IsVariadic: true,
+			},
+			want: "func(format string, args ...interface{}) (int, error)",
+		},
+		{
+			typ: Struct{
+				{Names: []token.Token{ident("Name")}, Type: String, Tag: tag(`"json:\"name\""`)},
+				{Names: []token.Token{ident("Raw")}, Type: String, Tag: tag("`xml:\"raw\"`")},
+				{Names: []token.Token{ident("Quote")}, Type: String, Tag: tag("\"a`b\"")},
+			},
+			want: "struct{Name string `json:\"name\"`; Raw string `xml:\"raw\"`; Quote string \"a`b\"}",
+		},
+		{typ: Chan{Dir: Send | Recv, Elem: Chan{Dir: Recv, Elem: Func{}}}, want: "chan (<-chan func())"},
+	}
+
+	for i, g := range golden {
+		buf := new(bytes.Buffer)
+		if err := WriteType(buf, g.typ); err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+			continue
+		}
+		if got := buf.String(); got != g.want {
+			t.Errorf("i=%d: output mismatch; expected %q, got %q.", i, g.want, got)
+		}
+		if s := g.typ.(fmt.Stringer).String(); s != buf.String() {
+			t.Errorf("i=%d: String mismatch; expected %q, got %q.", i, buf.String(), s)
+		}
+	}
+}
+
+// errWriter is a writer which fails after n bytes have been written.
+type errWriter struct {
+	n int
+}
+
+func (w *errWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n := w.n
+		w.n = 0
+		return n, errors.New("write failed")
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestWriteTypeError(t *testing.T) {
+	typ := Map{Key: String, Elem: Slice{Elem: Int}}
+	if err := WriteType(&errWriter{n: 5}, typ); err == nil {
+		t.Errorf("expected write error, got nil.")
+	}
+}
@@ -0,0 +1,29 @@
+package types
+
+import (
+	"reflect"
+
+	"github.com/mewlang/go/token"
+)
+
+// LookupTag returns the value associated with key in the provided struct field
+// tag, and a boolean indicating whether the key was present. By convention, tag
+// strings are a concatenation of optionally space-separated key:"value" pairs,
+// where each value is quoted using Go string literal syntax; e.g.
+//
+//    `json:"name,omitempty" xml:"name"`
+//
+// If the tag is not a valid string literal, or does not have the conventional
+// format, the boolean is false.
+//
+// ref: http://golang.org/pkg/reflect/#StructTag
+func LookupTag(tag token.Token, key string) (value string, ok bool) {
+	if tag.Kind != token.String {
+		return "", false
+	}
+	s, err := tag.Unquote()
+	if err != nil {
+		return "", false
+	}
+	return reflect.StructTag(s).Lookup(key)
+}
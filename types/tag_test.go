@@ -0,0 +1,46 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/mewlang/go/token"
+)
+
+func TestLookupTag(t *testing.T) {
+	raw := func(val string) token.Token {
+		return token.Token{Kind: token.String, Val: "`" + val + "`"}
+	}
+	golden := []struct {
+		tag   token.Token
+		key   string
+		value string
+		ok    bool
+	}{
+		{tag: raw(`json:"name,omitempty"`), key: "json", value: "name,omitempty", ok: true},
+		{tag: raw(`json:"name,omitempty" xml:"name"`), key: "xml", value: "name", ok: true},
+		{tag: raw(`json:"-"`), key: "json", value: "-", ok: true},
+		{tag: raw(`json:""`), key: "json", value: "", ok: true},
+		{tag: raw(`json:"a\"b\\c\tz"`), key: "json", value: "a\"b\\c\tz", ok: true},
+		{tag: raw(`json:"name"   yaml:"n"`), key: "yaml", value: "n", ok: true},
+		// Interpreted string literal.
+		{tag: token.Token{Kind: token.String, Val: `"json:\"id\""`}, key: "json", value: "id", ok: true},
+		// Missing keys.
+		{tag: raw(`json:"name"`), key: "xml", ok: false},
+		{tag: raw(`json:"name"`), key: "jso", ok: false},
+		{tag: raw(``), key: "json", ok: false},
+		{tag: token.Token{}, key: "json", ok: false},
+		// Unconventional tags.
+		{tag: raw(`json`), key: "json", ok: false},
+		{tag: raw(`json:name`), key: "json", ok: false},
+		{tag: raw(`json:"name`), key: "json", ok: false},
+		// Invalid string literal.
+		{tag: token.Token{Kind: token.String, Val: `"json:\"`}, key: "json", ok: false},
+	}
+
+	for i, g := range golden {
+		value, ok := LookupTag(g.tag, g.key)
+		if ok != g.ok || value != g.value {
+			t.Errorf("i=%d: LookupTag(%s, %q) mismatch; expected %q (%t), got %q (%t).", i, g.tag.Val, g.key, g.value, g.ok, value, ok)
+		}
+	}
+}
@@ -1,7 +1,11 @@
 // Package types declares the data types of the Go programming language.
 package types
 
-import "github.com/mewlang/go/token"
+import (
+	"fmt"
+
+	"github.com/mewlang/go/token"
+)
 
 // A Type determines the set of values and operations specific to values of that
 // type. Types may be named or unnamed. Named types are specified by a (possibly
@@ -52,6 +56,9 @@ const (
 	Uint32
 	Uint64
 	Uintptr
+	// UntypedNil is the type of the predeclared identifier nil, which may be
+	// assigned to pointer, function, slice, map, channel and interface types.
+	UntypedNil
 )
 
 // A Name binds an identifier, the type name, to a new type that has the same
@@ -62,10 +69,14 @@ const (
 //
 // ref: http://golang.org/ref/spec#Type_declarations
 type Name struct {
+	// Package name of a qualified type name, or NONE.
+	Pkg token.Token
 	// Type name.
 	Name token.Token
 	// Underlying type.
 	Type Type
+	// Methods declared with the named type as receiver base type, or nil.
+	Methods []Method
 }
 
 // An Array is a numbered sequence of elements of a single type, called the
@@ -78,7 +89,8 @@ type Name struct {
 //
 // ref: http://golang.org/ref/spec#Array_types
 type Array struct {
-	// Array length; holds an ast.ConstExpr or an ellipsis (token.Token).
+	// Array length; holds a constant expression (ast.Expr) or an ellipsis
+	// (token.Token).
 	Len interface{}
 	// Element type.
 	Elem Type
@@ -175,10 +187,12 @@ type Interface []Method
 // A Method denotes the set of all methods with the same method name, and
 // parameter and result types.
 type Method struct {
+	// Package name of a qualified interface type name, or NONE.
+	Pkg token.Token
 	// Method name (if Sig != nil) or interface type name.
 	Name token.Token
 	// Method signature, or nil.
-	Sig Func
+	Sig *Func
 }
 
 // A Slice is a descriptor for a contiguous segment of an underlying array and
@@ -227,8 +241,36 @@ type ChanDir uint8
 const (
 	Send ChanDir = 1 << iota
 	Recv
+	// Bidirectional channel.
+	BothDir = Send | Recv
 )
 
+// CanSend returns true if values may be sent on channels of direction d, and
+// false otherwise.
+func (d ChanDir) CanSend() bool {
+	return d&Send != 0
+}
+
+// CanRecv returns true if values may be received from channels of direction d,
+// and false otherwise.
+func (d ChanDir) CanRecv() bool {
+	return d&Recv != 0
+}
+
+// String returns the channel type prefix of the channel direction; i.e. "chan",
+// "chan<-" or "<-chan".
+func (d ChanDir) String() string {
+	switch d {
+	case BothDir:
+		return "chan"
+	case Send:
+		return "chan<-"
+	case Recv:
+		return "<-chan"
+	}
+	return fmt.Sprintf("ChanDir(%d)", uint8(d))
+}
+
 // isType ensures that only type nodes can be assigned to the Type interface.
 func (Basic) isType()     {}
 func (Name) isType()      {}
@@ -0,0 +1,29 @@
+package types
+
+import "testing"
+
+func TestChanDir(t *testing.T) {
+	golden := []struct {
+		dir     ChanDir
+		canSend bool
+		canRecv bool
+		want    string
+	}{
+		{dir: BothDir, canSend: true, canRecv: true, want: "chan"},
+		{dir: Send, canSend: true, canRecv: false, want: "chan<-"},
+		{dir: Recv, canSend: false, canRecv: true, want: "<-chan"},
+		{dir: 0, canSend: false, canRecv: false, want: "ChanDir(0)"},
+	}
+
+	for i, g := range golden {
+		if got := g.dir.CanSend(); got != g.canSend {
+			t.Errorf("i=%d: CanSend mismatch for %v; expected %v, got %v.", i, g.dir, g.canSend, got)
+		}
+		if got := g.dir.CanRecv(); got != g.canRecv {
+			t.Errorf("i=%d: CanRecv mismatch for %v; expected %v, got %v.", i, g.dir, g.canRecv, got)
+		}
+		if got := g.dir.String(); got != g.want {
+			t.Errorf("i=%d: string mismatch; expected %q, got %q.", i, g.want, got)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package types
+
+// Underlying returns the underlying type of t. The underlying type of a named
+// type is the underlying type of the type it binds to, which is located by
+// following the chain of type names to the first type which is not a type name.
+// Every other type is its own underlying type.
+//
+// Underlying returns nil if the chain ends in an unresolved type name, i.e. a
+// type name with a nil Type.
+//
+// ref: http://golang.org/ref/spec#Types
+func Underlying(t Type) Type {
+	for {
+		name, ok := t.(Name)
+		if !ok {
+			return t
+		}
+		t = name.Type
+	}
+}
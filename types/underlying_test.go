@@ -0,0 +1,39 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mewlang/go/token"
+)
+
+func TestUnderlying(t *testing.T) {
+	ident := func(val string) token.Token {
+		return token.Token{Kind: token.Ident, Val: val}
+	}
+	// type A []int
+	// type B A
+	// type C B
+	a := Name{Name: ident("A"), Type: Slice{Elem: Int}}
+	b := Name{Name: ident("B"), Type: a}
+	c := Name{Name: ident("C"), Type: b}
+	golden := []struct {
+		typ  Type
+		want Type
+	}{
+		{typ: c, want: Slice{Elem: Int}},
+		{typ: b, want: Slice{Elem: Int}},
+		{typ: a, want: Slice{Elem: Int}},
+		{typ: Slice{Elem: c}, want: Slice{Elem: c}},
+		{typ: Pointer{Base: a}, want: Pointer{Base: a}},
+		{typ: String, want: String},
+		{typ: Name{Name: ident("T")}, want: nil},
+	}
+
+	for i, g := range golden {
+		got := Underlying(g.typ)
+		if !reflect.DeepEqual(got, g.want) {
+			t.Errorf("i=%d: underlying type mismatch; expected %v, got %v.", i, g.want, got)
+		}
+	}
+}